@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	locationHeader          = "Location"
+	operationLocationHeader = "Operation-Location"
+
+	defaultAsyncPollInterval = time.Second
+)
+
+var ErrCrossOriginStatus = errors.New("async status URL is on another origin")
+
+var runningJobStates = map[string]bool{
+	"notstarted": true,
+	"accepted":   true,
+	"queued":     true,
+	"pending":    true,
+	"running":    true,
+	"inprogress": true,
+}
+
+// AsyncPoll configures SendAsync.
+type AsyncPoll struct {
+	// Interval is the delay between polls when a status response carries
+	// no Retry-After, and the least a Retry-After can ask for; one second by
+	// default.
+	Interval time.Duration
+	// Terminal reports whether a status response is final. By default any
+	// response other than 202 is, unless its JSON "status" field names a
+	// running state such as "running" or "InProgress".
+	Terminal func(*Response) bool
+}
+
+// SendAsync sends spec and, when the server answers 202 Accepted with an
+// Operation-Location or Location header, polls that URL with GET until a
+// terminal response, honoring Retry-After between polls. The header is
+// resolved against the URL that answered, and status URLs on another origin
+// fail with ErrCrossOriginStatus. Polls get opts, except those setting a
+// body. The final status response is returned; responses other than 202 are
+// returned as is.
+func (client *Client) SendAsync(
+	ctx context.Context,
+	spec RequestSpec,
+	poll AsyncPoll,
+	opts ...RequestOption,
+) (*Response, error) {
+	response, err := client.Send(ctx, spec, opts...)
+	if err != nil || response.StatusCode != http.StatusAccepted {
+		return response, err
+	}
+
+	if poll.Interval <= 0 {
+		poll.Interval = defaultAsyncPollInterval
+	}
+
+	if poll.Terminal == nil {
+		poll.Terminal = client.jobFinished
+	}
+
+	statusUrl, ok, err := statusLocation(response)
+	if !ok || err != nil {
+		return response, err
+	}
+
+	headers := spec.Headers.Clone()
+	headers.Del(ContentTypeHeader)
+
+	pollOpts := append(append([]RequestOption(nil), opts...), withoutBody, followUrl())
+
+	for {
+		if err = sleepContext(ctx, max(retryAfter(response.Header, poll.Interval), poll.Interval)); err != nil {
+			return response, err
+		}
+
+		response, err = client.Send(ctx, RequestSpec{Method: http.MethodGet, Path: statusUrl, Headers: headers}, pollOpts...)
+		if err != nil || poll.Terminal(response) {
+			return response, err
+		}
+
+		next, ok, err := statusLocation(response)
+		if err != nil {
+			return response, err
+		}
+
+		if ok {
+			statusUrl = next
+		}
+	}
+}
+
+// withoutBody drops the body options of the original request from polls.
+func withoutBody(options *requestOptions) {
+	options.body = nil
+	options.hasBody = false
+	options.bodyMarshaler = nil
+	options.formFields = nil
+	options.contentLength = 0
+}
+
+func (client *Client) jobFinished(response *Response) bool {
+	if response.StatusCode == http.StatusAccepted {
+		return false
+	}
+
+	var body struct {
+		Status string `json:"status"`
+	}
+
+	if client.DecodeJSON(response, &body) != nil {
+		return true
+	}
+
+	state := strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(body.Status))
+
+	return !runningJobStates[state]
+}
+
+func statusLocation(response *Response) (string, bool, error) {
+	location := response.Header.Get(operationLocationHeader)
+	if location == "" {
+		location = response.Header.Get(locationHeader)
+	}
+
+	if location == "" {
+		return "", false, nil
+	}
+
+	statusUrl, ok := resolveSameOrigin(response.URL, location)
+	if !ok {
+		return "", false, ErrCrossOriginStatus
+	}
+
+	return statusUrl, true, nil
+}
+
+func retryAfter(header http.Header, fallback time.Duration) time.Duration {
+	value := header.Get(retryAfterHeader)
+	if value == "" {
+		return fallback
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+
+	return fallback
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSendAsync_PollsOperationLocation(t *testing.T) {
+	var polls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/exports":
+			w.Header().Set(operationLocationHeader, "/operations/7")
+			w.Header().Set(retryAfterHeader, "0")
+			w.WriteHeader(http.StatusAccepted)
+		case "/operations/7":
+			if r.Method != http.MethodGet || r.Header.Get("X-Global") != "G" {
+				t.Errorf("poll %s headers=%v", r.Method, r.Header)
+			}
+			switch atomic.AddInt32(&polls, 1) {
+			case 1:
+				w.Header().Set(retryAfterHeader, "0")
+				w.WriteHeader(http.StatusAccepted)
+			case 2:
+				fmt.Fprint(w, `{"status":"InProgress"}`)
+			default:
+				fmt.Fprint(w, `{"status":"Succeeded"}`)
+			}
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	response, err := c.SendAsync(context.Background(), RequestSpec{Method: http.MethodPost, Path: "/exports"},
+		AsyncPoll{Interval: 1}, JSONBody(map[string]string{"format": "csv"}))
+	if err != nil {
+		t.Fatalf("SendAsync error: %v", err)
+	}
+	if string(response.Body) != `{"status":"Succeeded"}` || atomic.LoadInt32(&polls) != 3 {
+		t.Fatalf("body=%s polls=%d", response.Body, polls)
+	}
+}
+
+func TestSendAsync_ReturnsNonAcceptedAsIs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	response, err := c.SendAsync(context.Background(), RequestSpec{Method: http.MethodPost, Path: "/x"}, AsyncPoll{})
+	if err != nil || response.StatusCode != http.StatusCreated {
+		t.Fatalf("response=%+v err=%v", response, err)
+	}
+}
+
+func TestSendAsync_ResolvesAgainstRequestURLAndPassesOptions(t *testing.T) {
+	var polls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/exports":
+			w.Header().Set(locationHeader, "status/7")
+			w.WriteHeader(http.StatusAccepted)
+		case "/v1/status/7":
+			atomic.AddInt32(&polls, 1)
+			if body, _ := io.ReadAll(r.Body); len(body) > 0 {
+				t.Errorf("poll sent body %s", body)
+			}
+			w.Header().Set(locationHeader, "https://evil.example/status/7")
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			t.Errorf("unexpected %s", r.URL)
+		}
+	}))
+	defer srv.Close()
+
+	var judged int32
+	c := newTestClient(t, srv.URL)
+	_, err := c.SendAsync(context.Background(), RequestSpec{Method: http.MethodPost, Path: "/v1/exports"},
+		AsyncPoll{Interval: 1}, JSONBody(map[string]string{"format": "csv"}), SuccessWhen(func(r *Response) bool {
+			atomic.AddInt32(&judged, 1)
+			return r.StatusCode < http.StatusMultipleChoices
+		}))
+	if !errors.Is(err, ErrCrossOriginStatus) {
+		t.Fatalf("err=%v, want ErrCrossOriginStatus", err)
+	}
+	if atomic.LoadInt32(&polls) != 1 || atomic.LoadInt32(&judged) != 2 {
+		t.Fatalf("polls=%d judged=%d", polls, judged)
+	}
+}
+
+func TestSendAsync_IntervalBoundsRetryAfter(t *testing.T) {
+	var polls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/exports" || atomic.AddInt32(&polls, 1) < 3 {
+			w.Header().Set(operationLocationHeader, "/operations/7")
+			w.Header().Set(retryAfterHeader, "0")
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	defer srv.Close()
+
+	interval := 30 * time.Millisecond
+	c := newTestClient(t, srv.URL)
+
+	started := time.Now()
+	if _, err := c.SendAsync(context.Background(), RequestSpec{Method: http.MethodPost, Path: "/exports"},
+		AsyncPoll{Interval: interval}); err != nil {
+		t.Fatalf("SendAsync error: %v", err)
+	}
+	if elapsed := time.Since(started); atomic.LoadInt32(&polls) != 3 || elapsed < 3*interval {
+		t.Fatalf("polls=%d elapsed=%v", polls, elapsed)
+	}
+}
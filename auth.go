@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Token is a credential with an optional expiry; a zero Expiry never
+// expires.
+type Token struct {
+	Value  string
+	Expiry time.Time
+}
+
+func (token Token) expired(now time.Time) bool {
+	return !token.Expiry.IsZero() && !now.Before(token.Expiry)
+}
+
+// TokenSource supplies the credential used by the auth options.
+type TokenSource interface {
+	Token(ctx context.Context) (Token, error)
+}
+
+type TokenSourceFunc func(ctx context.Context) (Token, error)
+
+func (fn TokenSourceFunc) Token(ctx context.Context) (Token, error) {
+	return fn(ctx)
+}
+
+type staticToken string
+
+func (token staticToken) Token(context.Context) (Token, error) {
+	return Token{Value: string(token)}, nil
+}
+
+func (staticToken) String() string { return "static" }
+
+// StaticToken is a TokenSource that always returns value.
+func StaticToken(value string) TokenSource {
+	return staticToken(value)
+}
+
+type authScheme struct {
+	header string
+	prefix string
+	source TokenSource
+}
+
+// WithBearerToken sends "Authorization: Bearer <token>" with every request
+// that does not set Authorization itself.
+func WithBearerToken(source TokenSource) Option {
+	return func(client *Client) error {
+		client.auth = &authScheme{header: AuthorizationHeader, prefix: "Bearer ", source: source}
+
+		return nil
+	}
+}
+
+// WithAPIKey sends the token from source in header, such as "X-API-Key",
+// with every request that does not set it itself.
+func WithAPIKey(header string, source TokenSource) Option {
+	return func(client *Client) error {
+		client.auth = &authScheme{header: header, source: source}
+
+		return nil
+	}
+}
+
+func (client *Client) authorize(ctx context.Context, request *http.Request) error {
+	if client.auth == nil || request.Header.Get(client.auth.header) != "" {
+		return nil
+	}
+
+	token, err := client.auth.source.Token(ctx)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set(client.auth.header, client.auth.prefix+token.Value)
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithBearerToken_SetsAuthorization(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(AuthorizationHeader)
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithBearerToken(StaticToken("abc")))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if got != "Bearer abc" {
+		t.Fatalf("authorization=%q", got)
+	}
+
+	_, err = c.Send(context.Background(), RequestSpec{
+		Method:  http.MethodGet,
+		Path:    "/",
+		Headers: MultiHeaders{AuthorizationHeader: {"Basic xyz"}},
+	})
+	if err != nil || got != "Basic xyz" {
+		t.Fatalf("explicit header overridden: %q %v", got, err)
+	}
+}
+
+func TestWithAPIKey_SourceErrorFailsRequest(t *testing.T) {
+	errVault := errors.New("vault down")
+	log := zerolog.Nop()
+	c, err := New("http://127.0.0.1:1", nil, &log, false, "ua",
+		WithAPIKey("X-API-Key", TokenSourceFunc(func(context.Context) (Token, error) { return Token{}, errVault })))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); !errors.Is(err, errVault) {
+		t.Fatalf("expected source error, got %v", err)
+	}
+}
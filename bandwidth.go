@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const maxThrottledRead = 32 * 1024
+
+// WithBandwidthLimit caps request and response body throughput at
+// bytesPerSec each way, shared by all requests of the client.
+func WithBandwidthLimit(bytesPerSec int) Option {
+	return func(client *Client) error {
+		if bytesPerSec <= 0 {
+			return errors.New("bandwidth limit must be positive")
+		}
+
+		client.uploadLimit = newBandwidthLimiter(bytesPerSec)
+		client.downloadLimit = newBandwidthLimiter(bytesPerSec)
+
+		return nil
+	}
+}
+
+type bandwidthLimiter struct {
+	mu      sync.Mutex
+	rate    float64
+	chunk   int
+	balance float64
+	last    time.Time
+}
+
+func newBandwidthLimiter(bytesPerSec int) *bandwidthLimiter {
+	chunk := bytesPerSec
+	if chunk > maxThrottledRead {
+		chunk = maxThrottledRead
+	}
+
+	return &bandwidthLimiter{rate: float64(bytesPerSec), chunk: chunk, last: time.Now()}
+}
+
+// consume books n bytes and waits until the running balance is paid off.
+// Idle time refills at most one second worth of bytes.
+func (limiter *bandwidthLimiter) consume(ctx context.Context, n int) error {
+	limiter.mu.Lock()
+
+	now := time.Now()
+	limiter.balance += now.Sub(limiter.last).Seconds() * limiter.rate
+	limiter.last = now
+
+	if limiter.balance > limiter.rate {
+		limiter.balance = limiter.rate
+	}
+
+	limiter.balance -= float64(n)
+	debt := limiter.balance
+
+	limiter.mu.Unlock()
+
+	if debt >= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(time.Duration(-debt / limiter.rate * float64(time.Second)))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type throttledBody struct {
+	io.ReadCloser
+	ctx     context.Context
+	limiter *bandwidthLimiter
+}
+
+func (body *throttledBody) Read(p []byte) (int, error) {
+	if len(p) > body.limiter.chunk {
+		p = p[:body.limiter.chunk]
+	}
+
+	n, err := body.ReadCloser.Read(p)
+
+	if n > 0 {
+		if waitErr := body.limiter.consume(body.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+
+	return n, err
+}
+
+func (client *Client) throttleRequest(request *http.Request) {
+	if client.uploadLimit == nil || request.Body == nil || request.Body == http.NoBody {
+		return
+	}
+
+	limiter := client.uploadLimit
+	ctx := request.Context()
+
+	request.Body = &throttledBody{ReadCloser: request.Body, ctx: ctx, limiter: limiter}
+
+	if getBody := request.GetBody; getBody != nil {
+		request.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+			if err != nil {
+				return nil, err
+			}
+
+			return &throttledBody{ReadCloser: body, ctx: ctx, limiter: limiter}, nil
+		}
+	}
+}
+
+func (client *Client) throttleResponse(ctx context.Context, response *http.Response) {
+	if client.downloadLimit == nil {
+		return
+	}
+
+	response.Body = &throttledBody{ReadCloser: response.Body, ctx: ctx, limiter: client.downloadLimit}
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithBandwidthLimit_ThrottlesBodies(t *testing.T) {
+	const rate = 4000
+	payload := bytes.Repeat([]byte("x"), rate)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithBandwidthLimit(rate))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	started := time.Now()
+	resp, err := c.Send(context.Background(), RequestSpec{
+		Method: http.MethodPost,
+		Path:   "/echo",
+		Body:   bytes.NewReader(payload),
+	})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if !bytes.Equal(resp.Body, payload) {
+		t.Fatalf("body length %d", len(resp.Body))
+	}
+
+	// The limiter starts empty, so each direction needs about a second.
+	if elapsed := time.Since(started); elapsed < 900*time.Millisecond {
+		t.Fatalf("request finished in %v, expected throttling", elapsed)
+	}
+
+	if _, err = New(srv.URL, nil, &log, false, "ua", WithBandwidthLimit(0)); err == nil {
+		t.Fatal("expected error for zero limit")
+	}
+}
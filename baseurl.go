@@ -0,0 +1,122 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var ErrInvalidBaseURL = errors.New("invalid base url")
+
+type BaseURLError struct {
+	URL    string
+	Reason string
+}
+
+func (e *BaseURLError) Error() string {
+	return fmt.Sprintf("%s %q: %s", ErrInvalidBaseURL, e.URL, e.Reason)
+}
+
+func (e *BaseURLError) Is(target error) bool {
+	return target == ErrInvalidBaseURL
+}
+
+// WithAllowedSchemes accepts base URL schemes besides http and https, such
+// as unix or h2c, for transports that understand them. Such URLs are not
+// required to have a host.
+func WithAllowedSchemes(schemes ...string) Option {
+	return func(client *Client) error {
+		if client.allowedSchemes == nil {
+			client.allowedSchemes = map[string]bool{}
+		}
+
+		for _, scheme := range schemes {
+			client.allowedSchemes[scheme] = true
+		}
+
+		return nil
+	}
+}
+
+// WithBaseURLCredentials turns userinfo in the base URL into a Basic
+// Authorization header instead of rejecting the URL.
+func WithBaseURLCredentials() Option {
+	return func(client *Client) error {
+		client.baseUrlCredentials = true
+
+		return nil
+	}
+}
+
+// normalizeBaseUrl validates rawUrl, strips a fragment with a warning and
+// moves userinfo into the Authorization header when allowed.
+func (client *Client) normalizeBaseUrl(rawUrl string) (string, error) {
+	if err := client.validateBaseUrl(rawUrl); err != nil {
+		return "", err
+	}
+
+	u, _ := url.Parse(rawUrl)
+
+	changed, err := asciiHost(u)
+	if err != nil {
+		return "", &BaseURLError{URL: u.Redacted(), Reason: "invalid internationalized host: " + err.Error()}
+	}
+
+	if u.Fragment != "" || strings.Contains(rawUrl, "#") {
+		client.logger.Warn().
+			Str(client.logField("url"), u.Redacted()).
+			Str("fragment", u.Fragment).
+			Msg("stripping fragment from base url")
+
+		u.Fragment, u.RawFragment = "", ""
+		changed = true
+	}
+
+	if u.User != nil {
+		if !client.baseUrlCredentials {
+			return "", &BaseURLError{URL: u.Redacted(), Reason: "userinfo is not allowed, use WithBaseURLCredentials"}
+		}
+
+		password, _ := u.User.Password()
+		auth := "Basic " + PrepareBasicAuth(u.User.Username(), password)
+
+		if existing, ok := client.Headers[AuthorizationHeader]; ok && existing != auth {
+			return "", &BaseURLError{URL: u.Redacted(), Reason: "conflicting credentials"}
+		}
+
+		client.Headers[AuthorizationHeader] = auth
+		u.User = nil
+		changed = true
+	}
+
+	if !changed {
+		return rawUrl, nil
+	}
+
+	return u.String(), nil
+}
+
+func (client *Client) validateBaseUrl(rawUrl string) error {
+	if rawUrl == "" {
+		return &BaseURLError{URL: rawUrl, Reason: "empty"}
+	}
+
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return &BaseURLError{URL: rawUrl, Reason: err.Error()}
+	}
+
+	switch {
+	case u.Scheme == "":
+		return &BaseURLError{URL: u.Redacted(), Reason: "missing scheme"}
+	case client.allowedSchemes[u.Scheme]:
+		return nil
+	case u.Scheme != "http" && u.Scheme != "https" && u.Scheme != srvScheme && u.Scheme != srvHTTPSScheme:
+		return &BaseURLError{URL: u.Redacted(), Reason: fmt.Sprintf("unsupported scheme %q", u.Scheme)}
+	case u.Host == "":
+		return &BaseURLError{URL: u.Redacted(), Reason: "missing host"}
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package client
+
+import "io"
+
+// BodyMode switches between the two ways a response body can be consumed:
+// Buffered reads it into Response.Body, Streamed copies it to a writer
+// through Sink without holding it in memory. Comparing both on real
+// payloads with clienttest.BenchmarkPayload or clienttest.BenchmarkRequest
+// shows whether the streaming APIs pay off for a given service.
+type BodyMode int
+
+const (
+	Buffered BodyMode = iota
+	Streamed
+)
+
+func (mode BodyMode) String() string {
+	if mode == Streamed {
+		return "streamed"
+	}
+
+	return "buffered"
+}
+
+// Option returns the request option selecting mode; streamed bodies go to w.
+func (mode BodyMode) Option(w io.Writer) RequestOption {
+	if mode == Streamed {
+		return Sink(w)
+	}
+
+	return func(*requestOptions) {}
+}
@@ -0,0 +1,175 @@
+package client
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const omitEmpty = "omitempty"
+
+type binding struct {
+	path    map[string]string
+	query   MultiParams
+	headers MultiHeaders
+	body    any
+	hasBody bool
+	// tagged is set when any field carries a query, header or body tag.
+	tagged bool
+}
+
+// BindRequest builds a request from the tagged fields of v:
+//
+//	type ListOrders struct {
+//		Shop   string `path:"shop"`
+//		Page   int    `query:"page,omitempty"`
+//		Tenant string `header:"X-Tenant"`
+//		Filter Filter `body:""`
+//	}
+//
+// Path placeholders such as "/shops/{shop}/orders" are filled from path
+// fields; a nil path field leaves its placeholder unbound, which fails with
+// ErrUnboundPathParam. Slices add one value per element, nil pointers are
+// skipped and omitempty skips zero values. The body field is encoded with
+// the client's body marshaler.
+func BindRequest(method, path string, v any) (RequestSpec, error) {
+	return bind(v).spec(method, path)
+}
+
+func (binding *binding) spec(method, path string) (RequestSpec, error) {
+	path, err := expandPath(path, binding.path)
+	if err != nil {
+		return RequestSpec{}, err
+	}
+
+	spec := RequestSpec{Method: method, Path: path}
+
+	if len(binding.query) > 0 {
+		spec.Params = binding.query
+	}
+
+	if len(binding.headers) > 0 {
+		spec.Headers = binding.headers
+	}
+
+	if binding.hasBody {
+		spec.Options = []RequestOption{Body(binding.body)}
+	}
+
+	return spec, nil
+}
+
+func bind(v any) *binding {
+	binding := &binding{path: map[string]string{}, query: MultiParams{}, headers: MultiHeaders{}}
+
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return binding
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldValue := value.Field(i)
+
+		if _, ok := field.Tag.Lookup("body"); ok {
+			binding.tagged = true
+			binding.body = fieldValue.Interface()
+			binding.hasBody = true
+
+			continue
+		}
+
+		if name, ok := field.Tag.Lookup("path"); ok && !isNilPointer(fieldValue) {
+			binding.path[name] = formatBound(fieldValue)
+		}
+
+		if tag, ok := field.Tag.Lookup("query"); ok {
+			binding.tagged = true
+			name, omit := parseBindTag(tag)
+
+			for _, item := range boundValues(fieldValue, omit) {
+				binding.query.Add(name, item)
+			}
+		}
+
+		if tag, ok := field.Tag.Lookup("header"); ok {
+			binding.tagged = true
+			name, omit := parseBindTag(tag)
+
+			for _, item := range boundValues(fieldValue, omit) {
+				binding.headers.Add(name, item)
+			}
+		}
+	}
+
+	return binding
+}
+
+func parseBindTag(tag string) (string, bool) {
+	name, option, _ := strings.Cut(tag, ",")
+
+	return name, option == omitEmpty
+}
+
+func boundValues(value reflect.Value, omit bool) []string {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		if _, ok := value.Interface().(encoding.TextMarshaler); !ok && value.Type().Elem().Kind() != reflect.Uint8 {
+			values := make([]string, 0, value.Len())
+
+			for i := 0; i < value.Len(); i++ {
+				values = append(values, formatBound(value.Index(i)))
+			}
+
+			return values
+		}
+	}
+
+	if omit && value.IsZero() {
+		return nil
+	}
+
+	return []string{formatBound(value)}
+}
+
+func isNilPointer(value reflect.Value) bool {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return true
+		}
+
+		value = value.Elem()
+	}
+
+	return false
+}
+
+func formatBound(value reflect.Value) string {
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+		if text, err := marshaler.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+
+	return fmt.Sprint(value.Interface())
+}
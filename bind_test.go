@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type orderFilter struct {
+	Status string `json:"status"`
+}
+
+type listOrders struct {
+	Shop   string      `path:"shop"`
+	Page   int         `query:"page,omitempty"`
+	Tags   []string    `query:"tag"`
+	Since  *time.Time  `query:"since"`
+	Tenant string      `header:"X-Tenant"`
+	Filter orderFilter `body:""`
+}
+
+func TestBindRequest_MapsTaggedFields(t *testing.T) {
+	spec, err := BindRequest(http.MethodPost, "/shops/{shop}/orders", listOrders{
+		Shop:   "main store",
+		Tags:   []string{"a", "b"},
+		Tenant: "acme",
+		Filter: orderFilter{Status: "open"},
+	})
+	if err != nil {
+		t.Fatalf("BindRequest error: %v", err)
+	}
+	if spec.Path != "/shops/main%20store/orders" {
+		t.Fatalf("path=%s", spec.Path)
+	}
+	if spec.Params.Has("page") || spec.Params.Has("since") || len(spec.Params["tag"]) != 2 {
+		t.Fatalf("params=%v", spec.Params)
+	}
+	if spec.Headers.Get("X-Tenant") != "acme" || len(spec.Options) != 1 {
+		t.Fatalf("headers=%v options=%d", spec.Headers, len(spec.Options))
+	}
+}
+
+func TestBindRequest_NilPathField(t *testing.T) {
+	type getOrder struct {
+		ID *int `path:"id"`
+	}
+
+	if _, err := BindRequest(http.MethodGet, "/orders/{id}", getOrder{}); !errors.Is(err, ErrUnboundPathParam) {
+		t.Fatalf("expected ErrUnboundPathParam, got %v", err)
+	}
+}
+
+func TestBindRequest_SendsPathEscapedOnce(t *testing.T) {
+	var gotPath, gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.EscapedPath(), r.URL.RawQuery
+	}))
+	defer srv.Close()
+
+	spec, err := BindRequest(http.MethodGet, "/shops/{shop}/orders", listOrders{Shop: "main store", Page: 2})
+	if err != nil {
+		t.Fatalf("BindRequest error: %v", err)
+	}
+
+	c := newTestClient(t, srv.URL)
+	if _, err = c.Send(context.Background(), spec); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if gotPath != "/shops/main%20store/orders" || gotQuery != "page=2" {
+		t.Fatalf("path=%s query=%s", gotPath, gotQuery)
+	}
+}
+
+func TestEndpoint_UsesTaggedBody(t *testing.T) {
+	var gotQuery, gotTenant, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		gotTenant = r.Header.Get("X-Tenant")
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	endpoint := NewEndpoint[listOrders, struct{}](c, http.MethodPost, "/shops/{shop}/orders/search")
+	_, _, err := endpoint.Call(context.Background(), listOrders{Shop: "s1", Page: 2, Tenant: "acme", Filter: orderFilter{Status: "open"}})
+	if err != nil {
+		t.Fatalf("Call error: %v", err)
+	}
+	if gotQuery != "page=2" || gotTenant != "acme" || gotBody != `{"status":"open"}` {
+		t.Fatalf("query=%s tenant=%s body=%s", gotQuery, gotTenant, gotBody)
+	}
+}
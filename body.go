@@ -0,0 +1,177 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type BodyMarshaler interface {
+	ContentType() string
+	Marshal(v any) ([]byte, error)
+}
+
+// JSONMarshaler uses the client's JSONEngine when Engine is nil.
+type JSONMarshaler struct {
+	Engine JSONEngine
+}
+
+func (JSONMarshaler) ContentType() string { return ContentTypeJson }
+
+func (marshaler JSONMarshaler) Marshal(v any) ([]byte, error) {
+	if marshaler.Engine == nil {
+		return json.Marshal(v)
+	}
+
+	return marshaler.Engine.Marshal(v)
+}
+
+type XMLMarshaler struct{}
+
+func (XMLMarshaler) ContentType() string { return ContentTypeXml }
+
+func (XMLMarshaler) Marshal(v any) ([]byte, error) { return xml.Marshal(v) }
+
+// FormMarshaler encodes url.Values, MultiParams, Params and
+// map[string]string values as an urlencoded form.
+type FormMarshaler struct{}
+
+func (FormMarshaler) ContentType() string { return ContentTypeForm }
+
+func (FormMarshaler) Marshal(v any) ([]byte, error) {
+	switch values := v.(type) {
+	case url.Values:
+		return []byte(values.Encode()), nil
+	case MultiParams:
+		return []byte(values.Values().Encode()), nil
+	case map[string][]string:
+		return []byte(url.Values(values).Encode()), nil
+	case Params:
+		return []byte(values.Multi().Values().Encode()), nil
+	case map[string]string:
+		return []byte(Params(values).Multi().Values().Encode()), nil
+	default:
+		return nil, fmt.Errorf("form body: unsupported type %T", v)
+	}
+}
+
+// WithBodyMarshaler sets the marshaler used by the Body request option;
+// JSON is used when none is configured.
+func WithBodyMarshaler(marshaler BodyMarshaler) Option {
+	return func(client *Client) error {
+		client.bodyMarshaler = marshaler
+
+		return nil
+	}
+}
+
+func Body(v any) RequestOption {
+	return func(options *requestOptions) {
+		options.body = v
+		options.hasBody = true
+		options.bodyMarshaler = nil
+	}
+}
+
+func JSONBody(v any) RequestOption {
+	return BodyWith(v, JSONMarshaler{})
+}
+
+func XMLBody(v any) RequestOption {
+	return BodyWith(v, XMLMarshaler{})
+}
+
+func FormBody(v any) RequestOption {
+	return BodyWith(v, FormMarshaler{})
+}
+
+// FormField adds a field to urlencoded request bodies, whether set with
+// FormBody or as a RequestSpec.Body with a form Content-Type. Other bodies
+// are left untouched.
+func FormField(name, value string) RequestOption {
+	return func(options *requestOptions) {
+		if options.formFields == nil {
+			options.formFields = url.Values{}
+		}
+
+		options.formFields.Add(name, value)
+	}
+}
+
+// BodyWith encodes v with marshaler, whatever WithBodyMarshaler configured.
+func BodyWith(v any, marshaler BodyMarshaler) RequestOption {
+	return func(options *requestOptions) {
+		options.body = v
+		options.hasBody = true
+		options.bodyMarshaler = marshaler
+	}
+}
+
+func (client *Client) encodeBody(spec *RequestSpec, options *requestOptions) error {
+	if !options.hasBody {
+		return appendFormFields(spec, options.formFields)
+	}
+
+	marshaler := options.bodyMarshaler
+	if marshaler == nil {
+		marshaler = client.bodyMarshaler
+	}
+
+	if marshaler == nil {
+		marshaler = JSONMarshaler{}
+	}
+
+	if jsonMarshaler, ok := marshaler.(JSONMarshaler); ok && jsonMarshaler.Engine == nil {
+		marshaler = JSONMarshaler{Engine: client.json()}
+	}
+
+	data, err := marshaler.Marshal(options.body)
+	if err != nil {
+		return err
+	}
+
+	if marshaler.ContentType() == ContentTypeForm {
+		data = appendFormData(data, options.formFields)
+	}
+
+	spec.Body = bytes.NewReader(data)
+
+	spec.Headers = spec.Headers.Clone()
+	if spec.Headers == nil {
+		spec.Headers = MultiHeaders{}
+	}
+
+	spec.Headers.Set(ContentTypeHeader, marshaler.ContentType())
+
+	return nil
+}
+
+func appendFormFields(spec *RequestSpec, fields url.Values) error {
+	if len(fields) == 0 || spec.Body == nil || !strings.HasPrefix(spec.Headers.Get(ContentTypeHeader), ContentTypeForm) {
+		return nil
+	}
+
+	data, err := readSpecBody(spec)
+	if err != nil {
+		return err
+	}
+
+	spec.Body = bytes.NewReader(appendFormData(data, fields))
+
+	return nil
+}
+
+func appendFormData(data []byte, fields url.Values) []byte {
+	if len(fields) == 0 {
+		return data
+	}
+
+	if len(data) > 0 {
+		data = append(data, '&')
+	}
+
+	return append(data, fields.Encode()...)
+}
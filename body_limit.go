@@ -0,0 +1,69 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+var ErrRequestTooLarge = errors.New("request body exceeds limit")
+
+// WithMaxRequestBytes rejects request bodies larger than limit. Bodies of
+// known length are refused before sending, others are aborted as soon as
+// the limit is crossed while streaming.
+func WithMaxRequestBytes(limit int64) Option {
+	return func(client *Client) error {
+		if limit <= 0 {
+			return errors.New("request body limit must be positive")
+		}
+
+		client.maxRequestBytes = limit
+
+		return nil
+	}
+}
+
+func (client *Client) limitRequestBody(request *http.Request) error {
+	limit := client.maxRequestBytes
+
+	if limit <= 0 || request.Body == nil || request.Body == http.NoBody {
+		return nil
+	}
+
+	if request.ContentLength > limit {
+		return fmt.Errorf("%w: %d bytes, limit %d", ErrRequestTooLarge, request.ContentLength, limit)
+	}
+
+	request.Body = &limitedBody{ReadCloser: request.Body, remaining: limit, limit: limit}
+
+	if getBody := request.GetBody; getBody != nil {
+		request.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+			if err != nil {
+				return nil, err
+			}
+
+			return &limitedBody{ReadCloser: body, remaining: limit, limit: limit}, nil
+		}
+	}
+
+	return nil
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	limit     int64
+}
+
+func (body *limitedBody) Read(p []byte) (int, error) {
+	n, err := body.ReadCloser.Read(p)
+	body.remaining -= int64(n)
+
+	if body.remaining < 0 {
+		return 0, fmt.Errorf("%w: limit %d", ErrRequestTooLarge, body.limit)
+	}
+
+	return n, err
+}
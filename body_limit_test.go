@@ -0,0 +1,47 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithMaxRequestBytes(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		_, _ = io.Copy(io.Discard, r.Body)
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithMaxRequestBytes(10))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	big := bytes.Repeat([]byte("x"), 11)
+
+	_, err = c.Send(context.Background(), RequestSpec{Method: http.MethodPost, Path: "/", Body: bytes.NewReader(big)})
+	if !errors.Is(err, ErrRequestTooLarge) || atomic.LoadInt32(&hits) != 0 {
+		t.Fatalf("known length: err=%v hits=%d", err, hits)
+	}
+
+	streamed := io.MultiReader(bytes.NewReader(big[:6]), bytes.NewReader(big[6:]))
+	_, err = c.Send(context.Background(), RequestSpec{Method: http.MethodPost, Path: "/", Body: streamed})
+	if !errors.Is(err, ErrRequestTooLarge) {
+		t.Fatalf("streamed: expected ErrRequestTooLarge, got %v", err)
+	}
+
+	_, err = c.Send(context.Background(), RequestSpec{Method: http.MethodPost, Path: "/", Body: bytes.NewReader(big[:10])})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+type testOrder struct {
+	XMLName xml.Name `xml:"order" json:"-"`
+	ID      int      `xml:"id" json:"id"`
+}
+
+func TestBodyOptions_SetContentTypeAndPayload(t *testing.T) {
+	var gotType, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotType = r.Header.Get(ContentTypeHeader)
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	spec := RequestSpec{Method: http.MethodPost, Path: "/orders", Headers: MultiHeaders{"X-Keep": {"1"}}}
+
+	cases := []struct {
+		opt      RequestOption
+		wantType string
+		wantBody string
+	}{
+		{JSONBody(testOrder{ID: 7}), ContentTypeJson, `{"id":7}`},
+		{XMLBody(testOrder{ID: 7}), ContentTypeXml, `<order><id>7</id></order>`},
+		{FormBody(map[string]string{"id": "7"}), ContentTypeForm, `id=7`},
+		{Body(testOrder{ID: 7}), ContentTypeJson, `{"id":7}`},
+	}
+
+	for _, tc := range cases {
+		if _, err := c.Send(context.Background(), spec, tc.opt); err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+		if gotType != tc.wantType || gotBody != tc.wantBody {
+			t.Errorf("type=%s body=%s, want %s %s", gotType, gotBody, tc.wantType, tc.wantBody)
+		}
+	}
+
+	if len(spec.Headers) != 1 {
+		t.Fatalf("caller headers were modified: %v", spec.Headers)
+	}
+}
+
+func TestWithBodyMarshaler_DefaultForBody(t *testing.T) {
+	var gotType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotType = r.Header.Get(ContentTypeHeader)
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithBodyMarshaler(XMLMarshaler{}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodPut, Path: "/o"}, Body(testOrder{ID: 1})); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if gotType != ContentTypeXml {
+		t.Fatalf("content-type=%s", gotType)
+	}
+
+	if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodPut, Path: "/o"}, FormBody(42)); err == nil {
+		t.Fatal("expected error for unsupported form body")
+	}
+}
+
+func TestRequestSpecOptions_AppliedBeforeSendOptions(t *testing.T) {
+	var gotType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotType = r.Header.Get(ContentTypeHeader)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	spec := RequestSpec{Method: http.MethodPost, Path: "/", Options: []RequestOption{JSONBody(1)}}
+
+	if _, err := c.Send(context.Background(), spec); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if gotType != ContentTypeJson {
+		t.Fatalf("content-type=%s", gotType)
+	}
+
+	if _, err := c.Send(context.Background(), spec, XMLBody(testOrder{ID: 1})); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if gotType != ContentTypeXml {
+		t.Fatalf("Send options should override spec options, content-type=%s", gotType)
+	}
+}
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+var ErrBodyReadIdle = errors.New("no response body bytes received within the read timeout")
+
+// errHeaderDeadline marks the total timeout firing before response headers
+// when WithBodyReadTimeout took it over from http.Client.
+type errHeaderDeadline struct {
+	err error
+}
+
+func (e *errHeaderDeadline) Error() string {
+	return "Client.Timeout exceeded while awaiting headers: " + e.err.Error()
+}
+
+func (e *errHeaderDeadline) Unwrap() error {
+	return e.err
+}
+
+func (e *errHeaderDeadline) Timeout() bool {
+	return true
+}
+
+// WithBodyReadTimeout aborts reading a response body once no bytes arrived
+// for idle. The total timeout from New then only covers waiting for the
+// response headers, so long downloads run as long as data keeps flowing.
+func WithBodyReadTimeout(idle time.Duration) Option {
+	return func(client *Client) error {
+		if idle <= 0 {
+			return errors.New("body read timeout must be positive")
+		}
+
+		client.bodyReadTimeout = idle
+
+		return nil
+	}
+}
+
+// getIdleResponse limits only the wait for response headers with the client
+// timeout. The body is limited by the body read timeout, if any, so streams
+// can stay open as long as they deliver.
+func (client *Client) getIdleResponse(request *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(request.Context())
+
+	httpClient := client.httpClient
+	httpClient.Timeout = 0
+
+	var (
+		headerTimer *time.Timer
+		expired     atomic.Bool
+	)
+
+	if timeout := client.httpClient.Timeout; timeout > 0 {
+		headerTimer = time.AfterFunc(timeout, func() {
+			expired.Store(true)
+			cancel()
+		})
+	}
+
+	response, err := httpClient.Do(request.WithContext(ctx))
+
+	if headerTimer != nil {
+		headerTimer.Stop()
+	}
+
+	if err == nil && expired.Load() {
+		_ = response.Body.Close()
+		err = context.Canceled
+	}
+
+	if err != nil {
+		cancel()
+
+		if expired.Load() {
+			return nil, &errHeaderDeadline{err: err}
+		}
+
+		return nil, err
+	}
+
+	body := &idleBody{ReadCloser: response.Body, idle: client.bodyReadTimeout, cancel: cancel}
+	if body.idle > 0 {
+		body.timer = time.AfterFunc(body.idle, func() {
+			body.expired.Store(true)
+			cancel()
+		})
+	}
+	response.Body = body
+
+	return response, nil
+}
+
+type idleBody struct {
+	io.ReadCloser
+	idle    time.Duration
+	timer   *time.Timer
+	expired atomic.Bool
+	cancel  context.CancelFunc
+}
+
+func (body *idleBody) Read(p []byte) (int, error) {
+	n, err := body.ReadCloser.Read(p)
+
+	if body.expired.Load() {
+		return n, &TimeoutError{Kind: TimeoutBodyRead, Phase: "body", Err: ErrBodyReadIdle}
+	}
+
+	if n > 0 && body.timer != nil {
+		body.timer.Reset(body.idle)
+	}
+
+	return n, err
+}
+
+func (body *idleBody) Close() error {
+	if body.timer != nil {
+		body.timer.Stop()
+	}
+
+	err := body.ReadCloser.Close()
+	body.cancel()
+
+	return err
+}
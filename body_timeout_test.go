@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func newBodyTimeoutClient(t *testing.T, url string, idle time.Duration) *Client {
+	t.Helper()
+	log := zerolog.Nop()
+	c, err := New(url, nil, &log, false, "ua", WithBodyReadTimeout(idle))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	c.httpClient.Timeout = 100 * time.Millisecond
+	return c
+}
+
+func TestBodyReadTimeout_SlowStreamOutlivesTotalTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 10; i++ {
+			_, _ = w.Write([]byte("x"))
+			w.(http.Flusher).Flush()
+			time.Sleep(30 * time.Millisecond)
+		}
+	}))
+	defer srv.Close()
+
+	c := newBodyTimeoutClient(t, srv.URL, 150*time.Millisecond)
+
+	resp, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if string(resp.Body) != "xxxxxxxxxx" {
+		t.Fatalf("body=%q", resp.Body)
+	}
+}
+
+func TestBodyReadTimeout_HungStream(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("x"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	c := newBodyTimeoutClient(t, srv.URL, 50*time.Millisecond)
+
+	_, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"})
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Kind != TimeoutBodyRead || !errors.Is(err, ErrBodyReadIdle) {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestBodyReadTimeout_TotalTimeoutStillCoversHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	c := newBodyTimeoutClient(t, srv.URL, time.Second)
+
+	_, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"})
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Kind != TimeoutClient || timeoutErr.Phase != "headers" {
+		t.Fatalf("err=%v", err)
+	}
+}
@@ -0,0 +1,307 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerFailures = 5
+	defaultBreakerWindow   = 20
+	defaultBreakerOpenFor  = 30 * time.Second
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	// CircuitHalfOpen lets a single probe through; its outcome closes or
+	// reopens the circuit.
+	CircuitHalfOpen
+)
+
+func (state CircuitState) String() string {
+	switch state {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker trips after ConsecutiveFailures failed requests in a row, or
+// once FailureRate of the last Window requests failed, and rejects requests
+// with ErrCircuitOpen for OpenFor. Transport errors and 5xx answers count as
+// failures; requests canceled by the caller are not counted. A request that
+// WithRetry retried counts once.
+type CircuitBreaker struct {
+	ConsecutiveFailures int
+	// FailureRate is between 0 and 1; zero disables the rate check.
+	FailureRate float64
+	Window      int
+	OpenFor     time.Duration
+}
+
+type CircuitMetrics struct {
+	State               CircuitState
+	Requests            uint64
+	Failures            uint64
+	Rejected            uint64
+	ConsecutiveFailures int
+	// FailureRate covers the current window.
+	FailureRate float64
+	OpenedAt    time.Time
+}
+
+// Circuit is the breaker installed by WithCircuitBreaker.
+type Circuit struct {
+	config CircuitBreaker
+
+	mu          sync.Mutex
+	state       CircuitState
+	generation  uint64
+	openedAt    time.Time
+	probing     bool
+	outcomes    []bool
+	next        int
+	filled      int
+	consecutive int
+	requests    uint64
+	failures    uint64
+	rejected    uint64
+}
+
+// circuitTicket is what allow admitted a request with. Only the probe may
+// change a half-open circuit, and outcomes of requests admitted before the
+// last state change are dropped.
+type circuitTicket struct {
+	generation uint64
+	probe      bool
+}
+
+func WithCircuitBreaker(config CircuitBreaker) Option {
+	return func(client *Client) error {
+		if config.FailureRate < 0 || config.FailureRate > 1 {
+			return errors.New("circuit breaker failure rate must be between 0 and 1")
+		}
+
+		if config.ConsecutiveFailures <= 0 {
+			config.ConsecutiveFailures = defaultBreakerFailures
+		}
+
+		if config.Window <= 0 {
+			config.Window = defaultBreakerWindow
+		}
+
+		if config.OpenFor <= 0 {
+			config.OpenFor = defaultBreakerOpenFor
+		}
+
+		client.circuit = &Circuit{config: config, outcomes: make([]bool, config.Window)}
+
+		return nil
+	}
+}
+
+// Circuit returns the client's breaker, nil without WithCircuitBreaker.
+func (client *Client) Circuit() *Circuit {
+	return client.circuit
+}
+
+func (circuit *Circuit) State() CircuitState {
+	if circuit == nil {
+		return CircuitClosed
+	}
+
+	circuit.mu.Lock()
+	defer circuit.mu.Unlock()
+
+	return circuit.current(time.Now())
+}
+
+func (circuit *Circuit) Metrics() CircuitMetrics {
+	if circuit == nil {
+		return CircuitMetrics{}
+	}
+
+	circuit.mu.Lock()
+	defer circuit.mu.Unlock()
+
+	return CircuitMetrics{
+		State:               circuit.current(time.Now()),
+		Requests:            circuit.requests,
+		Failures:            circuit.failures,
+		Rejected:            circuit.rejected,
+		ConsecutiveFailures: circuit.consecutive,
+		FailureRate:         circuit.rate(),
+		OpenedAt:            circuit.openedAt,
+	}
+}
+
+// current must be called with circuit.mu held.
+func (circuit *Circuit) current(now time.Time) CircuitState {
+	if circuit.state == CircuitOpen && now.Sub(circuit.openedAt) >= circuit.config.OpenFor {
+		return CircuitHalfOpen
+	}
+
+	return circuit.state
+}
+
+// rate must be called with circuit.mu held.
+func (circuit *Circuit) rate() float64 {
+	if circuit.filled == 0 {
+		return 0
+	}
+
+	failed := 0
+
+	for _, outcome := range circuit.outcomes[:circuit.filled] {
+		if outcome {
+			failed++
+		}
+	}
+
+	return float64(failed) / float64(circuit.filled)
+}
+
+func (circuit *Circuit) allow() (circuitTicket, error) {
+	if circuit == nil {
+		return circuitTicket{}, nil
+	}
+
+	circuit.mu.Lock()
+	defer circuit.mu.Unlock()
+
+	switch circuit.current(time.Now()) {
+	case CircuitOpen:
+		circuit.rejected++
+
+		return circuitTicket{}, ErrCircuitOpen
+	case CircuitHalfOpen:
+		if circuit.probing {
+			circuit.rejected++
+
+			return circuitTicket{}, ErrCircuitOpen
+		}
+
+		circuit.transition(CircuitHalfOpen)
+		circuit.probing = true
+
+		return circuitTicket{generation: circuit.generation, probe: true}, nil
+	}
+
+	return circuitTicket{generation: circuit.generation}, nil
+}
+
+// release returns a ticket whose request never reached the network.
+func (circuit *Circuit) release(ticket circuitTicket) {
+	if circuit == nil || !ticket.probe {
+		return
+	}
+
+	circuit.mu.Lock()
+	defer circuit.mu.Unlock()
+
+	if ticket.generation == circuit.generation {
+		circuit.probing = false
+	}
+}
+
+// transition must be called with circuit.mu held.
+func (circuit *Circuit) transition(state CircuitState) {
+	circuit.state = state
+	circuit.generation++
+}
+
+func (circuit *Circuit) record(ctx context.Context, ticket circuitTicket, result *Response, err error) CircuitState {
+	if circuit == nil {
+		return CircuitClosed
+	}
+
+	circuit.mu.Lock()
+	defer circuit.mu.Unlock()
+
+	if ticket.generation != circuit.generation {
+		return circuit.state
+	}
+
+	probe := ticket.probe
+	if probe {
+		circuit.probing = false
+	}
+
+	if err != nil && ctx.Err() != nil {
+		return circuit.state
+	}
+
+	failed := err != nil && (result == nil || result.StatusCode >= http.StatusInternalServerError)
+
+	circuit.requests++
+	circuit.outcomes[circuit.next] = failed
+	circuit.next = (circuit.next + 1) % len(circuit.outcomes)
+	circuit.filled = min(circuit.filled+1, len(circuit.outcomes))
+
+	if !failed {
+		circuit.consecutive = 0
+
+		if probe {
+			circuit.transition(CircuitClosed)
+			circuit.filled, circuit.next = 0, 0
+		}
+
+		return circuit.state
+	}
+
+	circuit.failures++
+	circuit.consecutive++
+
+	tripped := circuit.consecutive >= circuit.config.ConsecutiveFailures ||
+		(circuit.config.FailureRate > 0 && circuit.filled == len(circuit.outcomes) &&
+			circuit.rate() >= circuit.config.FailureRate)
+
+	if probe || (circuit.state == CircuitClosed && tripped) {
+		circuit.transition(CircuitOpen)
+		circuit.openedAt = time.Now()
+	}
+
+	return circuit.state
+}
+
+// recordCircuit only counts requests that reached the network; local
+// failures such as rejected headers or missing credentials are not counted.
+func (client *Client) recordCircuit(
+	ctx context.Context,
+	spec *RequestSpec,
+	options *requestOptions,
+	ticket circuitTicket,
+	result *Response,
+	err error,
+) {
+	if client.circuit == nil {
+		return
+	}
+
+	if !options.sent {
+		client.circuit.release(ticket)
+		return
+	}
+
+	before := client.circuit.State()
+
+	if after := client.circuit.record(ctx, ticket, result, err); after != before {
+		client.logger.Warn().
+			Str(client.logField("method"), spec.Method).
+			Func(client.logURL(client.baseUrl+spec.Path)).
+			Str("from", before.String()).
+			Str("to", after.String()).
+			Msg("circuit breaker state changed")
+	}
+}
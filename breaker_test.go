@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestCircuitBreaker_TripsAndRecovers(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua",
+		WithCircuitBreaker(CircuitBreaker{ConsecutiveFailures: 2, OpenFor: 50 * time.Millisecond}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	spec := RequestSpec{Method: http.MethodGet, Path: "/"}
+
+	for i := 0; i < 2; i++ {
+		if _, err = c.Send(context.Background(), spec); !errors.Is(err, ErrRequestFailed) {
+			t.Fatalf("attempt %d: err=%v", i, err)
+		}
+	}
+
+	if _, err = c.Send(context.Background(), spec); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err=%v, want ErrCircuitOpen", err)
+	}
+	if calls.Load() != 2 || c.Circuit().State() != CircuitOpen {
+		t.Fatalf("calls=%d state=%s", calls.Load(), c.Circuit().State())
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if c.Circuit().State() != CircuitHalfOpen {
+		t.Fatalf("state=%s, want half-open", c.Circuit().State())
+	}
+
+	if _, err = c.Send(context.Background(), spec); !errors.Is(err, ErrRequestFailed) {
+		t.Fatalf("failed probe: err=%v", err)
+	}
+	if c.Circuit().State() != CircuitOpen {
+		t.Fatalf("failed probe left state=%s", c.Circuit().State())
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	failing.Store(false)
+
+	if _, err = c.Send(context.Background(), spec); err != nil {
+		t.Fatalf("probe error: %v", err)
+	}
+
+	metrics := c.Circuit().Metrics()
+	if metrics.State != CircuitClosed || metrics.Requests != 4 || metrics.Failures != 3 || metrics.Rejected != 1 {
+		t.Fatalf("metrics=%+v", metrics)
+	}
+}
+
+func TestCircuitBreaker_FailureRate(t *testing.T) {
+	circuit := &Circuit{
+		config:   CircuitBreaker{ConsecutiveFailures: 10, FailureRate: 0.5, Window: 4, OpenFor: time.Minute},
+		outcomes: make([]bool, 4),
+	}
+	ctx := context.Background()
+	failed := &Response{StatusCode: http.StatusBadGateway}
+	ok := &Response{StatusCode: http.StatusOK}
+
+	circuit.record(ctx, circuitTicket{}, failed, ErrRequestFailed)
+	circuit.record(ctx, circuitTicket{}, ok, nil)
+	circuit.record(ctx, circuitTicket{}, failed, ErrRequestFailed)
+
+	if circuit.State() != CircuitClosed {
+		t.Fatal("tripped before the window filled")
+	}
+
+	circuit.record(ctx, circuitTicket{}, &Response{StatusCode: http.StatusNotFound}, ErrRequestFailed)
+	if circuit.State() != CircuitClosed {
+		t.Fatal("4xx counted as failure")
+	}
+
+	circuit.record(ctx, circuitTicket{}, failed, ErrRequestFailed)
+	if circuit.State() != CircuitOpen {
+		t.Fatalf("state=%s rate=%v", circuit.State(), circuit.Metrics().FailureRate)
+	}
+}
+
+func TestCircuitBreaker_NilIsClosed(t *testing.T) {
+	c := newTestClient(t, "http://example.com")
+	if c.Circuit().State() != CircuitClosed || c.Circuit().Metrics() != (CircuitMetrics{}) {
+		t.Fatal("nil circuit must report closed")
+	}
+}
+
+func TestCircuitBreaker_OnlyTheProbeChangesHalfOpen(t *testing.T) {
+	circuit := &Circuit{
+		config:   CircuitBreaker{ConsecutiveFailures: 1, OpenFor: time.Millisecond},
+		outcomes: make([]bool, 1),
+	}
+	ctx := context.Background()
+	failed := &Response{StatusCode: http.StatusBadGateway}
+
+	slow, err := circuit.allow()
+	if err != nil {
+		t.Fatalf("allow: %v", err)
+	}
+
+	circuit.record(ctx, circuitTicket{generation: slow.generation}, failed, ErrRequestFailed)
+	time.Sleep(5 * time.Millisecond)
+
+	probe, err := circuit.allow()
+	if err != nil || !probe.probe {
+		t.Fatalf("probe=%+v err=%v", probe, err)
+	}
+
+	if state := circuit.record(ctx, slow, &Response{StatusCode: http.StatusOK}, nil); state != CircuitHalfOpen {
+		t.Fatalf("stale success moved the circuit to %s", state)
+	}
+	if _, err = circuit.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second probe admitted: err=%v", err)
+	}
+
+	if state := circuit.record(ctx, probe, failed, ErrRequestFailed); state != CircuitOpen {
+		t.Fatalf("failed probe left state=%s", state)
+	}
+}
+
+func TestCircuitBreaker_IgnoresLocalErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua",
+		WithCircuitBreaker(CircuitBreaker{ConsecutiveFailures: 1, OpenFor: time.Minute}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	denied := errors.New("denied")
+	c.OnRequest(func(*http.Request) error { return denied })
+
+	for i := 0; i < 3; i++ {
+		if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); !errors.Is(err, denied) {
+			t.Fatalf("attempt %d: err=%v", i, err)
+		}
+	}
+
+	if metrics := c.Circuit().Metrics(); metrics.State != CircuitClosed || metrics.Requests != 0 || calls.Load() != 0 {
+		t.Fatalf("metrics=%+v calls=%d", metrics, calls.Load())
+	}
+}
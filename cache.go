@@ -0,0 +1,288 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrCacheMiss = errors.New("cache miss")
+	ErrOffline   = errors.New("client is offline and the response is not cached")
+)
+
+type CachedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	StoredAt   time.Time   `json:"storedAt"`
+	ExpiresAt  time.Time   `json:"expiresAt"`
+}
+
+// Cache stores successful GET responses. Get returns ErrCacheMiss for
+// unknown or expired keys; any other error is logged and treated as a miss.
+type Cache interface {
+	Get(ctx context.Context, key string) (*CachedResponse, error)
+	Set(ctx context.Context, key string, entry *CachedResponse) error
+	Delete(ctx context.Context, key string) error
+}
+
+// CacheMode overrides the client cache for a single request.
+type CacheMode int
+
+const (
+	CacheDefault CacheMode = iota
+	// CacheRefresh skips the cached entry, like Cache-Control no-cache, and
+	// stores the fresh response.
+	CacheRefresh
+	// CacheBypass neither reads nor writes the cache, like no-store.
+	CacheBypass
+)
+
+type CacheKeyFunc func(ctx context.Context, spec *RequestSpec, header http.Header) string
+
+// CacheKeyConfig builds a CacheKeyFunc. The key always covers the method,
+// path and query; IncludeHeaders and Principal narrow it further so
+// responses are not shared between tenants or users.
+type CacheKeyConfig struct {
+	IncludeHeaders []string
+	ExcludeParams  []string
+	Principal      func(ctx context.Context, spec *RequestSpec) string
+}
+
+type cacheConfig struct {
+	cache   Cache
+	ttl     time.Duration
+	keyFunc CacheKeyFunc
+}
+
+// WithCache caches successful GET responses. Responses with Cache-Control
+// max-age use it as their lifetime, others live for ttl; no-store responses
+// and a zero ttl without max-age are not cached.
+func WithCache(cache Cache, ttl time.Duration) Option {
+	return func(client *Client) error {
+		if client.cache == nil {
+			client.cache = &cacheConfig{}
+		}
+
+		client.cache.cache = cache
+		client.cache.ttl = ttl
+
+		return nil
+	}
+}
+
+// WithOfflineMode starts the client offline: requests are answered from the
+// cache only and fail with ErrOffline on a miss. See SetOffline.
+func WithOfflineMode() Option {
+	return func(client *Client) error {
+		client.offline.Store(true)
+
+		return nil
+	}
+}
+
+func (client *Client) SetOffline(offline bool) {
+	client.offline.Store(offline)
+}
+
+func (client *Client) Offline() bool {
+	return client.offline.Load()
+}
+
+// CacheControl sets the cache mode of one request without changing the
+// client cache.
+func CacheControl(mode CacheMode) RequestOption {
+	return func(options *requestOptions) {
+		options.cacheMode = mode
+	}
+}
+
+func WithCacheKeyFunc(keyFunc CacheKeyFunc) Option {
+	return func(client *Client) error {
+		if client.cache == nil {
+			client.cache = &cacheConfig{}
+		}
+
+		client.cache.keyFunc = keyFunc
+
+		return nil
+	}
+}
+
+// DefaultCacheKey varies on the Authorization header so cached responses are
+// never shared between credentials.
+var DefaultCacheKey = CacheKey(CacheKeyConfig{IncludeHeaders: []string{AuthorizationHeader}})
+
+func CacheKey(config CacheKeyConfig) CacheKeyFunc {
+	excluded := map[string]bool{}
+	for _, param := range config.ExcludeParams {
+		excluded[param] = true
+	}
+
+	headers := append([]string(nil), config.IncludeHeaders...)
+	sort.Strings(headers)
+
+	return func(ctx context.Context, spec *RequestSpec, header http.Header) string {
+		params := spec.Params.Clone()
+
+		for _, param := range spec.OrderedParams {
+			params.Add(param.Key, param.Value)
+		}
+
+		for param := range excluded {
+			params.Del(param)
+		}
+
+		parts := []string{spec.Method, spec.Path, params.Values().Encode()}
+
+		for _, name := range headers {
+			parts = append(parts, name+"="+strings.Join(header.Values(name), ","))
+		}
+
+		if config.Principal != nil {
+			parts = append(parts, "principal="+config.Principal(ctx, spec))
+		}
+
+		sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+
+		return hex.EncodeToString(sum[:])
+	}
+}
+
+func (client *Client) cacheKey(ctx context.Context, spec *RequestSpec) string {
+	if client.cache == nil || client.cache.cache == nil || spec.Method != http.MethodGet {
+		return ""
+	}
+
+	keyFunc := client.cache.keyFunc
+	if keyFunc == nil {
+		keyFunc = DefaultCacheKey
+	}
+
+	return keyFunc(ctx, spec, client.requestHeaders(spec))
+}
+
+func (client *Client) cachedResponse(ctx context.Context, key string, mode CacheMode) *Response {
+	if key == "" || mode == CacheRefresh {
+		return nil
+	}
+
+	entry, err := client.cache.cache.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, ErrCacheMiss) {
+			client.logger.Warn().
+				Err(err).
+				Msg("failed to read http cache")
+		}
+
+		return nil
+	}
+
+	if !entry.ExpiresAt.IsZero() && time.Now().After(entry.ExpiresAt) {
+		return nil
+	}
+
+	return &Response{
+		StatusCode: entry.StatusCode,
+		Header:     entry.Header.Clone(),
+		Body:       entry.Body,
+	}
+}
+
+func (client *Client) storeResponse(ctx context.Context, key string, response *Response) {
+	if key == "" || response.StatusCode != http.StatusOK {
+		return
+	}
+
+	ttl, ok := cacheLifetime(response.Header, client.cache.ttl)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	entry := &CachedResponse{
+		StatusCode: response.StatusCode,
+		Header:     response.Header.Clone(),
+		Body:       response.Body,
+		StoredAt:   now,
+		ExpiresAt:  now.Add(ttl),
+	}
+
+	if err := client.cache.cache.Set(ctx, key, entry); err != nil {
+		client.logger.Warn().
+			Err(err).
+			Msg("failed to write http cache")
+	}
+}
+
+func cacheLifetime(header http.Header, fallback time.Duration) (time.Duration, bool) {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(strings.ToLower(directive)), "=")
+
+		switch name {
+		case "no-store", "no-cache":
+			return 0, false
+		case "max-age":
+			seconds, err := strconv.Atoi(value)
+			if err != nil || seconds <= 0 {
+				return 0, false
+			}
+
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+
+	return fallback, fallback > 0
+}
+
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]*CachedResponse
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: map[string]*CachedResponse{}}
+}
+
+func (cache *MemoryCache) Get(_ context.Context, key string) (*CachedResponse, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.entries[key]
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+
+	if !entry.ExpiresAt.IsZero() && time.Now().After(entry.ExpiresAt) {
+		delete(cache.entries, key)
+		return nil, ErrCacheMiss
+	}
+
+	return entry, nil
+}
+
+func (cache *MemoryCache) Set(_ context.Context, key string, entry *CachedResponse) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.entries[key] = entry
+
+	return nil
+}
+
+func (cache *MemoryCache) Delete(_ context.Context, key string) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	delete(cache.entries, key)
+
+	return nil
+}
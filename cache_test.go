@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type tenantContextKey struct{}
+
+func newCountingServer(t *testing.T, cacheControl string) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&hits, 1)
+		if cacheControl != "" {
+			w.Header().Set("Cache-Control", cacheControl)
+		}
+		io.WriteString(w, fmt.Sprint(n))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &hits
+}
+
+func TestWithCache_DefaultKeyVariesOnAuthorization(t *testing.T) {
+	srv, hits := newCountingServer(t, "")
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithCache(NewMemoryCache(), time.Minute))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	first, _, _ := c.SendGet("/items", Params{"a": "1"}, Headers{AuthorizationHeader: "Bearer one"})
+	again, _, _ := c.SendGet("/items", Params{"a": "1"}, Headers{AuthorizationHeader: "Bearer one"})
+	other, _, _ := c.SendGet("/items", Params{"a": "1"}, Headers{AuthorizationHeader: "Bearer two"})
+
+	if string(first) != "1" || string(again) != "1" || string(other) != "2" {
+		t.Fatalf("bodies: %s %s %s", first, again, other)
+	}
+	if atomic.LoadInt32(hits) != 2 {
+		t.Fatalf("hits=%d", *hits)
+	}
+
+	if _, _, err = c.SendPost("/items", nil, nil, nil); err != nil || atomic.LoadInt32(hits) != 3 {
+		t.Fatalf("POST must not be cached: hits=%d err=%v", *hits, err)
+	}
+}
+
+func TestWithCacheKeyFunc_PrincipalAndExcludedParams(t *testing.T) {
+	srv, hits := newCountingServer(t, "max-age=60")
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua",
+		WithCache(NewMemoryCache(), 0),
+		WithCacheKeyFunc(CacheKey(CacheKeyConfig{
+			ExcludeParams: []string{"_"},
+			Principal: func(ctx context.Context, _ *RequestSpec) string {
+				tenant, _ := ctx.Value(tenantContextKey{}).(string)
+				return tenant
+			},
+		})))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	get := func(tenant, buster string) string {
+		ctx := context.WithValue(context.Background(), tenantContextKey{}, tenant)
+		resp, err := c.Send(ctx, RequestSpec{Method: http.MethodGet, Path: "/x", Params: MultiParams{"_": {buster}}})
+		if err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+		return string(resp.Body)
+	}
+
+	if a, b, other := get("t1", "1"), get("t1", "2"), get("t2", "1"); a != "1" || b != "1" || other != "2" {
+		t.Fatalf("bodies: %s %s %s", a, b, other)
+	}
+	if atomic.LoadInt32(hits) != 2 {
+		t.Fatalf("hits=%d", *hits)
+	}
+}
+
+func TestWithCache_RespectsNoStore(t *testing.T) {
+	srv, hits := newCountingServer(t, "no-store")
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithCache(NewMemoryCache(), time.Minute))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	_, _, _ = c.SendGet("/x", nil, nil)
+	_, _, _ = c.SendGet("/x", nil, nil)
+	if atomic.LoadInt32(hits) != 2 {
+		t.Fatalf("no-store response was cached, hits=%d", *hits)
+	}
+}
+
+func TestOfflineMode_ServesCacheAndFailsFastOnMiss(t *testing.T) {
+	srv, hits := newCountingServer(t, "")
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithCache(NewMemoryCache(), time.Minute))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	if _, _, err = c.SendGet("/cached", nil, nil); err != nil {
+		t.Fatalf("SendGet error: %v", err)
+	}
+
+	c.SetOffline(true)
+
+	body, _, err := c.SendGet("/cached", nil, nil)
+	if err != nil || string(body) != "1" {
+		t.Fatalf("cached body=%s err=%v", body, err)
+	}
+
+	body, status, err := c.SendGet("/missing", nil, nil)
+	if !errors.Is(err, ErrOffline) || body != nil || status != nil {
+		t.Fatalf("expected ErrOffline, got body=%s status=%v err=%v", body, status, err)
+	}
+	if _, _, err = c.SendPost("/cached", nil, nil, nil); !errors.Is(err, ErrOffline) {
+		t.Fatalf("expected ErrOffline for POST, got %v", err)
+	}
+	if atomic.LoadInt32(hits) != 1 {
+		t.Fatalf("offline client reached the server, hits=%d", *hits)
+	}
+}
+
+func TestCacheControl_RefreshAndBypass(t *testing.T) {
+	srv, hits := newCountingServer(t, "")
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithCache(NewMemoryCache(), time.Minute))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	ctx := context.Background()
+	spec := RequestSpec{Method: http.MethodGet, Path: "/items"}
+	body := func(opts ...RequestOption) string {
+		resp, err := c.Send(ctx, spec, opts...)
+		if err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+		return string(resp.Body)
+	}
+
+	if got := body(); got != "1" {
+		t.Fatalf("first=%s", got)
+	}
+	if got := body(CacheControl(CacheBypass)); got != "2" {
+		t.Fatalf("bypass=%s", got)
+	}
+	if got := body(); got != "1" {
+		t.Fatalf("bypass overwrote the cache: %s", got)
+	}
+	if got := body(CacheControl(CacheRefresh)); got != "3" {
+		t.Fatalf("refresh=%s", got)
+	}
+	if got := body(); got != "3" || atomic.LoadInt32(hits) != 3 {
+		t.Fatalf("refresh did not store: %s hits=%d", got, *hits)
+	}
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+const defaultWarmCacheConcurrency = 4
+
+var (
+	ErrNoCache      = errors.New("client has no cache")
+	ErrNotCacheable = errors.New("request is not cacheable")
+)
+
+// WarmCache sends specs with at most concurrency requests in flight, four
+// when it is not positive, so their responses are in the cache before real
+// traffic arrives. Entries that are still fresh are not fetched again. Only
+// GET requests are cached; other specs fail with ErrNotCacheable. Failures
+// are returned as a *MultiError indexed like specs.
+func (client *Client) WarmCache(ctx context.Context, specs []RequestSpec, concurrency int) error {
+	if client.cache == nil || client.cache.cache == nil {
+		return ErrNoCache
+	}
+
+	if concurrency < 1 {
+		concurrency = defaultWarmCacheConcurrency
+	}
+
+	collector := &multiErrorCollector{total: len(specs)}
+	slots := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for i, spec := range specs {
+		if spec.Method != http.MethodGet {
+			collector.add(i, ErrNotCacheable)
+			continue
+		}
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			collector.add(i, ctx.Err())
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(index int, spec RequestSpec) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if _, err := client.Send(ctx, spec); err != nil {
+				collector.add(index, err)
+			}
+		}(i, spec)
+	}
+
+	wg.Wait()
+
+	return collector.err()
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWarmCache_PopulatesCacheWithBoundedConcurrency(t *testing.T) {
+	var inFlight, peak, hits atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			highest := peak.Load()
+			if current <= highest || peak.CompareAndSwap(highest, current) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithCache(NewMemoryCache(), time.Minute))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	specs := make([]RequestSpec, 0, 6)
+	for _, path := range []string{"/a", "/b", "/c", "/d", "/e", "/f"} {
+		specs = append(specs, RequestSpec{Method: http.MethodGet, Path: path})
+	}
+
+	if err = c.WarmCache(context.Background(), specs, 2); err != nil {
+		t.Fatalf("WarmCache() error: %v", err)
+	}
+
+	if got := peak.Load(); got > 2 {
+		t.Fatalf("peak concurrency = %d, want at most 2", got)
+	}
+	if got := hits.Load(); got != 6 {
+		t.Fatalf("hits = %d, want 6", got)
+	}
+
+	resp, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/c"})
+	if err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+	if !resp.CacheHit || string(resp.Body) != "/c" {
+		t.Fatalf("expected a cache hit for /c, got hit=%v body=%q", resp.CacheHit, resp.Body)
+	}
+	if got := hits.Load(); got != 6 {
+		t.Fatalf("hits after warm = %d, want 6", got)
+	}
+}
+
+func TestWarmCache_ReportsFailuresByIndex(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithCache(NewMemoryCache(), time.Minute))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	err = c.WarmCache(context.Background(), []RequestSpec{
+		{Method: http.MethodGet, Path: "/ok"},
+		{Method: http.MethodPost, Path: "/ok"},
+		{Method: http.MethodGet, Path: "/missing"},
+	}, 0)
+
+	var multi *MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("err = %v, want *MultiError", err)
+	}
+	if multi.Err(0) != nil || !errors.Is(multi.Err(1), ErrNotCacheable) || !errors.Is(multi.Err(2), ErrRequestFailed) {
+		t.Fatalf("unexpected item errors: %v", err)
+	}
+}
+
+func TestWarmCache_NeedsCache(t *testing.T) {
+	c := newTestClient(t, "http://example.com")
+
+	if err := c.WarmCache(context.Background(), nil, 1); !errors.Is(err, ErrNoCache) {
+		t.Fatalf("err = %v, want ErrNoCache", err)
+	}
+}
+
+func TestWarmCache_StopsOnCanceledContext(t *testing.T) {
+	release := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithCache(NewMemoryCache(), time.Minute))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = c.WarmCache(ctx, []RequestSpec{
+		{Method: http.MethodGet, Path: "/a"},
+		{Method: http.MethodGet, Path: "/b"},
+	}, 1)
+
+	var multi *MultiError
+	if !errors.As(err, &multi) || len(multi.Errors) != 2 {
+		t.Fatalf("err = %v, want both specs to fail", err)
+	}
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// CapturedRequest is a redacted record of a single attempt sent upstream.
+// Query parameters and headers named by WithRedactedQueryParams and
+// WithRedactedHeaders, credentials among them, are masked and bodies are not
+// kept.
+type CapturedRequest struct {
+	Time           time.Time
+	Method         string
+	URL            string
+	RequestHeader  http.Header
+	StatusCode     int
+	ResponseHeader http.Header
+	Duration       time.Duration
+	Err            string
+	RemoteAddr     string
+}
+
+type captureRing struct {
+	mu      sync.Mutex
+	entries []CapturedRequest
+	next    int
+	full    bool
+}
+
+// WithRequestCapture keeps the last size attempts in memory for
+// RecentRequests.
+func WithRequestCapture(size int) Option {
+	return func(client *Client) error {
+		if size > 0 {
+			client.capture = &captureRing{entries: make([]CapturedRequest, size)}
+		}
+
+		return nil
+	}
+}
+
+// RecentRequests returns the captured attempts, oldest first. It is empty
+// unless WithRequestCapture is set.
+func (client *Client) RecentRequests() []CapturedRequest {
+	if client.capture == nil {
+		return nil
+	}
+
+	return client.capture.snapshot()
+}
+
+func (client *Client) captureRequest(
+	request *http.Request,
+	response *http.Response,
+	connection *ConnectionInfo,
+	timings *requestTimings,
+	err error,
+) {
+	if client.capture == nil {
+		return
+	}
+
+	entry := CapturedRequest{
+		Time:          timings.start,
+		Method:        request.Method,
+		URL:           client.redactURL(request.URL),
+		RequestHeader: client.redactHeader(request.Header),
+		Duration:      time.Since(timings.start),
+		RemoteAddr:    connection.RemoteAddr,
+	}
+
+	if err != nil {
+		entry.Err = err.Error()
+	}
+
+	if response != nil {
+		entry.StatusCode = response.StatusCode
+		entry.ResponseHeader = client.redactHeader(response.Header)
+	}
+
+	client.capture.add(entry)
+}
+
+func (client *Client) redactURL(u *url.URL) string {
+	redacted := *u
+	query := redacted.Query()
+
+	for key := range query {
+		if client.isRedactedParam(key) {
+			query[key] = []string{redactedValue}
+		}
+	}
+
+	redacted.RawQuery = query.Encode()
+
+	return redacted.Redacted()
+}
+
+func (client *Client) redactHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+
+	for name := range redacted {
+		if client.isRedactedHeader(name) {
+			redacted[name] = []string{redactedValue}
+		}
+	}
+
+	return redacted
+}
+
+func (ring *captureRing) add(entry CapturedRequest) {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	ring.entries[ring.next] = entry
+	ring.next = (ring.next + 1) % len(ring.entries)
+
+	if ring.next == 0 {
+		ring.full = true
+	}
+}
+
+func (ring *captureRing) snapshot() []CapturedRequest {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	if !ring.full {
+		return append([]CapturedRequest(nil), ring.entries[:ring.next]...)
+	}
+
+	return append(append([]CapturedRequest(nil), ring.entries[ring.next:]...), ring.entries[:ring.next]...)
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestRecentRequests_KeepsLastRedacted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=s3cr3t")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithRequestCapture(2))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	for _, path := range []string{"/a", "/b", "/c"} {
+		_, err = c.Send(context.Background(), RequestSpec{
+			Method:  http.MethodGet,
+			Path:    path,
+			Params:  MultiParams{"token": {"abc123"}},
+			Headers: MultiHeaders{AuthorizationHeader: {"Bearer abc123"}},
+		})
+		if err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+	}
+
+	recent := c.RecentRequests()
+	if len(recent) != 2 {
+		t.Fatalf("recent=%d", len(recent))
+	}
+	if !strings.Contains(recent[0].URL, "/b?") || !strings.Contains(recent[1].URL, "/c?") {
+		t.Fatalf("urls: %s %s", recent[0].URL, recent[1].URL)
+	}
+
+	for _, entry := range recent {
+		if strings.Contains(entry.URL, "abc123") || entry.RequestHeader.Get(AuthorizationHeader) != redactedValue {
+			t.Fatalf("request not redacted: %+v", entry)
+		}
+		if entry.ResponseHeader.Get("Set-Cookie") != redactedValue || entry.StatusCode != http.StatusAccepted {
+			t.Fatalf("response not captured: %+v", entry)
+		}
+	}
+}
+
+func TestRecentRequests_DisabledByDefault(t *testing.T) {
+	c := newTestClient(t, "http://example.com")
+	if recent := c.RecentRequests(); recent != nil {
+		t.Fatalf("recent=%v", recent)
+	}
+}
+
+func TestRecentRequests_RedactsAPIKeyAndConfiguredHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua",
+		WithRequestCapture(1),
+		WithAPIKey("X-Partner-Key", StaticToken("k3y")),
+		WithRedactedHeaders("X-Session-Secret"),
+		WithRedactedQueryParams("x-shared"),
+	)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	_, err = c.Send(context.Background(), RequestSpec{
+		Method: http.MethodGet,
+		Path:   "/",
+		Params: MultiParams{"X-Session-Secret": {"q1"}},
+		Headers: MultiHeaders{
+			"X-Session-Secret": {"h1"},
+			"X-Shared":         {"h2"},
+			"X-CSRF-Token":     {"h3"},
+			"Signature":        {"h4"},
+			"X-Trace":          {"visible"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	entry := c.RecentRequests()[0]
+	for _, name := range []string{"X-Partner-Key", "X-Session-Secret", "X-Shared", "X-CSRF-Token", "Signature"} {
+		if got := entry.RequestHeader.Get(name); got != redactedValue {
+			t.Fatalf("%s = %q, want redacted", name, got)
+		}
+	}
+	if entry.RequestHeader.Get("X-Trace") != "visible" {
+		t.Fatalf("X-Trace was redacted")
+	}
+	if strings.Contains(entry.URL, "q1") {
+		t.Fatalf("query not redacted: %s", entry.URL)
+	}
+}
@@ -2,12 +2,15 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"github.com/rs/zerolog"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -15,15 +18,93 @@ const (
 	defaultTimeout = 10
 )
 
+var ErrRequestFailed = errors.New("http request failed")
+
+// ErrAbsolutePath is returned when RequestSpec.Path is an absolute URL;
+// requests always go to the client's base URL.
+var ErrAbsolutePath = errors.New("request path is an absolute url")
+
 type Client struct {
 	Headers    Headers
 	baseUrl    string
 	httpClient http.Client
 	logger     *zerolog.Logger
 	userAgent  string
+	envelope   *EnvelopeFields
+	validators []func(*Response) error
+
+	requestValidators  []func(*RequestSpec) error
+	preserveQueryOrder bool
+	warmup             *warmupConfig
+	dial               *dialConfig
+	logConnections     bool
+	endpoints          *endpointPool
+	extraEndpoints     []string
+	outlier            *OutlierDetection
+	srv                srvConfig
+	sticky             *StickySessions
+	cache              *cacheConfig
+	offline            atomic.Bool
+	coalescing         *coalescer
+	stats              clientStats
+	metricsHook        func(RequestMetrics)
+	lifecycle          lifecycle
+	bodyMarshaler      BodyMarshaler
+	allowedMethods     map[string]struct{}
+	permissiveMethods  bool
+	allowTrace         bool
+	allowConnect       bool
+	encodings          []string
+	uploadLimit        *bandwidthLimiter
+	downloadLimit      *bandwidthLimiter
+	retrySameEndpoint  bool
+
+	retryStaleConnections bool
+	cooldown              *cooldownTracker
+	rateLimits            *keyedRateLimiter
+	headerLimits          *HeaderLimits
+	maxRequestBytes       int64
+	allowedSchemes        map[string]bool
+	baseUrlCredentials    bool
+	redactedNames         map[string]bool
+	logFieldNames         map[string]string
+	slowThreshold         time.Duration
+	successSampler        *successSampler
+	capture               *captureRing
+	jsonEngine            JSONEngine
+	paginationHeaders     *PaginationHeaders
+	middleware            []Middleware
+	consistencyHeaders    []string
+	auth                  *authScheme
+	requestTransforms     []RequestTransform
+	responseTransforms    []ResponseTransform
+	bodyDigest            *BodyDigest
+	signatures            *MessageSignatures
+	nonce                 *NonceHeaders
+	allowedHeaders        map[string]bool
+	bodyReadTimeout       time.Duration
+	decodeOptions         DecodeOptions
+	deadlineHeader        *deadlineHeader
+	retry                 *RetryPolicy
+	circuit               *Circuit
+	latency               latencyTracker
+	requestHooks          []func(*http.Request) error
+	responseHooks         []func(*http.Request, *Response, error)
+	maxRedirects          *int
+	defaultTags           map[string]string
+	apiVersion            *APIVersion
+	sharedTransport       bool
+	replacedTransport     http.RoundTripper
 }
 
-func New(baseUrl string, timeout *int, log *zerolog.Logger, nolog bool, userAgent string) (*Client, error) {
+func New(
+	baseUrl string,
+	timeout *int,
+	log *zerolog.Logger,
+	nolog bool,
+	userAgent string,
+	opts ...Option,
+) (*Client, error) {
 	if log == nil && !nolog {
 		return nil, errors.New("no logger provided")
 	}
@@ -39,7 +120,7 @@ func New(baseUrl string, timeout *int, log *zerolog.Logger, nolog bool, userAgen
 		tt = *timeout
 	}
 
-	return &Client{
+	client := &Client{
 		Headers: Headers{},
 		baseUrl: baseUrl,
 		httpClient: http.Client{
@@ -47,7 +128,45 @@ func New(baseUrl string, timeout *int, log *zerolog.Logger, nolog bool, userAgen
 		},
 		logger:    log,
 		userAgent: userAgent,
-	}, nil
+	}
+
+	for _, opt := range opts {
+		if err := opt(client); err != nil {
+			return nil, err
+		}
+	}
+
+	baseUrl, err := client.normalizeBaseUrl(baseUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, extra := range client.extraEndpoints {
+		if client.extraEndpoints[i], err = client.normalizeBaseUrl(extra); err != nil {
+			return nil, err
+		}
+	}
+
+	if client.replacedTransport != nil {
+		return nil, fmt.Errorf("%w, got %T", errTransportOptions, client.replacedTransport)
+	}
+
+	client.baseUrl = baseUrl
+	client.applyMiddleware()
+
+	if client.httpClient.CheckRedirect == nil {
+		client.httpClient.CheckRedirect = client.checkRedirect
+	}
+
+	if err := client.setupEndpoints(baseUrl); err != nil {
+		return nil, err
+	}
+
+	if client.warmup != nil {
+		client.startWarmup()
+	}
+
+	return client, nil
 }
 
 func (client *Client) SetHeader(key, val string) *Client {
@@ -56,243 +175,643 @@ func (client *Client) SetHeader(key, val string) *Client {
 	return client
 }
 
-func (client *Client) fillRequestHeaders(r *http.Request, headers Headers) *Client {
+func (client *Client) fillRequestHeaders(r *http.Request, headers MultiHeaders) *Client {
 	for key, val := range client.Headers {
 		r.Header.Add(key, val)
 	}
 
-	for key, val := range headers {
-		r.Header.Add(key, val)
+	for key, vals := range headers {
+		for _, val := range vals {
+			r.Header.Add(key, val)
+		}
 	}
 
 	if client.userAgent != "" {
 		r.Header.Set("User-Agent", client.userAgent)
 	}
 
+	if host := r.Header.Get(hostHeader); host != "" {
+		r.Host = host
+		r.Header.Del(hostHeader)
+	}
+
 	return client
 }
 
-func (client *Client) SendGet(path string, params Params, headers Headers) ([]byte, *int, error) {
-	request, err := client.createRequest(http.MethodGet, path, params, nil)
-	if err != nil {
+func (client *Client) requestHeaders(spec *RequestSpec) http.Header {
+	header := http.Header{}
+
+	for key, val := range client.Headers {
+		header.Add(key, val)
+	}
+
+	for key, vals := range spec.Headers {
+		for _, val := range vals {
+			header.Add(key, val)
+		}
+	}
+
+	return header
+}
+
+func (client *Client) Send(ctx context.Context, spec RequestSpec, opts ...RequestOption) (*Response, error) {
+	client.filterHeaders(&spec)
+
+	return client.sendFiltered(ctx, spec, opts)
+}
+
+// sendFiltered sends spec whose headers already passed the allowlist.
+func (client *Client) sendFiltered(ctx context.Context, spec RequestSpec, opts []RequestOption) (*Response, error) {
+	ctx, id, ok := client.lifecycle.enter(ctx)
+	if !ok {
+		return nil, ErrClientClosed
+	}
+
+	defer client.lifecycle.leave(id)
+
+	ctx, meta := withMeta(ctx)
+	client.tagMeta(meta)
+	started := time.Now()
+
+	client.applyConsistency(ctx, &spec)
+
+	response, err := client.dispatch(ctx, spec, opts)
+	client.recordConsistency(ctx, spec.Method, response)
+
+	if response != nil {
+		response.Meta = meta
+		response.Duration = time.Since(started)
+	}
+
+	return response, canceledByClient(ctx, err)
+}
+
+func (client *Client) dispatch(ctx context.Context, spec RequestSpec, opts []RequestOption) (*Response, error) {
+	options := newRequestOptions(append(append([]RequestOption{}, spec.Options...), opts...))
+	options.applyHeaders(&spec)
+
+	if !options.absoluteUrl && isAbsoluteUrl(spec.Path) {
 		client.logger.Error().
-			Err(err).
-			Str("method", http.MethodGet).
-			Str("url", client.baseUrl+path).
-			Msg("failed to build HTTP request")
-		return nil, nil, err
+			Str(client.logField("method"), spec.Method).
+			Func(client.logURL(spec.Path)).
+			Msg("refused http request to an absolute url")
+		return nil, ErrAbsolutePath
 	}
 
-	client.fillRequestHeaders(request, headers)
+	ctx, cancel := client.clampDeadline(ctx, &spec, options)
+	defer cancel()
 
-	var response *http.Response
+	if err := client.encodeBody(&spec, options); err != nil {
+		client.logger.Error().
+			Err(err).
+			Str(client.logField("method"), spec.Method).
+			Func(client.logURL(client.baseUrl + spec.Path)).
+			Msg("failed to encode HTTP request body")
+		return nil, err
+	}
 
-	response, err = client.getResponse(request)
-	if err != nil {
+	if err := client.transformRequest(ctx, &spec); err != nil {
 		client.logger.Error().
 			Err(err).
-			Str("method", request.Method).
-			Str("url", request.URL.String()).
-			Msg("failed to send HTTP request")
-		return nil, nil, err
+			Str(client.logField("method"), spec.Method).
+			Func(client.logURL(client.baseUrl + spec.Path)).
+			Msg("failed to transform HTTP request body")
+		return nil, err
 	}
 
-	client.logger.Info().
-		Str("method", request.Method).
-		Str("url", request.URL.String()).
-		Int("status", response.StatusCode).
-		Msg("http request succeeded")
+	if err := client.digestBody(&spec); err != nil {
+		client.logger.Error().
+			Err(err).
+			Str(client.logField("method"), spec.Method).
+			Func(client.logURL(client.baseUrl + spec.Path)).
+			Msg("failed to hash HTTP request body")
+		return nil, err
+	}
 
-	return getResponseBody(response, client.logger)
-}
+	var (
+		response *Response
+		err      error
+	)
 
-func (client *Client) SendPost(
-	path string,
-	jsonData []byte,
-	queryParams Params,
-	headers Headers,
-) ([]byte, *int, error) {
+	if client.coalescing != nil && spec.Method == http.MethodGet && options.sink == nil {
+		response, err = client.coalescing.do(ctx, client.coalescingKey(ctx, &spec), func() (*Response, error) {
+			return client.doSend(ctx, spec, options)
+		})
+	} else {
+		response, err = client.doSend(ctx, spec, options)
+	}
 
-	request, err := client.createRequest(http.MethodPost, path, queryParams, jsonData)
-	if err != nil {
+	if response != nil {
+		response.strictDecoding = options.strictDecoding
+	}
+
+	return response, err
+}
+
+func (client *Client) doSend(ctx context.Context, spec RequestSpec, options *requestOptions) (*Response, error) {
+	if err := client.validateRequest(&spec); err != nil {
 		client.logger.Error().
 			Err(err).
-			Str("method", http.MethodPost).
-			Str("url", client.baseUrl+path).
-			Msg("failed to build HTTP request")
-		return nil, nil, err
+			Str(client.logField("method"), spec.Method).
+			Func(client.logURL(client.baseUrl + spec.Path)).
+			Msg("http request validation failed")
+		return nil, err
+	}
+
+	var cacheKey string
+	if options.sink == nil && options.cacheMode != CacheBypass {
+		cacheKey = client.cacheKey(ctx, &spec)
+	}
+
+	if cached := client.cachedResponse(ctx, cacheKey, options.cacheMode); cached != nil {
+		cached.Request = &spec
+		cached.URL, _ = client.requestUrl(client.baseUrl, &spec)
+		cached.CacheHit = true
+
+		if err := client.transformResponse(ctx, cached); err != nil {
+			client.logger.Error().
+				Err(err).
+				Str(client.logField("method"), spec.Method).
+				Func(client.logURL(client.baseUrl + spec.Path)).
+				Msg("failed to transform cached HTTP response body")
+			return cached, err
+		}
+
+		return client.processResponse(cached, spec.Method, client.baseUrl+spec.Path)
 	}
 
-	client.fillRequestHeaders(request, headers)
+	if client.offline.Load() {
+		return nil, ErrOffline
+	}
 
-	var response *http.Response
+	if err := client.rateLimits.wait(ctx, &spec); err != nil {
+		return nil, err
+	}
 
-	response, err = client.getResponse(request)
+	release, waited, err := client.acquireSlot(ctx)
 	if err != nil {
-		client.logger.Error().
+		return nil, err
+	}
+
+	defer release()
+
+	ticket, err := client.circuit.allow()
+	if err != nil {
+		client.logger.Warn().
 			Err(err).
-			Str("method", request.Method).
-			Str("url", request.URL.String()).
-			Msg("failed to send HTTP request")
-		return nil, nil, err
+			Str(client.logField("method"), spec.Method).
+			Func(client.logURL(client.baseUrl + spec.Path)).
+			Msg("http request rejected by circuit breaker")
+		return nil, err
 	}
-	client.logger.Info().
-		Str("method", request.Method).
-		Str("url", request.URL.String()).
-		Int("status", response.StatusCode).
-		Msg("http request succeeded")
 
-	return getResponseBody(response, client.logger)
+	started := time.Now()
+	result, err := client.exchangeWithRetry(ctx, &spec, options, cacheKey)
+	client.recordCircuit(ctx, &spec, options, ticket, result, err)
+
+	client.recordMetrics(ctx, &spec, result, err, waited, time.Since(started))
+
+	return result, err
 }
 
-func (client *Client) SendPut(
-	path string,
-	jsonData []byte,
-	queryParams Params,
-	headers Headers,
-) ([]byte, *int, error) {
-	request, err := client.createRequest(http.MethodPut, path, queryParams, jsonData)
+func (client *Client) exchange(
+	ctx context.Context,
+	spec *RequestSpec,
+	options *requestOptions,
+	cacheKey string,
+) (*Response, error) {
+	baseUrl := client.attemptBaseUrl(ctx, spec, options)
+
+	request, err := client.createRequest(ctx, baseUrl, spec)
 	if err != nil {
+		client.releaseEndpoint(baseUrl)
 		client.logger.Error().
 			Err(err).
-			Str("method", http.MethodPut).
-			Str("url", client.baseUrl+path).
+			Str(client.logField("method"), spec.Method).
+			Func(client.logURL(baseUrl + spec.Path)).
 			Msg("failed to build HTTP request")
-		return nil, nil, err
+		return nil, err
+	}
+
+	if err = client.cooldown.wait(ctx, request.URL.Host); err != nil {
+		client.releaseEndpoint(baseUrl)
+		client.logger.Warn().
+			Err(err).
+			Str(client.logField("method"), request.Method).
+			Func(client.logURL(request.URL.String())).
+			Msg("http request held back by quota cooldown")
+		return nil, err
 	}
 
-	client.fillRequestHeaders(request, headers)
+	client.fillRequestHeaders(request, spec.Headers)
 
-	response, err := client.getResponse(request)
-	if err != nil {
+	if options.destination != "" {
+		request.Header.Set(destinationHeader, baseUrl+options.destination)
+	}
+
+	attachCookies(request, options)
+
+	if err = client.authorize(ctx, request); err != nil {
+		client.releaseEndpoint(baseUrl)
 		client.logger.Error().
 			Err(err).
-			Str("method", request.Method).
-			Str("url", request.URL.String()).
-			Msg("failed to send HTTP request")
-		return nil, nil, err
+			Str(client.logField("method"), request.Method).
+			Func(client.logURL(request.URL.String())).
+			Msg("failed to obtain http request credentials")
+		return nil, err
 	}
 
-	client.logger.Info().
-		Str("method", request.Method).
-		Str("url", request.URL.String()).
-		Int("status", response.StatusCode).
-		Msg("http request succeeded")
+	for _, name := range options.stripHeaders {
+		request.Header.Del(name)
+	}
 
-	return getResponseBody(response, client.logger)
-}
+	client.negotiateEncoding(request, options)
+	client.applyAPIVersion(request)
 
-func (client *Client) SendPatch(
-	path string,
-	jsonData []byte,
-	queryParams Params,
-	headers Headers,
-) ([]byte, *int, error) {
-	request, err := client.createRequest(http.MethodPatch, path, queryParams, jsonData)
-	if err != nil {
+	if options.contentLength > 0 && request.Body != nil {
+		request.ContentLength = options.contentLength
+	}
+
+	if err = client.limitRequestBody(request); err != nil {
+		client.releaseEndpoint(baseUrl)
 		client.logger.Error().
 			Err(err).
-			Str("method", http.MethodPatch).
-			Str("url", client.baseUrl+path).
-			Msg("failed to build HTTP request")
-		return nil, nil, err
+			Str(client.logField("method"), request.Method).
+			Func(client.logURL(request.URL.String())).
+			Msg("http request body rejected")
+		return nil, err
 	}
 
-	client.fillRequestHeaders(request, headers)
+	if err = client.runRequestHooks(request); err != nil {
+		client.releaseEndpoint(baseUrl)
+		client.logger.Error().
+			Err(err).
+			Str(client.logField("method"), request.Method).
+			Func(client.logURL(request.URL.String())).
+			Msg("http request hook failed")
+		return nil, err
+	}
 
-	response, err := client.getResponse(request)
-	if err != nil {
+	if err = client.headerLimits.check(request.Header); err != nil {
+		client.releaseEndpoint(baseUrl)
 		client.logger.Error().
 			Err(err).
-			Str("method", request.Method).
-			Str("url", request.URL.String()).
-			Msg("failed to send HTTP request")
-		return nil, nil, err
+			Str(client.logField("method"), request.Method).
+			Func(client.logURL(request.URL.String())).
+			Msg("http request headers rejected")
+		return nil, err
 	}
 
-	client.logger.Info().
-		Str("method", request.Method).
-		Str("url", request.URL.String()).
-		Int("status", response.StatusCode).
-		Msg("http request succeeded")
+	client.attachDeadline(ctx, request, options)
 
-	return getResponseBody(response, client.logger)
-}
+	nonce, created, err := client.attachNonce(request)
+	if err == nil {
+		err = client.signRequest(request, nonce, created)
+	}
 
-func (client *Client) SendDelete(path string, params Params, headers Headers) ([]byte, *int, error) {
-	request, err := client.createRequest(http.MethodDelete, path, params, nil)
 	if err != nil {
+		client.releaseEndpoint(baseUrl)
 		client.logger.Error().
 			Err(err).
-			Str("method", http.MethodDelete).
-			Str("url", client.baseUrl+path).
-			Msg("failed to build HTTP request")
-		return nil, nil, err
+			Str(client.logField("method"), request.Method).
+			Func(client.logURL(request.URL.String())).
+			Msg("failed to sign HTTP request")
+		return nil, err
+	}
+	client.throttleRequest(request)
+
+	result, err := client.roundTrip(ctx, spec, options, cacheKey, baseUrl, request)
+	client.runResponseHooks(request, result, err)
+
+	return result, err
+}
+
+func (client *Client) roundTrip(
+	ctx context.Context,
+	spec *RequestSpec,
+	options *requestOptions,
+	cacheKey string,
+	baseUrl string,
+	request *http.Request,
+) (*Response, error) {
+	request, connection, timings := client.traceConnections(request)
+	options.sent = true
+
+	response, err := client.getResponse(request, options)
+	options.staleConnection = err != nil && connection.Reused
+
+	client.reportEndpoint(baseUrl, spec, response, time.Since(timings.start), err)
+
+	if err == nil {
+		client.latency.observe(time.Since(timings.start))
 	}
 
-	client.fillRequestHeaders(request, headers)
+	client.captureRequest(request, response, connection, timings, err)
+
+	if err == nil {
+		storeCookies(response, options)
+		client.observeDeprecation(request, response)
+
+		if until, ok := client.cooldown.observe(request.URL.Host, response); ok {
+			client.logger.Warn().
+				Str(client.logField("method"), request.Method).
+				Func(client.logURL(request.URL.String())).
+				Time("until", until).
+				Msg("http quota exhausted, cooling down endpoint")
+		}
+	}
 
-	response, err := client.getResponse(request)
 	if err != nil {
+		err = classifyTimeout(ctx, err, timings, false)
 		client.logger.Error().
 			Err(err).
-			Str("method", request.Method).
-			Str("url", request.URL.String()).
+			Str(client.logField("method"), request.Method).
+			Func(client.logURL(request.URL.String())).
 			Msg("failed to send HTTP request")
-		return nil, nil, err
+		return nil, err
 	}
 
-	client.logger.Info().
-		Str("method", request.Method).
-		Str("url", request.URL.String()).
-		Int("status", response.StatusCode).
-		Msg("http request succeeded")
+	client.throttleResponse(ctx, response)
 
-	return getResponseBody(response, client.logger)
-}
+	if err = client.decodeResponse(response, options); err != nil {
+		client.logger.Error().
+			Err(err).
+			Str(client.logField("method"), request.Method).
+			Func(client.logURL(request.URL.String())).
+			Msg("failed to decode HTTP response body")
+		return nil, err
+	}
+
+	client.logSuccess(request, response, timings)
+
+	if options.sink != nil && options.streams(response) {
+		result, err := streamResponse(response, options.sink, options.keepBody, client.logger)
+		err = classifyTimeout(ctx, err, timings, true)
+		result.Request = spec
+		result.URL = response.Request.URL.String()
+		result.Endpoint = baseUrl
+		result.Connection = connectionInfo(connection, response)
+		result.Deprecation = parseDeprecation(response.Header)
+		result.Decompressed = response.Uncompressed
+
+		return result, err
+	}
 
-func (client *Client) prepareUrlWithParams(path string, dirtyParams Params) (string, error) {
-	params := url.Values{}
+	result, err := readResponse(response, client.logger)
+	err = classifyTimeout(ctx, err, timings, true)
 
-	for key, val := range dirtyParams {
-		params.Add(key, val)
+	if result == nil {
+		return nil, err
 	}
 
-	u, err := url.ParseRequestURI(client.baseUrl)
+	err = options.judge(result, err)
+
+	result.Request = spec
+	result.URL = response.Request.URL.String()
+	result.Endpoint = baseUrl
+	result.Connection = connectionInfo(connection, response)
+	result.Deprecation = parseDeprecation(response.Header)
+	result.Decompressed = response.Uncompressed
+
+	// The cache keeps the body as received; transforms run again on hits.
+	raw := *result
+	raw.Header = result.Header.Clone()
+
+	if transformErr := client.transformResponse(ctx, result); transformErr != nil {
+		client.logger.Error().
+			Err(transformErr).
+			Str(client.logField("method"), request.Method).
+			Func(client.logURL(request.URL.String())).
+			Msg("failed to transform HTTP response body")
+
+		if err == nil {
+			return result, transformErr
+		}
+	}
 
 	if err != nil {
-		return "", err
+		return result, err
 	}
 
-	u.Path = path
-	u.RawQuery = params.Encode()
+	client.storeResponse(ctx, cacheKey, &raw)
 
-	return fmt.Sprintf("%v", u), err
+	return client.processResponse(result, request.Method, request.URL.String())
 }
 
-func (client *Client) createRequest(
-	method string,
+func (client *Client) processResponse(result *Response, method, rawUrl string) (*Response, error) {
+	if result.Pagination == nil {
+		result.Pagination = client.headerPagination(result.Header)
+	}
+
+	if client.envelope != nil {
+		client.envelope.unwrap(result)
+	}
+
+	if err := client.validateResponse(result); err != nil {
+		client.logger.Warn().
+			Err(err).
+			Str(client.logField("method"), method).
+			Func(client.logURL(rawUrl)).
+			Msg("http response validation failed")
+
+		return result, err
+	}
+
+	return result, nil
+}
+
+func (client *Client) SendGet(path string, params Params, headers Headers, opts ...RequestOption) ([]byte, *int, error) {
+	return client.send(http.MethodGet, path, params, nil, headers, opts)
+}
+
+func (client *Client) SendPost(
+	path string,
+	jsonData []byte,
+	queryParams Params,
+	headers Headers,
+	opts ...RequestOption,
+) ([]byte, *int, error) {
+	return client.send(http.MethodPost, path, queryParams, jsonData, headers, opts)
+}
+
+func (client *Client) SendPut(
 	path string,
+	jsonData []byte,
 	queryParams Params,
+	headers Headers,
+	opts ...RequestOption,
+) ([]byte, *int, error) {
+	return client.send(http.MethodPut, path, queryParams, jsonData, headers, opts)
+}
+
+func (client *Client) SendPatch(
+	path string,
 	jsonData []byte,
-) (*http.Request, error) {
+	queryParams Params,
+	headers Headers,
+	opts ...RequestOption,
+) ([]byte, *int, error) {
+	return client.send(http.MethodPatch, path, queryParams, jsonData, headers, opts)
+}
+
+func (client *Client) SendDelete(path string, params Params, headers Headers, opts ...RequestOption) ([]byte, *int, error) {
+	return client.send(http.MethodDelete, path, params, nil, headers, opts)
+}
+
+func (client *Client) send(
+	method string,
+	path string,
+	params Params,
+	jsonData []byte,
+	headers Headers,
+	opts []RequestOption,
+) ([]byte, *int, error) {
+	response, err := client.Send(context.Background(), RequestSpec{
+		Method:  method,
+		Path:    path,
+		Params:  params.Multi(),
+		Headers: headers.Multi(),
+		Body:    bytes.NewReader(jsonData),
+	}, opts...)
+
+	return unwrapResponse(response, err)
+}
+
+func (client *Client) prepareUrlWithParams(baseUrl, path string, dirtyParams MultiParams) (string, error) {
+	params := dirtyParams.Values()
+	path, rawQuery, _ := strings.Cut(path, "?")
+
+	// path is already escaped, so it is parsed with the base URL rather
+	// than assigned to u.Path, which would escape it again.
+	u, err := url.ParseRequestURI(baseUrl + path)
+
+	if err != nil {
+		return "", err
+	}
+
+	u.RawQuery = client.combineQuery(rawQuery, params)
+
+	return fmt.Sprintf("%v", u), err
+}
+
+func (client *Client) createRequest(ctx context.Context, baseUrl string, spec *RequestSpec) (*http.Request, error) {
+	preparedUrl, err := client.requestUrl(baseUrl, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, spec.Method, preparedUrl, spec.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if section, ok := spec.Body.(*io.SectionReader); ok {
+		setSectionBody(request, section)
+	}
+
+	return request, nil
+}
+
+// setSectionBody gives a file section body a length and a GetBody that
+// reopens it, as net/http does for in-memory bodies.
+func setSectionBody(request *http.Request, section *io.SectionReader) {
+	start, err := section.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return
+	}
+
+	size := section.Size() - start
+
+	request.ContentLength = size
+	request.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(io.NewSectionReader(section, start, size)), nil
+	}
+}
+
+func (client *Client) requestUrl(baseUrl string, spec *RequestSpec) (string, error) {
 	var preparedUrl string
 	var err error
 
+	switch {
+	case isAbsoluteUrl(spec.Path):
+		preparedUrl, err = client.mergeUrlParams(spec.Path, spec.Params)
+	case len(spec.Params) < 1:
+		preparedUrl = baseUrl + client.versionedPath(spec.Path)
+	default:
+		preparedUrl, err = client.prepareUrlWithParams(baseUrl, client.versionedPath(spec.Path), spec.Params)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return appendRawQuery(preparedUrl, spec.OrderedParams.Encode()), nil
+}
+
+func isAbsoluteUrl(path string) bool {
+	u, err := url.Parse(path)
+
+	return err == nil && u.IsAbs() && u.Host != ""
+}
+
+func (client *Client) mergeUrlParams(rawUrl string, queryParams MultiParams) (string, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = asciiHost(u); err != nil {
+		return "", err
+	}
+
 	if len(queryParams) < 1 {
-		preparedUrl = client.baseUrl + path
-	} else {
-		preparedUrl, err = client.prepareUrlWithParams(path, queryParams)
+		return u.String(), nil
+	}
 
-		if err != nil {
-			return nil, err
-		}
+	u.RawQuery = client.combineQuery(u.RawQuery, queryParams.Values())
+
+	return u.String(), nil
+}
+
+func (client *Client) combineQuery(rawQuery string, params url.Values) string {
+	if client.preserveQueryOrder {
+		return joinRawQuery(rawQuery, params.Encode())
 	}
 
-	return http.NewRequest(method, preparedUrl, bytes.NewBuffer(jsonData))
+	merged, _ := url.ParseQuery(rawQuery)
+
+	for key, vals := range params {
+		merged[key] = vals
+	}
+
+	return merged.Encode()
+}
+
+func appendRawQuery(rawUrl, rawQuery string) string {
+	if rawQuery == "" {
+		return rawUrl
+	}
+
+	if strings.Contains(rawUrl, "?") {
+		return rawUrl + "&" + rawQuery
+	}
+
+	return rawUrl + "?" + rawQuery
 }
 
-func (client *Client) getResponse(request *http.Request) (*http.Response, error) {
+func joinRawQuery(left, right string) string {
+	if left == "" || right == "" {
+		return left + right
+	}
+
+	return left + "&" + right
+}
+
+func (client *Client) getResponse(request *http.Request, options *requestOptions) (*http.Response, error) {
+	if client.bodyReadTimeout > 0 || options.streaming {
+		return client.getIdleResponse(request)
+	}
+
 	response, err := client.httpClient.Do(request)
 
 	if err != nil {
@@ -312,6 +831,10 @@ func closeResponseBody(response *http.Response) error {
 }
 
 func getResponseBody(response *http.Response, logger *zerolog.Logger) ([]byte, *int, error) {
+	return unwrapResponse(readResponse(response, logger))
+}
+
+func readResponse(response *http.Response, logger *zerolog.Logger) (*Response, error) {
 	defer func() {
 		if err := closeResponseBody(response); err != nil {
 			logger.Warn().
@@ -320,15 +843,33 @@ func getResponseBody(response *http.Response, logger *zerolog.Logger) ([]byte, *
 		}
 	}()
 
-	if response.StatusCode >= 300 {
-		return nil, &response.StatusCode, errors.New("http request failed")
+	result := &Response{
+		StatusCode: response.StatusCode,
+		Header:     response.Header,
 	}
 
 	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return result, err
+	}
+
+	result.Body = body
+
+	if response.StatusCode >= 300 {
+		return result, ErrRequestFailed
+	}
+
+	return result, nil
+}
+
+func unwrapResponse(response *Response, err error) ([]byte, *int, error) {
+	if response == nil {
+		return nil, nil, err
+	}
 
 	if err != nil {
 		return nil, &response.StatusCode, err
 	}
 
-	return body, &response.StatusCode, nil
+	return response.Body, &response.StatusCode, nil
 }
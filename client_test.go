@@ -1,6 +1,8 @@
 package client
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -187,14 +189,24 @@ func TestNon2xx_ReturnsErrorAndNoBody(t *testing.T) {
 }
 func TestCreateRequest_InvalidBaseURL(t *testing.T) {
 	log := zerolog.Nop()
-	c, err := New("http://[::1]:namedport", nil, &log, false, "ua")
-	if err != nil {
-		t.Fatalf("New error: %v", err)
+	_, err := New("http://[::1]:namedport", nil, &log, false, "ua")
+	if !errors.Is(err, ErrInvalidBaseURL) {
+		t.Fatalf("expected ErrInvalidBaseURL, got %v", err)
 	}
+}
 
-	_, _, err = c.SendGet("/x", nil, nil)
-	if err == nil {
-		t.Fatal("expected error on invalid baseURL, got nil")
+func TestSend_RejectsAbsolutePath(t *testing.T) {
+	var hits int
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer other.Close()
+
+	c := newTestClient(t, "http://example.com")
+
+	_, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: other.URL + "/admin"})
+	if !errors.Is(err, ErrAbsolutePath) || hits != 0 {
+		t.Fatalf("err=%v hits=%d", err, hits)
 	}
 }
 
@@ -259,3 +271,52 @@ func TestGetResponseBody_CloseErrorIsLoggedNotReturned(t *testing.T) {
 		t.Fatalf("body should be empty, got: %q", string(body))
 	}
 }
+
+func TestSendVerbs_AcceptRequestOptions(t *testing.T) {
+	var gotBody, gotType string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody, gotType = string(body), r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("missing"))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	body, status, err := c.SendPost("/items", nil, nil, nil,
+		BodyWith(map[string]string{"name": "x"}, FormMarshaler{}),
+		SuccessWhen(func(r *Response) bool { return r.StatusCode == http.StatusNotFound }),
+		Timeout(time.Second),
+	)
+	if err != nil {
+		t.Fatalf("SendPost() error: %v", err)
+	}
+	if status == nil || *status != http.StatusNotFound || string(body) != "missing" {
+		t.Fatalf("status=%v body=%q", status, body)
+	}
+	if gotType != ContentTypeForm || gotBody != "name=x" {
+		t.Fatalf("content-type=%q body=%q", gotType, gotBody)
+	}
+
+	if _, _, err = c.SendGet("/items", nil, nil); !errors.Is(err, ErrRequestFailed) {
+		t.Fatalf("SendGet() err = %v, want ErrRequestFailed", err)
+	}
+}
+
+func TestSuccessWhen_FailsAcceptedStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"error":"quota"}`))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	_, status, err := c.SendGet("/", nil, nil, SuccessWhen(func(r *Response) bool {
+		return !strings.Contains(string(r.Body), `"error"`)
+	}))
+	if !errors.Is(err, ErrRequestFailed) || status == nil || *status != http.StatusOK {
+		t.Fatalf("err=%v status=%v, want ErrRequestFailed with 200", err, status)
+	}
+}
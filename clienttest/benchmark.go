@@ -0,0 +1,56 @@
+// Package clienttest holds helpers for testing and benchmarking code that
+// uses the http client.
+package clienttest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	client "gitlab.sapsan.media/ttk-go-packages/http-client"
+)
+
+// BenchmarkRequest sends spec b.N times in mode and reports the body size
+// as bytes per operation. Streamed bodies are discarded.
+func BenchmarkRequest(b *testing.B, c *client.Client, spec client.RequestSpec, mode client.BodyMode) {
+	b.Helper()
+	b.ReportAllocs()
+
+	ctx := context.Background()
+
+	for i := 0; i < b.N; i++ {
+		response, err := c.Send(ctx, spec, mode.Option(io.Discard))
+		if err != nil {
+			b.Fatalf("send: %v", err)
+		}
+
+		if i == 0 {
+			b.SetBytes(int64(len(response.Body)) + response.BytesWritten)
+		}
+	}
+}
+
+// BenchmarkPayload serves payload from a local server and runs
+// BenchmarkRequest against it with a quiet client built from opts.
+func BenchmarkPayload(b *testing.B, payload []byte, mode client.BodyMode, opts ...client.Option) {
+	b.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	logger := zerolog.Nop()
+
+	c, err := client.New(server.URL, nil, &logger, true, "", opts...)
+	if err != nil {
+		b.Fatalf("new client: %v", err)
+	}
+
+	b.ResetTimer()
+	BenchmarkRequest(b, c, client.RequestSpec{Method: http.MethodGet, Path: "/"}, mode)
+}
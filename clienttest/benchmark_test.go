@@ -0,0 +1,18 @@
+package clienttest
+
+import (
+	"bytes"
+	"testing"
+
+	client "gitlab.sapsan.media/ttk-go-packages/http-client"
+)
+
+func BenchmarkLargeResponse(b *testing.B) {
+	payload := bytes.Repeat([]byte(`{"id":1,"name":"item"},`), 1<<16)
+
+	for _, mode := range []client.BodyMode{client.Buffered, client.Streamed} {
+		b.Run(mode.String(), func(b *testing.B) {
+			BenchmarkPayload(b, payload, mode)
+		})
+	}
+}
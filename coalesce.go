@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type coalescer struct {
+	window time.Duration
+	mu     sync.Mutex
+	calls  map[string]*coalescedCall
+}
+
+type coalescedCall struct {
+	done     chan struct{}
+	response *Response
+	err      error
+	// cancelled reports that the call failed because its context ended.
+	cancelled bool
+}
+
+// WithRequestCoalescing shares one upstream call between identical GET
+// requests that are in flight or were answered less than window ago. The
+// shared call runs with the context of the request that started it; if that
+// context ends, the requests still waiting send their own call.
+func WithRequestCoalescing(window time.Duration) Option {
+	return func(client *Client) error {
+		client.coalescing = &coalescer{window: window, calls: map[string]*coalescedCall{}}
+
+		return nil
+	}
+}
+
+func (client *Client) coalescingKey(ctx context.Context, spec *RequestSpec) string {
+	return DefaultCacheKey(ctx, spec, client.requestHeaders(spec))
+}
+
+// do runs fn once per key. Waiters give up when their own context ends, and
+// start over when the shared call failed only because the context of the
+// request that started it ended.
+func (c *coalescer) do(ctx context.Context, key string, fn func() (*Response, error)) (*Response, error) {
+	for {
+		c.mu.Lock()
+
+		call, ok := c.calls[key]
+		if !ok {
+			call = &coalescedCall{done: make(chan struct{})}
+			c.calls[key] = call
+		}
+
+		c.mu.Unlock()
+
+		if !ok {
+			return c.lead(ctx, key, call, fn)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-call.done:
+		}
+
+		if !call.cancelled {
+			return call.result()
+		}
+	}
+}
+
+func (c *coalescer) lead(ctx context.Context, key string, call *coalescedCall, fn func() (*Response, error)) (*Response, error) {
+	func() {
+		defer close(call.done)
+
+		call.response, call.err = fn()
+
+		if call.err != nil && ctx.Err() != nil {
+			call.cancelled = true
+			c.forget(key, call)
+		}
+	}()
+
+	if !call.cancelled {
+		time.AfterFunc(c.window, func() { c.forget(key, call) })
+	}
+
+	return call.result()
+}
+
+func (c *coalescer) forget(key string, call *coalescedCall) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.calls[key] == call {
+		delete(c.calls, key)
+	}
+}
+
+func (call *coalescedCall) result() (*Response, error) {
+	if call.response == nil {
+		return nil, call.err
+	}
+
+	response := *call.response
+	response.Header = call.response.Header.Clone()
+
+	return &response, call.err
+}
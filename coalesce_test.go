@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithRequestCoalescing_SharesIdenticalGets(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithRequestCoalescing(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, _, err := c.SendGet("/poll", Params{"id": "1"}, nil)
+			if err != nil || string(body) != "ok" {
+				t.Errorf("body=%s err=%v", body, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if _, _, err = c.SendGet("/poll", Params{"id": "1"}, nil); err != nil {
+		t.Fatalf("SendGet error: %v", err)
+	}
+	if n := atomic.LoadInt32(&hits); n != 1 {
+		t.Fatalf("hits within window=%d", n)
+	}
+
+	if _, _, err = c.SendGet("/poll", Params{"id": "2"}, nil); err != nil {
+		t.Fatalf("SendGet error: %v", err)
+	}
+	time.Sleep(150 * time.Millisecond)
+	if _, _, err = c.SendGet("/poll", Params{"id": "1"}, nil); err != nil {
+		t.Fatalf("SendGet error: %v", err)
+	}
+	if n := atomic.LoadInt32(&hits); n != 3 {
+		t.Fatalf("hits after window=%d", n)
+	}
+}
+
+func TestWithRequestCoalescing_WaitersKeepTheirOwnContext(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(100 * time.Millisecond):
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithRequestCoalescing(time.Second))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	spec := RequestSpec{Method: http.MethodGet, Path: "/poll"}
+
+	leader, cancel := context.WithCancel(context.Background())
+	go func() {
+		_, _ = c.Send(leader, spec)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	short, cancelShort := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelShort()
+
+	started := time.Now()
+	if _, err = c.Send(short, spec); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("short waiter err=%v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 60*time.Millisecond {
+		t.Fatalf("short waiter blocked for %v", elapsed)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		response, err := c.Send(context.Background(), spec)
+		if err == nil && string(response.Body) != "ok" {
+			err = errors.New("unexpected body " + string(response.Body))
+		}
+		done <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err = <-done; err != nil {
+		t.Fatalf("waiter got the leader's error: %v", err)
+	}
+	if n := atomic.LoadInt32(&hits); n != 2 {
+		t.Fatalf("hits=%d, want 2", n)
+	}
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+const (
+	etagHeader            = "ETag"
+	lastModifiedHeader    = "Last-Modified"
+	ifNoneMatchHeader     = "If-None-Match"
+	ifModifiedSinceHeader = "If-Modified-Since"
+)
+
+// ConditionalStore remembers ETag and Last-Modified validators per request
+// URL so later requests can be made conditional without the response cache.
+type ConditionalStore struct {
+	mu      sync.Mutex
+	entries map[string]conditionalValidators
+}
+
+type conditionalValidators struct {
+	etag         string
+	lastModified string
+}
+
+func NewConditionalStore() *ConditionalStore {
+	return &ConditionalStore{entries: map[string]conditionalValidators{}}
+}
+
+// Record stores the validators of a successful response; responses without
+// any are ignored.
+func (store *ConditionalStore) Record(response *Response) {
+	if response == nil || response.Request == nil || response.StatusCode != http.StatusOK {
+		return
+	}
+
+	validators := conditionalValidators{
+		etag:         response.Header.Get(etagHeader),
+		lastModified: response.Header.Get(lastModifiedHeader),
+	}
+
+	if validators.etag == "" && validators.lastModified == "" {
+		return
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.entries[conditionalKey(response.Request)] = validators
+}
+
+// Headers returns If-None-Match and If-Modified-Since for spec, or nil when
+// nothing was recorded for it.
+func (store *ConditionalStore) Headers(spec *RequestSpec) MultiHeaders {
+	store.mu.Lock()
+	validators, ok := store.entries[conditionalKey(spec)]
+	store.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	headers := MultiHeaders{}
+
+	if validators.etag != "" {
+		headers.Set(ifNoneMatchHeader, validators.etag)
+	}
+
+	if validators.lastModified != "" {
+		headers.Set(ifModifiedSinceHeader, validators.lastModified)
+	}
+
+	return headers
+}
+
+func (store *ConditionalStore) Forget(spec *RequestSpec) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	delete(store.entries, conditionalKey(spec))
+}
+
+// SendConditional adds the stored validators to spec, sends it and records
+// the new validators. A 304 answer is returned without error; check
+// Response.NotModified.
+func (client *Client) SendConditional(
+	ctx context.Context,
+	store *ConditionalStore,
+	spec RequestSpec,
+	opts ...RequestOption,
+) (*Response, error) {
+	if conditional := store.Headers(&spec); conditional != nil {
+		opts = append([]RequestOption{withHeaders(conditional)}, opts...)
+	}
+
+	response, err := client.Send(ctx, spec, opts...)
+
+	if response.NotModified() && errors.Is(err, ErrRequestFailed) {
+		return response, nil
+	}
+
+	if err == nil {
+		store.Record(response)
+	}
+
+	return response, err
+}
+
+func (response *Response) NotModified() bool {
+	return response != nil && response.StatusCode == http.StatusNotModified
+}
+
+func conditionalKey(spec *RequestSpec) string {
+	return appendRawQuery(spec.Path+"?"+spec.Params.Values().Encode(), spec.OrderedParams.Encode())
+}
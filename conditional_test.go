@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendConditional(t *testing.T) {
+	var gotIfNoneMatch, gotIfModifiedSince string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotIfNoneMatch = r.Header.Get("If-None-Match")
+		gotIfModifiedSince = r.Header.Get("If-Modified-Since")
+		if gotIfNoneMatch == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		_, _ = w.Write([]byte("body"))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	store := NewConditionalStore()
+	spec := RequestSpec{Method: http.MethodGet, Path: "/doc", Params: MultiParams{"v": {"1"}}}
+
+	resp, err := c.SendConditional(context.Background(), store, spec)
+	if err != nil || resp.NotModified() || gotIfNoneMatch != "" {
+		t.Fatalf("first: err=%v status=%d inm=%q", err, resp.StatusCode, gotIfNoneMatch)
+	}
+
+	resp, err = c.SendConditional(context.Background(), store, spec)
+	if err != nil || !resp.NotModified() {
+		t.Fatalf("second: err=%v status=%d", err, resp.StatusCode)
+	}
+	if gotIfModifiedSince != "Wed, 21 Oct 2015 07:28:00 GMT" {
+		t.Errorf("If-Modified-Since=%q", gotIfModifiedSince)
+	}
+
+	other := RequestSpec{Method: http.MethodGet, Path: "/doc", Params: MultiParams{"v": {"2"}}}
+	if store.Headers(&other) != nil {
+		t.Error("validators must be stored per URL")
+	}
+
+	store.Forget(&spec)
+	if store.Headers(&spec) != nil {
+		t.Error("Forget did not remove validators")
+	}
+}
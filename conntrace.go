@@ -0,0 +1,93 @@
+package client
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+)
+
+// ConnectionInfo describes the connection that carried a request.
+type ConnectionInfo struct {
+	RemoteAddr string
+	Reused     bool
+	WasIdle    bool
+	IdleTime   time.Duration
+	// Protocol is the response protocol, such as "HTTP/1.1" or "HTTP/2.0".
+	Protocol string
+}
+
+// WithConnectionLogging logs the resolved addresses, the remote address and
+// whether the connection was reused for every request.
+func WithConnectionLogging() Option {
+	return func(client *Client) error {
+		client.logConnections = true
+
+		return nil
+	}
+}
+
+func (client *Client) traceConnections(request *http.Request) (*http.Request, *ConnectionInfo, *requestTimings) {
+	var resolved []string
+
+	connection := &ConnectionInfo{}
+	timings := &requestTimings{start: time.Now()}
+
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { timings.mark(&timings.dnsStart) },
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			timings.mark(&timings.dnsDone)
+
+			for _, addr := range info.Addrs {
+				resolved = append(resolved, addr.String())
+			}
+		},
+		ConnectStart:         func(string, string) { timings.mark(&timings.connectStart) },
+		ConnectDone:          func(string, string, error) { timings.mark(&timings.connectDone) },
+		TLSHandshakeStart:    func() { timings.mark(&timings.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { timings.mark(&timings.tlsDone) },
+		GotFirstResponseByte: func() { timings.mark(&timings.firstByte) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			timings.mark(&timings.gotConn)
+			connection.RemoteAddr = info.Conn.RemoteAddr().String()
+			connection.Reused = info.Reused
+			connection.WasIdle = info.WasIdle
+			connection.IdleTime = info.IdleTime
+
+			if !client.logConnections {
+				return
+			}
+
+			client.logger.Info().
+				Str(client.logField("method"), request.Method).
+				Func(client.logURL(request.URL.String())).
+				Strs("resolved", resolved).
+				Str("remote_addr", connection.RemoteAddr).
+				Str("alpn", negotiatedProtocol(info)).
+				Bool("reused", info.Reused).
+				Bool("was_idle", info.WasIdle).
+				Dur("idle_time", info.IdleTime).
+				Msg("http connection acquired")
+		},
+	}
+
+	return request.WithContext(httptrace.WithClientTrace(request.Context(), trace)), connection, timings
+}
+
+func connectionInfo(connection *ConnectionInfo, response *http.Response) *ConnectionInfo {
+	if connection.RemoteAddr == "" {
+		return nil
+	}
+
+	connection.Protocol = response.Proto
+
+	return connection
+}
+
+func negotiatedProtocol(info httptrace.GotConnInfo) string {
+	if conn, ok := info.Conn.(*tls.Conn); ok {
+		return conn.ConnectionState().NegotiatedProtocol
+	}
+
+	return ""
+}
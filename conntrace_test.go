@@ -0,0 +1,104 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithConnectionLogging_LogsReuse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	log := zerolog.New(&buf)
+	c, err := New(srv.URL, nil, &log, false, "ua", WithConnectionLogging())
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, _, err = c.SendGet("/x", nil, nil); err != nil {
+			t.Fatalf("SendGet error: %v", err)
+		}
+	}
+
+	var reused []bool
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err = json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("bad log line %q", line)
+		}
+		if entry["message"] == "http connection acquired" {
+			if entry["remote_addr"] == "" {
+				t.Fatalf("missing remote_addr: %v", entry)
+			}
+			reused = append(reused, entry["reused"].(bool))
+		}
+	}
+	if len(reused) != 2 || reused[0] || !reused[1] {
+		t.Fatalf("reused=%v", reused)
+	}
+}
+
+func TestResponse_ConnectionInfo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	var infos []*ConnectionInfo
+	for i := 0; i < 2; i++ {
+		resp, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"})
+		if err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+		infos = append(infos, resp.Connection)
+	}
+
+	if infos[0] == nil || infos[1] == nil {
+		t.Fatal("missing connection info")
+	}
+	if infos[0].Reused || !infos[1].Reused || !infos[1].WasIdle {
+		t.Errorf("first=%+v second=%+v", infos[0], infos[1])
+	}
+	if infos[0].Protocol != "HTTP/1.1" || infos[0].RemoteAddr != srv.Listener.Addr().String() {
+		t.Errorf("first=%+v", infos[0])
+	}
+}
+
+func TestWithDialVeto_BlocksResolvedIP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	baseURL, _ := localhostURL(t, srv)
+
+	var seenHost string
+	log := zerolog.Nop()
+	c, err := New(baseURL, nil, &log, false, "ua", WithDialVeto(func(host string, ip net.IP) error {
+		seenHost = host
+		if ip.IsLoopback() {
+			return errors.New("loopback is not allowed")
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	_, _, err = c.SendGet("/x", nil, nil)
+	if !errors.Is(err, ErrConnectionVetoed) {
+		t.Fatalf("expected ErrConnectionVetoed, got %v", err)
+	}
+	if seenHost != "localhost" {
+		t.Fatalf("veto host=%q", seenHost)
+	}
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+const defaultConsistencyHeader = "X-Consistency-Token"
+
+type consistencyContextKey struct{}
+
+// ConsistencyScope holds the consistency tokens of one sequence of calls.
+type ConsistencyScope struct {
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+// WithReadYourWrites captures the given headers (X-Consistency-Token by
+// default) from responses to writes and sends them with later GET, HEAD
+// and OPTIONS requests made with the same scope, see WithConsistencyScope.
+func WithReadYourWrites(headers ...string) Option {
+	return func(client *Client) error {
+		if len(headers) == 0 {
+			headers = []string{defaultConsistencyHeader}
+		}
+
+		client.consistencyHeaders = append(client.consistencyHeaders, headers...)
+
+		return nil
+	}
+}
+
+// WithConsistencyScope returns a context whose requests share consistency
+// tokens. Requests without a scope neither record nor replay tokens.
+func WithConsistencyScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, consistencyContextKey{}, &ConsistencyScope{})
+}
+
+func consistencyScopeFrom(ctx context.Context) *ConsistencyScope {
+	scope, _ := ctx.Value(consistencyContextKey{}).(*ConsistencyScope)
+
+	return scope
+}
+
+func (client *Client) applyConsistency(ctx context.Context, spec *RequestSpec) {
+	if len(client.consistencyHeaders) == 0 || !isReadMethod(spec.Method) {
+		return
+	}
+
+	scope := consistencyScopeFrom(ctx)
+	if scope == nil {
+		return
+	}
+
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+
+	if len(scope.tokens) == 0 {
+		return
+	}
+
+	spec.Headers = spec.Headers.Clone()
+	if spec.Headers == nil {
+		spec.Headers = MultiHeaders{}
+	}
+
+	for name, token := range scope.tokens {
+		spec.Headers.Set(name, token)
+	}
+}
+
+func (client *Client) recordConsistency(ctx context.Context, method string, response *Response) {
+	if len(client.consistencyHeaders) == 0 || response == nil || isReadMethod(method) {
+		return
+	}
+
+	scope := consistencyScopeFrom(ctx)
+	if scope == nil {
+		return
+	}
+
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+
+	for _, name := range client.consistencyHeaders {
+		token := response.Header.Get(name)
+		if token == "" {
+			continue
+		}
+
+		if scope.tokens == nil {
+			scope.tokens = map[string]string{}
+		}
+
+		scope.tokens[name] = token
+	}
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
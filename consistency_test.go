@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestReadYourWrites_ReplaysTokenWithinScope(t *testing.T) {
+	var lastToken string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.Header().Set(defaultConsistencyHeader, "lsn-42")
+			return
+		}
+		lastToken = r.Header.Get(defaultConsistencyHeader)
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithReadYourWrites())
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	ctx := WithConsistencyScope(context.Background())
+	if _, err = c.Send(ctx, RequestSpec{Method: http.MethodPost, Path: "/orders"}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if _, err = c.Send(ctx, RequestSpec{Method: http.MethodGet, Path: "/orders"}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if lastToken != "lsn-42" {
+		t.Fatalf("token=%q", lastToken)
+	}
+
+	if _, err = c.Send(WithConsistencyScope(context.Background()), RequestSpec{Method: http.MethodGet, Path: "/orders"}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if lastToken != "" {
+		t.Fatalf("token leaked into another scope: %q", lastToken)
+	}
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultQuotaCooldown = time.Second
+	retryAfterHeader     = "Retry-After"
+	rateLimitReset       = "RateLimit-Reset"
+	xRateLimitReset      = "X-RateLimit-Reset"
+
+	// resetEpochThreshold separates epoch timestamps from delta seconds in
+	// X-RateLimit-Reset, which APIs use both ways.
+	resetEpochThreshold = 1_000_000_000
+)
+
+var ErrQuotaCooldown = errors.New("endpoint is cooling down after 429")
+
+// QuotaCooldown holds requests to a host that answered 429 until the reset
+// time it advertised (Retry-After, RateLimit-Reset or X-RateLimit-Reset,
+// else Default). Requests wait if the remaining cooldown fits in MaxWait and
+// fail fast with ErrQuotaCooldown otherwise.
+type QuotaCooldown struct {
+	Default time.Duration
+	MaxWait time.Duration
+}
+
+type cooldownTracker struct {
+	config QuotaCooldown
+	mu     sync.Mutex
+	until  map[string]time.Time
+}
+
+func WithQuotaCooldown(config QuotaCooldown) Option {
+	return func(client *Client) error {
+		if config.Default <= 0 {
+			config.Default = defaultQuotaCooldown
+		}
+
+		client.cooldown = &cooldownTracker{config: config, until: map[string]time.Time{}}
+
+		return nil
+	}
+}
+
+func (tracker *cooldownTracker) wait(ctx context.Context, host string) error {
+	if tracker == nil {
+		return nil
+	}
+
+	tracker.mu.Lock()
+	until, ok := tracker.until[host]
+	tracker.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	remaining := time.Until(until)
+	if remaining <= 0 {
+		return nil
+	}
+
+	if remaining > tracker.config.MaxWait {
+		return fmt.Errorf("%w: %s until %s", ErrQuotaCooldown, host, until.Format(time.RFC3339))
+	}
+
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (tracker *cooldownTracker) observe(host string, response *http.Response) (time.Time, bool) {
+	if tracker == nil || response.StatusCode != http.StatusTooManyRequests {
+		return time.Time{}, false
+	}
+
+	until := time.Now().Add(quotaReset(response.Header, tracker.config.Default))
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	if until.After(tracker.until[host]) {
+		tracker.until[host] = until
+	}
+
+	return until, true
+}
+
+func quotaReset(header http.Header, fallback time.Duration) time.Duration {
+	if value := header.Get(retryAfterHeader); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+
+		if at, err := http.ParseTime(value); err == nil {
+			return time.Until(at)
+		}
+	}
+
+	for _, name := range []string{rateLimitReset, xRateLimitReset} {
+		seconds, err := strconv.ParseInt(header.Get(name), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if seconds > resetEpochThreshold {
+			return time.Until(time.Unix(seconds, 0))
+		}
+
+		return time.Duration(seconds) * time.Second
+	}
+
+	return fallback
+}
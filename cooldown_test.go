@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithQuotaCooldown(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithQuotaCooldown(QuotaCooldown{MaxWait: time.Second}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	spec := RequestSpec{Method: http.MethodGet, Path: "/"}
+	if _, err = c.Send(context.Background(), spec); !errors.Is(err, ErrRequestFailed) {
+		t.Fatalf("expected 429 failure, got %v", err)
+	}
+
+	if _, err = c.Send(context.Background(), spec); !errors.Is(err, ErrQuotaCooldown) {
+		t.Fatalf("expected ErrQuotaCooldown, got %v", err)
+	}
+	if atomic.LoadInt32(&hits) != 1 {
+		t.Fatalf("request reached the server during cooldown, hits=%d", hits)
+	}
+}
+
+func TestQuotaReset(t *testing.T) {
+	epoch := time.Now().Add(30 * time.Second).Unix()
+
+	cases := []struct {
+		header http.Header
+		min    time.Duration
+		max    time.Duration
+	}{
+		{http.Header{"Retry-After": {"5"}}, 5 * time.Second, 5 * time.Second},
+		{http.Header{"Ratelimit-Reset": {"7"}}, 7 * time.Second, 7 * time.Second},
+		{http.Header{"X-Ratelimit-Reset": {strconv.FormatInt(epoch, 10)}}, 28 * time.Second, 31 * time.Second},
+		{http.Header{}, time.Second, time.Second},
+	}
+
+	for i, tc := range cases {
+		got := quotaReset(tc.header, time.Second)
+		if got < tc.min || got > tc.max {
+			t.Errorf("case %d: got %v, want [%v, %v]", i, got, tc.min, tc.max)
+		}
+	}
+}
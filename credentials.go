@@ -0,0 +1,230 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCredentialCacheTTL = 5 * time.Minute
+	metadataTimeout           = 2 * time.Second
+	metadataMaxBody           = 64 << 10
+)
+
+var ErrNoCredentials = errors.New("no credentials found")
+
+type envToken string
+
+// EnvToken reads the token from the environment variable name.
+func EnvToken(name string) TokenSource {
+	return envToken(name)
+}
+
+func (name envToken) Token(context.Context) (Token, error) {
+	value := os.Getenv(string(name))
+	if value == "" {
+		return Token{}, fmt.Errorf("%w: %s is not set", ErrNoCredentials, string(name))
+	}
+
+	return Token{Value: value}, nil
+}
+
+func (name envToken) String() string { return "env:" + string(name) }
+
+type fileToken string
+
+// FileToken reads the token from the file at path, trimming whitespace, so
+// mounted secrets can be rotated in place.
+func FileToken(path string) TokenSource {
+	return fileToken(path)
+}
+
+func (path fileToken) Token(context.Context) (Token, error) {
+	data, err := os.ReadFile(string(path))
+	if err != nil {
+		return Token{}, err
+	}
+
+	value := strings.TrimSpace(string(data))
+	if value == "" {
+		return Token{}, fmt.Errorf("%w: %s is empty", ErrNoCredentials, string(path))
+	}
+
+	return Token{Value: value}, nil
+}
+
+func (path fileToken) String() string { return "file:" + string(path) }
+
+type metadataToken struct {
+	url     string
+	headers MultiHeaders
+	client  *http.Client
+}
+
+// MetadataToken fetches the token from a metadata service with GET. A JSON
+// body with "access_token" (or "token") and "expires_in" seconds is
+// understood, anything else is used as the token verbatim.
+func MetadataToken(url string, headers MultiHeaders) TokenSource {
+	return &metadataToken{url: url, headers: headers, client: &http.Client{Timeout: metadataTimeout}}
+}
+
+func (source *metadataToken) Token(ctx context.Context) (Token, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, source.url, nil)
+	if err != nil {
+		return Token{}, err
+	}
+
+	request.Header = source.headers.Header()
+
+	response, err := source.client.Do(request)
+	if err != nil {
+		return Token{}, err
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, metadataMaxBody))
+	if err != nil {
+		return Token{}, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return Token{}, fmt.Errorf("%w: metadata service answered %d", ErrNoCredentials, response.StatusCode)
+	}
+
+	var payload struct {
+		AccessToken string `json:"access_token"`
+		Token       string `json:"token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+
+	if json.Unmarshal(body, &payload) != nil {
+		return Token{Value: strings.TrimSpace(string(body))}, nil
+	}
+
+	token := Token{Value: payload.AccessToken}
+	if token.Value == "" {
+		token.Value = payload.Token
+	}
+
+	if payload.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(payload.ExpiresIn) * time.Second)
+	}
+
+	if token.Value == "" {
+		return Token{}, fmt.Errorf("%w: metadata response has no token", ErrNoCredentials)
+	}
+
+	return token, nil
+}
+
+func (source *metadataToken) String() string { return "metadata:" + source.url }
+
+// CredentialStatus reports the outcome of the last attempt of one source of
+// a ChainedTokenSource.
+type CredentialStatus struct {
+	Source      string
+	Healthy     bool
+	LastError   error
+	LastSuccess time.Time
+	// Active marks the source the cached token came from.
+	Active bool
+}
+
+// ChainedTokenSource tries its sources in order and caches the first token
+// found, until it expires or for five minutes if it has no expiry.
+type ChainedTokenSource struct {
+	sources []TokenSource
+
+	mu     sync.Mutex
+	token  Token
+	cached time.Time
+	active int
+	status []CredentialStatus
+}
+
+// ChainTokens chains sources, typically EnvToken, FileToken, MetadataToken
+// and StaticToken in that order. The chain is itself a TokenSource.
+func ChainTokens(sources ...TokenSource) *ChainedTokenSource {
+	status := make([]CredentialStatus, len(sources))
+
+	for i, source := range sources {
+		status[i].Source = fmt.Sprint(source)
+	}
+
+	return &ChainedTokenSource{sources: sources, status: status, active: -1}
+}
+
+func (chain *ChainedTokenSource) Token(ctx context.Context) (Token, error) {
+	chain.mu.Lock()
+	defer chain.mu.Unlock()
+
+	now := time.Now()
+
+	if chain.active >= 0 && chain.fresh(now) {
+		return chain.token, nil
+	}
+
+	var errs []error
+
+	for i, source := range chain.sources {
+		token, err := source.Token(ctx)
+
+		chain.status[i].Healthy = err == nil
+		chain.status[i].LastError = err
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", chain.status[i].Source, err))
+			continue
+		}
+
+		chain.status[i].LastSuccess = now
+		chain.token, chain.cached = token, now
+		chain.setActive(i)
+
+		return token, nil
+	}
+
+	chain.setActive(-1)
+
+	return Token{}, errors.Join(append([]error{ErrNoCredentials}, errs...)...)
+}
+
+// Status reports the health of every source in the chain.
+func (chain *ChainedTokenSource) Status() []CredentialStatus {
+	chain.mu.Lock()
+	defer chain.mu.Unlock()
+
+	return append([]CredentialStatus(nil), chain.status...)
+}
+
+// Invalidate drops the cached token, e.g. after the server rejected it.
+func (chain *ChainedTokenSource) Invalidate() {
+	chain.mu.Lock()
+	defer chain.mu.Unlock()
+
+	chain.setActive(-1)
+}
+
+func (chain *ChainedTokenSource) fresh(now time.Time) bool {
+	if !chain.token.Expiry.IsZero() {
+		return !chain.token.expired(now)
+	}
+
+	return now.Sub(chain.cached) < defaultCredentialCacheTTL
+}
+
+func (chain *ChainedTokenSource) setActive(active int) {
+	chain.active = active
+
+	for i := range chain.status {
+		chain.status[i].Active = i == active
+	}
+}
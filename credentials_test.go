@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChainTokens_FallsThroughAndReportsHealth(t *testing.T) {
+	var metadataCalls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		metadataCalls++
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"from-metadata","expires_in":3600}`))
+	}))
+	defer srv.Close()
+
+	chain := ChainTokens(
+		EnvToken("HTTP_CLIENT_TEST_TOKEN_UNSET"),
+		FileToken(filepath.Join(t.TempDir(), "missing")),
+		MetadataToken(srv.URL, MultiHeaders{"Metadata-Flavor": {"Google"}}),
+		StaticToken("fallback"),
+	)
+
+	for i := 0; i < 2; i++ {
+		token, err := chain.Token(context.Background())
+		if err != nil {
+			t.Fatalf("Token error: %v", err)
+		}
+		if token.Value != "from-metadata" || token.Expiry.IsZero() {
+			t.Fatalf("token=%+v", token)
+		}
+	}
+	if metadataCalls != 1 {
+		t.Fatalf("token not cached, metadata calls=%d", metadataCalls)
+	}
+
+	status := chain.Status()
+	if status[0].Healthy || status[1].Healthy || !status[2].Healthy || !status[2].Active || status[3].Active {
+		t.Fatalf("status=%+v", status)
+	}
+	if status[0].Source != "env:HTTP_CLIENT_TEST_TOKEN_UNSET" || !errors.Is(status[0].LastError, ErrNoCredentials) {
+		t.Fatalf("env status=%+v", status[0])
+	}
+}
+
+func TestChainTokens_FileAndExhausted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+
+	token, err := ChainTokens(EnvToken("HTTP_CLIENT_TEST_TOKEN_UNSET"), FileToken(path)).Token(context.Background())
+	if err != nil || token.Value != "from-file" {
+		t.Fatalf("token=%+v err=%v", token, err)
+	}
+
+	if _, err = ChainTokens(EnvToken("HTTP_CLIENT_TEST_TOKEN_UNSET")).Token(context.Background()); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("expected ErrNoCredentials, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+)
+
+const defaultCSRFHeader = "X-CSRF-Token"
+
+var ErrCSRFTokenMissing = errors.New("csrf token not found")
+
+// CSRFConfig describes how a Session obtains and sends a CSRF token.
+type CSRFConfig struct {
+	// Path is fetched with GET to obtain the token.
+	Path string
+	// Cookie names the cookie holding the token, such as "XSRF-TOKEN". When
+	// empty the token is read from the ResponseHeader of the Path response.
+	Cookie         string
+	ResponseHeader string
+	// Header carries the token on unsafe requests, X-CSRF-Token by default.
+	Header string
+	// FormField, when set, also adds the token to urlencoded bodies.
+	FormField string
+}
+
+// WithCSRF fetches a CSRF token before the first request with an unsafe
+// method and attaches it to every unsafe request of the session. A 403
+// response drops the token so the next unsafe request fetches a new one.
+func (session *Session) WithCSRF(config CSRFConfig) *Session {
+	if config.Header == "" {
+		config.Header = defaultCSRFHeader
+	}
+
+	if config.ResponseHeader == "" {
+		config.ResponseHeader = config.Header
+	}
+
+	session.state.mu.Lock()
+	session.state.csrf = &config
+	session.state.csrfToken = ""
+	session.state.mu.Unlock()
+
+	return session
+}
+
+func (session *Session) applyCSRF(spec *RequestSpec, opts []RequestOption) ([]RequestOption, error) {
+	session.state.mu.Lock()
+	config, token := session.state.csrf, session.state.csrfToken
+	session.state.mu.Unlock()
+
+	if config == nil || isReadMethod(spec.Method) {
+		return opts, nil
+	}
+
+	if token == "" {
+		var err error
+		if token, err = session.fetchCSRFToken(config); err != nil {
+			return opts, err
+		}
+	}
+
+	spec.Headers.Set(config.Header, token)
+
+	if config.FormField != "" {
+		opts = append(opts, FormField(config.FormField, token))
+	}
+
+	return opts, nil
+}
+
+func (session *Session) fetchCSRFToken(config *CSRFConfig) (string, error) {
+	response, err := session.Send(RequestSpec{Method: http.MethodGet, Path: config.Path})
+	if err != nil {
+		return "", err
+	}
+
+	var token string
+
+	if config.Cookie != "" {
+		for _, cookie := range session.Cookies(response.URL) {
+			if cookie.Name == config.Cookie {
+				token = cookie.Value
+			}
+		}
+	} else {
+		token = response.Header.Get(config.ResponseHeader)
+	}
+
+	if token == "" {
+		return "", ErrCSRFTokenMissing
+	}
+
+	session.state.mu.Lock()
+	session.state.csrfToken = token
+	session.state.mu.Unlock()
+
+	return token, nil
+}
+
+func (session *Session) observeCSRF(response *Response) {
+	if response == nil || response.StatusCode != http.StatusForbidden {
+		return
+	}
+
+	session.state.mu.Lock()
+	session.state.csrfToken = ""
+	session.state.mu.Unlock()
+}
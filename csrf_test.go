@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSessionCSRF_BootstrapsAndAttachesToken(t *testing.T) {
+	var fetches int32
+	var gotHeader, gotField, gotCookie string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/csrf":
+			atomic.AddInt32(&fetches, 1)
+			http.SetCookie(w, &http.Cookie{Name: "XSRF-TOKEN", Value: "t1", Path: "/"})
+		case "/comments":
+			gotHeader = r.Header.Get("X-XSRF-Token")
+			gotCookie = r.Header.Get("Cookie")
+			b, _ := io.ReadAll(r.Body)
+			values, _ := url.ParseQuery(string(b))
+			gotField = values.Get("_csrf")
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	session := c.NewSession(context.Background()).WithCSRF(CSRFConfig{
+		Path:      "/csrf",
+		Cookie:    "XSRF-TOKEN",
+		Header:    "X-XSRF-Token",
+		FormField: "_csrf",
+	})
+
+	if _, err := session.Send(RequestSpec{Method: http.MethodGet, Path: "/comments"}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if atomic.LoadInt32(&fetches) != 0 || gotHeader != "" {
+		t.Fatalf("safe request fetched token: fetches=%d header=%q", fetches, gotHeader)
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := session.Send(RequestSpec{Method: http.MethodPost, Path: "/comments"}, FormBody(url.Values{"text": {"hi"}}))
+		if err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+	}
+	if atomic.LoadInt32(&fetches) != 1 {
+		t.Fatalf("fetches=%d", fetches)
+	}
+	if gotHeader != "t1" || gotField != "t1" || gotCookie != "XSRF-TOKEN=t1" {
+		t.Fatalf("header=%q field=%q cookie=%q", gotHeader, gotField, gotCookie)
+	}
+}
@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultDeadlineHeader = "X-Request-Timeout"
+
+	// grpcTimeoutDigits is the most digits grpc-timeout allows.
+	grpcTimeoutDigits = 8
+)
+
+type DeadlineFormat int
+
+const (
+	// DeadlineMilliseconds writes whole milliseconds, such as "1500".
+	DeadlineMilliseconds DeadlineFormat = iota
+	// DeadlineSeconds writes seconds with millisecond precision, such as "1.5".
+	DeadlineSeconds
+	// DeadlineGRPC writes the grpc-timeout form, such as "1500m".
+	DeadlineGRPC
+)
+
+type latencyTracker struct {
+	mu       sync.Mutex
+	typical  time.Duration
+	observed bool
+}
+
+type deadlineHeader struct {
+	name   string
+	format DeadlineFormat
+}
+
+// WithDeadlineHeader tells upstream services how much of the caller's budget
+// is left. Every attempt carries the time until the context deadline or the
+// client timeout, whichever comes first, in header name; X-Request-Timeout
+// when empty. Requests without either limit are sent without it.
+func WithDeadlineHeader(name string, format DeadlineFormat) Option {
+	return func(client *Client) error {
+		client.deadlineHeader = &deadlineHeader{name: fieldOrDefault(name, defaultDeadlineHeader), format: format}
+
+		return nil
+	}
+}
+
+func (client *Client) attachDeadline(ctx context.Context, request *http.Request, options *requestOptions) {
+	if client.deadlineHeader == nil {
+		return
+	}
+
+	var remaining time.Duration
+	if !options.streaming {
+		remaining = client.httpClient.Timeout
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if untilDeadline := time.Until(deadline); remaining <= 0 || untilDeadline < remaining {
+			remaining = untilDeadline
+		}
+	}
+
+	if remaining <= 0 {
+		return
+	}
+
+	request.Header.Set(client.deadlineHeader.name, client.deadlineHeader.format.encode(remaining))
+}
+
+func (format DeadlineFormat) encode(remaining time.Duration) string {
+	switch format {
+	case DeadlineSeconds:
+		return strconv.FormatFloat(float64(remaining.Milliseconds())/1000, 'f', -1, 64)
+	case DeadlineGRPC:
+		return grpcTimeout(remaining)
+	default:
+		return strconv.FormatInt(remaining.Milliseconds(), 10)
+	}
+}
+
+func grpcTimeout(remaining time.Duration) string {
+	units := []struct {
+		suffix string
+		size   time.Duration
+	}{
+		{"n", time.Nanosecond},
+		{"u", time.Microsecond},
+		{"m", time.Millisecond},
+		{"S", time.Second},
+		{"M", time.Minute},
+		{"H", time.Hour},
+	}
+
+	for _, unit := range units {
+		value := strconv.FormatInt(int64(remaining/unit.size), 10)
+		if len(value) <= grpcTimeoutDigits {
+			return value + unit.suffix
+		}
+	}
+
+	return strconv.FormatInt(int64(remaining/time.Hour), 10) + "H"
+}
+
+// Timeout bounds the whole request, retries included. When ctx already has
+// an earlier deadline that one wins.
+func Timeout(timeout time.Duration) RequestOption {
+	return func(options *requestOptions) {
+		options.timeout = timeout
+	}
+}
+
+// clampDeadline applies the per-request timeout and warns when the time
+// left is below the typical upstream latency, which usually means a caller
+// further up gave this request too little budget.
+func (client *Client) clampDeadline(
+	ctx context.Context,
+	spec *RequestSpec,
+	options *requestOptions,
+) (context.Context, context.CancelFunc) {
+	cancel := context.CancelFunc(func() {})
+
+	if options.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, options.timeout)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return ctx, cancel
+	}
+
+	remaining := time.Until(deadline)
+
+	if typical, observed := client.latency.get(); observed && remaining < typical {
+		client.logger.Warn().
+			Str(client.logField("method"), spec.Method).
+			Func(client.logURL(client.baseUrl+spec.Path)).
+			Dur("remaining", remaining).
+			Dur("typical_latency", typical).
+			Msg("request deadline is shorter than typical upstream latency")
+	}
+
+	return ctx, cancel
+}
+
+func (tracker *latencyTracker) observe(latency time.Duration) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	if !tracker.observed {
+		tracker.typical = latency
+		tracker.observed = true
+
+		return
+	}
+
+	tracker.typical += time.Duration(healthDecay * float64(latency-tracker.typical))
+}
+
+func (tracker *latencyTracker) get() (time.Duration, bool) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	return tracker.typical, tracker.observed
+}
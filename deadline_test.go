@@ -0,0 +1,143 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithDeadlineHeader_UsesContextDeadline(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("X-Request-Timeout")
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithDeadlineHeader("", DeadlineMilliseconds))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if _, err = c.Send(ctx, RequestSpec{Method: http.MethodGet, Path: "/"}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	ms, err := strconv.Atoi(got)
+	if err != nil || ms <= 1500 || ms > 2000 {
+		t.Fatalf("header=%q", got)
+	}
+}
+
+func TestWithDeadlineHeader_FallsBackToClientTimeout(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Grpc-Timeout")
+	}))
+	defer srv.Close()
+
+	timeout := 5
+	log := zerolog.Nop()
+	c, err := New(srv.URL, &timeout, &log, false, "ua", WithDeadlineHeader("grpc-timeout", DeadlineGRPC))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	if got != "5000000u" {
+		t.Fatalf("header=%q", got)
+	}
+}
+
+func TestDeadlineFormat_Encode(t *testing.T) {
+	cases := []struct {
+		format    DeadlineFormat
+		remaining time.Duration
+		want      string
+	}{
+		{DeadlineMilliseconds, 1500 * time.Millisecond, "1500"},
+		{DeadlineSeconds, 1500 * time.Millisecond, "1.5"},
+		{DeadlineGRPC, 50 * time.Millisecond, "50000000n"},
+		{DeadlineGRPC, 3 * time.Hour, "10800000m"},
+	}
+
+	for _, tc := range cases {
+		if got := tc.format.encode(tc.remaining); got != tc.want {
+			t.Fatalf("encode(%v)=%q, want %q", tc.remaining, got, tc.want)
+		}
+	}
+}
+
+func TestTimeout_ClampsToEarlierDeadline(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("X-Request-Timeout"))
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithDeadlineHeader("", DeadlineMilliseconds))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err = c.Send(ctx, RequestSpec{Method: http.MethodGet, Path: "/"}, Timeout(time.Second)); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	shortCtx, shortCancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer shortCancel()
+
+	if _, err = c.Send(shortCtx, RequestSpec{Method: http.MethodGet, Path: "/"}, Timeout(time.Minute)); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	first, _ := strconv.Atoi(got[0])
+	second, _ := strconv.Atoi(got[1])
+	if first <= 500 || first > 1000 || second <= 0 || second > 500 {
+		t.Fatalf("headers=%q", got)
+	}
+}
+
+func TestTimeout_WarnsBelowTypicalLatency(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	log := zerolog.New(&buf)
+	c, err := New(srv.URL, nil, &log, false, "ua", WithSuccessLogSampling(0))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("unexpected log: %s", buf.String())
+	}
+
+	_, _ = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}, Timeout(10*time.Millisecond))
+
+	if !strings.Contains(buf.String(), "request deadline is shorter than typical upstream latency") {
+		t.Fatalf("missing warning: %s", buf.String())
+	}
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const warningHeader = "Warning"
+
+var warningPattern = regexp.MustCompile(`(\d{3})\s+(\S+)\s+"((?:[^"\\]|\\.)*)"`)
+
+// DeprecationNotice collects what the server said about the lifetime of the
+// API it served: the Deprecation (RFC 9745), Sunset (RFC 8594) and Warning
+// headers.
+type DeprecationNotice struct {
+	Deprecated bool
+	// DeprecatedAt is zero when the server flagged deprecation without a
+	// date.
+	DeprecatedAt time.Time
+	Sunset       time.Time
+	Warnings     []Warning
+}
+
+type Warning struct {
+	Code  int
+	Agent string
+	Text  string
+}
+
+func parseDeprecation(header http.Header) *DeprecationNotice {
+	notice := &DeprecationNotice{}
+
+	if value := strings.TrimSpace(header.Get(deprecationHeader)); value != "" {
+		notice.Deprecated = !strings.EqualFold(value, "false") && value != "?0"
+
+		if seconds, err := strconv.ParseInt(strings.TrimPrefix(value, "@"), 10, 64); err == nil && value[0] == '@' {
+			notice.DeprecatedAt = time.Unix(seconds, 0).UTC()
+		} else if at, err := http.ParseTime(value); err == nil {
+			notice.DeprecatedAt = at
+		}
+	}
+
+	if at, err := http.ParseTime(header.Get(sunsetHeader)); err == nil {
+		notice.Sunset = at
+	}
+
+	for _, value := range header.Values(warningHeader) {
+		for _, match := range warningPattern.FindAllStringSubmatch(value, -1) {
+			code, _ := strconv.Atoi(match[1])
+			notice.Warnings = append(notice.Warnings, Warning{
+				Code:  code,
+				Agent: match[2],
+				Text:  strings.ReplaceAll(match[3], `\"`, `"`),
+			})
+		}
+	}
+
+	if !notice.Deprecated && notice.Sunset.IsZero() && len(notice.Warnings) == 0 {
+		return nil
+	}
+
+	return notice
+}
+
+func (client *Client) observeDeprecation(request *http.Request, response *http.Response) {
+	notice := parseDeprecation(response.Header)
+	if notice == nil {
+		return
+	}
+
+	event := client.logger.Warn().
+		Str(client.logField("method"), request.Method).
+		Func(client.logURL(request.URL.String())).
+		Bool("deprecated", notice.Deprecated)
+
+	if client.apiVersion != nil {
+		event = event.Str("api_version", client.apiVersion.Version)
+	}
+
+	if !notice.DeprecatedAt.IsZero() {
+		event = event.Time("deprecated_at", notice.DeprecatedAt)
+	}
+
+	if !notice.Sunset.IsZero() {
+		event = event.Time("sunset", notice.Sunset)
+	}
+
+	texts := make([]string, 0, len(notice.Warnings))
+	for _, warning := range notice.Warnings {
+		texts = append(texts, warning.Text)
+	}
+
+	if len(texts) > 0 {
+		event = event.Strs("warnings", texts)
+	}
+
+	event.Msg("server advertised api deprecation")
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestDeprecation_AttachedToResponseAndMetrics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/current" {
+			return
+		}
+		w.Header().Set("Deprecation", "@1735689600")
+		w.Header().Set("Sunset", "Wed, 31 Dec 2025 23:59:59 GMT")
+		w.Header().Add("Warning", `299 api.example.com "Use /v2/items \"soon\"", 199 - "Misc"`)
+	}))
+	defer srv.Close()
+
+	var metrics RequestMetrics
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithMetricsHook(func(m RequestMetrics) { metrics = m }))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	resp, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/items"})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	notice := resp.Deprecation
+	if notice == nil || !notice.Deprecated || !notice.DeprecatedAt.Equal(time.Unix(1735689600, 0)) {
+		t.Fatalf("notice=%+v", notice)
+	}
+	if notice.Sunset.Format(time.RFC3339) != "2025-12-31T23:59:59Z" {
+		t.Fatalf("sunset=%v", notice.Sunset)
+	}
+	if len(notice.Warnings) != 2 || notice.Warnings[0] != (Warning{Code: 299, Agent: "api.example.com", Text: `Use /v2/items "soon"`}) {
+		t.Fatalf("warnings=%+v", notice.Warnings)
+	}
+	if metrics.Deprecation != notice {
+		t.Fatal("metrics hook did not see the notice")
+	}
+
+	resp, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/current"})
+	if err != nil || resp.Deprecation != nil {
+		t.Fatalf("deprecation=%+v err=%v", resp.Deprecation, err)
+	}
+}
+
+func TestParseDeprecation_LegacyForms(t *testing.T) {
+	header := http.Header{}
+	header.Set("Deprecation", "true")
+
+	notice := parseDeprecation(header)
+	if notice == nil || !notice.Deprecated || !notice.DeprecatedAt.IsZero() {
+		t.Fatalf("notice=%+v", notice)
+	}
+
+	header.Set("Deprecation", "Sun, 01 Jun 2025 00:00:00 GMT")
+	if notice = parseDeprecation(header); notice.DeprecatedAt.Format("2006-01-02") != "2025-06-01" {
+		t.Fatalf("notice=%+v", notice)
+	}
+}
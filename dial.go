@@ -0,0 +1,255 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultDialTimeout   = 30 * time.Second
+	defaultDialKeepAlive = 30 * time.Second
+	defaultFallbackDelay = 300 * time.Millisecond
+)
+
+type IPFamily int
+
+const (
+	IPFamilyAny IPFamily = iota
+	IPFamilyPreferIPv4
+	IPFamilyPreferIPv6
+	IPFamilyIPv4Only
+	IPFamilyIPv6Only
+)
+
+var (
+	ErrConnectionVetoed = errors.New("connection vetoed")
+
+	errNoAddresses = errors.New("no addresses to dial")
+)
+
+type dialConfig struct {
+	dialer net.Dialer
+	family IPFamily
+	veto   func(host string, ip net.IP) error
+}
+
+type dialResult struct {
+	conn net.Conn
+	err  error
+}
+
+// WithIPFamily restricts or orders the address families used when dialing.
+// The Prefer variants race the other family after the fallback delay.
+func WithIPFamily(family IPFamily) Option {
+	return func(client *Client) error {
+		client.dialSettings().family = family
+
+		return nil
+	}
+}
+
+// WithDualStackFallbackDelay tunes how long a preferred address family gets
+// before the other one is tried in parallel. A negative delay disables the
+// parallel fallback, so families are tried one after another.
+func WithDualStackFallbackDelay(delay time.Duration) Option {
+	return func(client *Client) error {
+		client.dialSettings().dialer.FallbackDelay = delay
+
+		return nil
+	}
+}
+
+// WithDialVeto calls veto with the requested host and each resolved IP right
+// before connecting; a non-nil error aborts that connection attempt.
+func WithDialVeto(veto func(host string, ip net.IP) error) Option {
+	return func(client *Client) error {
+		client.dialSettings().veto = veto
+
+		return nil
+	}
+}
+
+func (client *Client) dialSettings() *dialConfig {
+	if client.dial != nil {
+		return client.dial
+	}
+
+	client.dial = &dialConfig{
+		dialer: net.Dialer{
+			Timeout:   defaultDialTimeout,
+			KeepAlive: defaultDialKeepAlive,
+		},
+	}
+
+	client.mutableTransport().DialContext = client.dial.dialContext
+
+	return client.dial
+}
+
+func (config *dialConfig) dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer, err := config.dialerFor(address)
+	if err != nil {
+		return nil, err
+	}
+
+	switch config.family {
+	case IPFamilyIPv4Only:
+		return dialer.DialContext(ctx, "tcp4", address)
+	case IPFamilyIPv6Only:
+		return dialer.DialContext(ctx, "tcp6", address)
+	case IPFamilyPreferIPv4, IPFamilyPreferIPv6:
+		return config.dialPreferred(ctx, dialer, address)
+	default:
+		return dialer.DialContext(ctx, network, address)
+	}
+}
+
+func (config *dialConfig) dialerFor(address string) (*net.Dialer, error) {
+	dialer := config.dialer
+
+	if config.veto == nil {
+		return &dialer, nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer.Control = func(_, resolved string, _ syscall.RawConn) error {
+		ipHost, _, err := net.SplitHostPort(resolved)
+		if err != nil {
+			return err
+		}
+
+		ip := net.ParseIP(ipHost)
+
+		if err = config.veto(host, ip); err != nil {
+			return fmt.Errorf("%w: %s (%s): %v", ErrConnectionVetoed, host, ip, err)
+		}
+
+		return nil
+	}
+
+	return &dialer, nil
+}
+
+func (config *dialConfig) dialPreferred(ctx context.Context, dialer *net.Dialer, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var primary, fallback []net.IP
+
+	for _, addr := range addrs {
+		if (addr.IP.To4() != nil) == (config.family == IPFamilyPreferIPv4) {
+			primary = append(primary, addr.IP)
+		} else {
+			fallback = append(fallback, addr.IP)
+		}
+	}
+
+	delay := dialer.FallbackDelay
+	if delay == 0 {
+		delay = defaultFallbackDelay
+	}
+
+	if len(primary) == 0 || len(fallback) == 0 || delay < 0 {
+		return dialSerial(ctx, dialer, append(primary, fallback...), port)
+	}
+
+	return dialRace(ctx, dialer, primary, fallback, port, delay)
+}
+
+func dialRace(
+	ctx context.Context,
+	dialer *net.Dialer,
+	primary []net.IP,
+	fallback []net.IP,
+	port string,
+	delay time.Duration,
+) (net.Conn, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan dialResult, 2)
+	start := func(ips []net.IP) {
+		go func() {
+			conn, err := dialSerial(ctx, dialer, ips, port)
+			results <- dialResult{conn: conn, err: err}
+		}()
+	}
+
+	start(primary)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	pending, fallbackStarted := 1, false
+
+	var firstErr error
+
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			if !fallbackStarted {
+				start(fallback)
+				pending++
+				fallbackStarted = true
+			}
+		case result := <-results:
+			pending--
+
+			if result.err == nil {
+				go closeLateConns(results, pending)
+				return result.conn, nil
+			}
+
+			if firstErr == nil {
+				firstErr = result.err
+			}
+
+			if !fallbackStarted {
+				timer.Stop()
+				start(fallback)
+				pending++
+				fallbackStarted = true
+			}
+		}
+	}
+
+	return nil, firstErr
+}
+
+func dialSerial(ctx context.Context, dialer *net.Dialer, ips []net.IP, port string) (net.Conn, error) {
+	err := errNoAddresses
+
+	for _, ip := range ips {
+		var conn net.Conn
+
+		conn, err = dialer.DialContext(ctx, "tcp", net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+	}
+
+	return nil, err
+}
+
+func closeLateConns(results chan dialResult, pending int) {
+	for ; pending > 0; pending-- {
+		if result := <-results; result.conn != nil {
+			_ = result.conn.Close()
+		}
+	}
+}
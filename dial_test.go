@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func localhostURL(t *testing.T, srv *httptest.Server) (string, string) {
+	t.Helper()
+
+	_, port, err := net.SplitHostPort(strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatalf("bad server url: %v", err)
+	}
+
+	return "http://localhost:" + port, port
+}
+
+func TestWithIPFamily_Dialing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	baseURL, port := localhostURL(t, srv)
+
+	cases := []struct {
+		family  IPFamily
+		address string
+		ok      bool
+	}{
+		{IPFamilyIPv4Only, "localhost:" + port, true},
+		{IPFamilyIPv6Only, "127.0.0.1:" + port, false},
+		{IPFamilyPreferIPv4, "localhost:" + port, true},
+		{IPFamilyPreferIPv6, "localhost:" + port, true},
+	}
+
+	for _, tc := range cases {
+		log := zerolog.Nop()
+		c, err := New(baseURL, nil, &log, false, "ua", WithIPFamily(tc.family))
+		if err != nil {
+			t.Fatalf("New error: %v", err)
+		}
+
+		conn, err := c.dial.dialContext(context.Background(), "tcp", tc.address)
+		if (err == nil) != tc.ok {
+			t.Errorf("family=%d address=%s err=%v", tc.family, tc.address, err)
+		}
+		if conn != nil {
+			if tc.family == IPFamilyIPv4Only || tc.family == IPFamilyPreferIPv4 {
+				if ip := conn.RemoteAddr().(*net.TCPAddr).IP; ip.To4() == nil {
+					t.Errorf("family=%d dialed %s", tc.family, ip)
+				}
+			}
+			conn.Close()
+		}
+	}
+}
+
+func TestWithDualStackFallbackDelay_Serial(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	baseURL, _ := localhostURL(t, srv)
+
+	log := zerolog.Nop()
+	c, err := New(baseURL, nil, &log, false, "ua", WithIPFamily(IPFamilyPreferIPv6), WithDualStackFallbackDelay(-1))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	if _, _, err = c.SendGet("/x", nil, nil); err != nil {
+		t.Fatalf("SendGet error: %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"crypto/md5" //nolint:gosec
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+)
+
+const (
+	contentMD5Header    = "Content-MD5"
+	contentDigestHeader = "Content-Digest"
+	digestHeader        = "Digest"
+)
+
+type DigestAlgorithm string
+
+const (
+	DigestSHA256 DigestAlgorithm = "sha-256"
+	DigestSHA512 DigestAlgorithm = "sha-512"
+)
+
+// BodyDigest selects the integrity headers computed for request bodies:
+// Content-MD5, RFC 9530 Content-Digest and the older RFC 3230 Digest.
+type BodyDigest struct {
+	ContentMD5    bool
+	ContentDigest []DigestAlgorithm
+	Digest        []DigestAlgorithm
+}
+
+// WithBodyDigest attaches digest headers to every request with a body.
+// Bodies that implement io.Seeker, such as files, are hashed while streaming
+// and rewound; others are buffered once.
+func WithBodyDigest(config BodyDigest) Option {
+	return func(client *Client) error {
+		for _, algorithm := range append(append([]DigestAlgorithm{}, config.ContentDigest...), config.Digest...) {
+			if newDigestHash(algorithm) == nil {
+				return fmt.Errorf("unsupported digest algorithm %q", algorithm)
+			}
+		}
+
+		client.bodyDigest = &config
+
+		return nil
+	}
+}
+
+func newDigestHash(algorithm DigestAlgorithm) hash.Hash {
+	switch DigestAlgorithm(strings.ToLower(string(algorithm))) {
+	case DigestSHA256:
+		return sha256.New()
+	case DigestSHA512:
+		return sha512.New()
+	default:
+		return nil
+	}
+}
+
+func (client *Client) digestBody(spec *RequestSpec) error {
+	config := client.bodyDigest
+	if config == nil || spec.Body == nil {
+		return nil
+	}
+
+	hashes := map[DigestAlgorithm]hash.Hash{}
+	writers := []io.Writer{}
+
+	for _, algorithm := range append(append([]DigestAlgorithm{}, config.ContentDigest...), config.Digest...) {
+		if _, ok := hashes[algorithm]; !ok {
+			hashes[algorithm] = newDigestHash(algorithm)
+			writers = append(writers, hashes[algorithm])
+		}
+	}
+
+	md5Hash := md5.New() //nolint:gosec
+	if config.ContentMD5 {
+		writers = append(writers, md5Hash)
+	}
+
+	if err := hashSpecBody(spec, io.MultiWriter(writers...)); err != nil {
+		return err
+	}
+
+	spec.Headers = spec.Headers.Clone()
+	if spec.Headers == nil {
+		spec.Headers = MultiHeaders{}
+	}
+
+	if config.ContentMD5 {
+		spec.Headers.Set(contentMD5Header, base64.StdEncoding.EncodeToString(md5Hash.Sum(nil)))
+	}
+
+	if len(config.ContentDigest) > 0 {
+		values := make([]string, 0, len(config.ContentDigest))
+		for _, algorithm := range config.ContentDigest {
+			values = append(values, fmt.Sprintf("%s=:%s:", algorithm, base64.StdEncoding.EncodeToString(hashes[algorithm].Sum(nil))))
+		}
+
+		spec.Headers.Set(contentDigestHeader, strings.Join(values, ", "))
+	}
+
+	if len(config.Digest) > 0 {
+		values := make([]string, 0, len(config.Digest))
+		for _, algorithm := range config.Digest {
+			values = append(values, strings.ToUpper(string(algorithm))+"="+base64.StdEncoding.EncodeToString(hashes[algorithm].Sum(nil)))
+		}
+
+		spec.Headers.Set(digestHeader, strings.Join(values, ","))
+	}
+
+	return nil
+}
+
+func hashSpecBody(spec *RequestSpec, w io.Writer) error {
+	if seeker, ok := spec.Body.(io.ReadSeeker); ok {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+
+		if _, err = io.Copy(w, seeker); err != nil {
+			return err
+		}
+
+		_, err = seeker.Seek(start, io.SeekStart)
+
+		return err
+	}
+
+	body, err := readSpecBody(spec)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(body)
+
+	return err
+}
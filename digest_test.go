@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithBodyDigest_SetsHeaders(t *testing.T) {
+	var header http.Header
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithBodyDigest(BodyDigest{
+		ContentMD5:    true,
+		ContentDigest: []DigestAlgorithm{DigestSHA256},
+		Digest:        []DigestAlgorithm{DigestSHA256},
+	}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	payload := "hello world"
+	md5Sum := md5.Sum([]byte(payload)) //nolint:gosec
+	shaSum := sha256.Sum256([]byte(payload))
+	sha := base64.StdEncoding.EncodeToString(shaSum[:])
+
+	path := filepath.Join(t.TempDir(), "upload")
+	if err = os.WriteFile(path, []byte(payload), 0o600); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open error: %v", err)
+	}
+	defer file.Close()
+
+	for _, reader := range []io.Reader{strings.NewReader(payload), io.MultiReader(strings.NewReader(payload)), file} {
+		if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodPut, Path: "/", Body: reader}); err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+		if body != payload {
+			t.Fatalf("body=%q", body)
+		}
+		if header.Get("Content-MD5") != base64.StdEncoding.EncodeToString(md5Sum[:]) {
+			t.Fatalf("Content-MD5=%s", header.Get("Content-MD5"))
+		}
+		if header.Get("Content-Digest") != "sha-256=:"+sha+":" || header.Get("Digest") != "SHA-256="+sha {
+			t.Fatalf("Content-Digest=%s Digest=%s", header.Get("Content-Digest"), header.Get("Digest"))
+		}
+	}
+}
+
+func TestWithBodyDigest_RejectsUnknownAlgorithm(t *testing.T) {
+	log := zerolog.Nop()
+	if _, err := New("http://example.com", nil, &log, false, "ua", WithBodyDigest(BodyDigest{Digest: []DigestAlgorithm{"crc32"}})); err == nil {
+		t.Fatal("expected error for unsupported algorithm")
+	}
+}
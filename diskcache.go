@@ -0,0 +1,325 @@
+package client
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	diskCacheSuffix     = ".entry"
+	diskCacheTempPrefix = ".tmp-"
+	diskCacheDirMode    = 0o700
+)
+
+var ErrCacheEntryTooLarge = errors.New("cache entry is larger than the cache")
+
+// DiskCache is a Cache that keeps one file per entry in a directory and
+// evicts the least recently used entries once the files exceed maxBytes.
+// Entries are written to a temporary file and renamed into place, and carry
+// a checksum; unreadable or corrupt entries are removed and reported as
+// misses. Recency survives restarts through the file modification times.
+type DiskCache struct {
+	dir      string
+	maxBytes int64
+
+	mu    sync.Mutex
+	index map[string]*list.Element
+	lru   *list.List
+	size  int64
+}
+
+type diskCacheFile struct {
+	name string
+	size int64
+}
+
+type diskCacheRecord struct {
+	Key   string          `json:"key"`
+	Entry *CachedResponse `json:"entry"`
+}
+
+// NewDiskCache opens or creates the cache in dir. Leftover temporary files
+// of interrupted writes are removed and the cache is trimmed to maxBytes.
+func NewDiskCache(dir string, maxBytes int64) (*DiskCache, error) {
+	if maxBytes <= 0 {
+		return nil, errors.New("disk cache size must be positive")
+	}
+
+	if err := os.MkdirAll(dir, diskCacheDirMode); err != nil {
+		return nil, fmt.Errorf("create disk cache: %w", err)
+	}
+
+	cache := &DiskCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		index:    map[string]*list.Element{},
+		lru:      list.New(),
+	}
+
+	if err := cache.load(); err != nil {
+		return nil, err
+	}
+
+	cache.mu.Lock()
+	cache.evict()
+	cache.mu.Unlock()
+
+	return cache, nil
+}
+
+func (cache *DiskCache) load() error {
+	entries, err := os.ReadDir(cache.dir)
+	if err != nil {
+		return fmt.Errorf("read disk cache: %w", err)
+	}
+
+	type found struct {
+		file    diskCacheFile
+		modTime time.Time
+	}
+
+	files := make([]found, 0, len(entries))
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if strings.HasPrefix(name, diskCacheTempPrefix) {
+			_ = os.Remove(filepath.Join(cache.dir, name))
+			continue
+		}
+
+		if entry.IsDir() || !strings.HasSuffix(name, diskCacheSuffix) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		files = append(files, found{file: diskCacheFile{name: name, size: info.Size()}, modTime: info.ModTime()})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.After(files[j].modTime) })
+
+	for _, file := range files {
+		cache.index[file.file.name] = cache.lru.PushBack(file.file)
+		cache.size += file.file.size
+	}
+
+	return nil
+}
+
+func (cache *DiskCache) Get(_ context.Context, key string) (*CachedResponse, error) {
+	name := diskCacheName(key)
+	path := filepath.Join(cache.dir, name)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		cache.forget(name)
+		return nil, ErrCacheMiss
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	record, ok := decodeDiskCacheRecord(data)
+	if !ok || record.Key != key {
+		cache.remove(name)
+		return nil, ErrCacheMiss
+	}
+
+	if !record.Entry.ExpiresAt.IsZero() && time.Now().After(record.Entry.ExpiresAt) {
+		cache.remove(name)
+		return nil, ErrCacheMiss
+	}
+
+	cache.touch(name, path, int64(len(data)))
+
+	return record.Entry, nil
+}
+
+func (cache *DiskCache) Set(_ context.Context, key string, entry *CachedResponse) error {
+	data, err := encodeDiskCacheRecord(diskCacheRecord{Key: key, Entry: entry})
+	if err != nil {
+		return err
+	}
+
+	if int64(len(data)) > cache.maxBytes {
+		return ErrCacheEntryTooLarge
+	}
+
+	temp, err := os.CreateTemp(cache.dir, diskCacheTempPrefix+"*")
+	if err != nil {
+		return err
+	}
+
+	if err = writeDiskCacheFile(temp, data); err != nil {
+		_ = os.Remove(temp.Name())
+		return err
+	}
+
+	name := diskCacheName(key)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if err = os.Rename(temp.Name(), filepath.Join(cache.dir, name)); err != nil {
+		_ = os.Remove(temp.Name())
+		return err
+	}
+
+	if element, ok := cache.index[name]; ok {
+		cache.size -= element.Value.(diskCacheFile).size
+		cache.lru.Remove(element)
+	}
+
+	cache.index[name] = cache.lru.PushFront(diskCacheFile{name: name, size: int64(len(data))})
+	cache.size += int64(len(data))
+	cache.evict()
+
+	return nil
+}
+
+func (cache *DiskCache) Delete(_ context.Context, key string) error {
+	name := diskCacheName(key)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.unindex(name)
+
+	if err := os.Remove(filepath.Join(cache.dir, name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// Size returns the bytes currently used by cache entries.
+func (cache *DiskCache) Size() int64 {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return cache.size
+}
+
+func (cache *DiskCache) touch(name, path string, size int64) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if element, ok := cache.index[name]; ok {
+		cache.lru.MoveToFront(element)
+	} else {
+		cache.index[name] = cache.lru.PushFront(diskCacheFile{name: name, size: size})
+		cache.size += size
+		cache.evict()
+	}
+
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+}
+
+func (cache *DiskCache) remove(name string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.unindex(name)
+	_ = os.Remove(filepath.Join(cache.dir, name))
+}
+
+func (cache *DiskCache) forget(name string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.unindex(name)
+}
+
+// unindex must be called with cache.mu held.
+func (cache *DiskCache) unindex(name string) {
+	if element, ok := cache.index[name]; ok {
+		cache.size -= element.Value.(diskCacheFile).size
+		cache.lru.Remove(element)
+		delete(cache.index, name)
+	}
+}
+
+// evict must be called with cache.mu held.
+func (cache *DiskCache) evict() {
+	for cache.size > cache.maxBytes {
+		oldest := cache.lru.Back()
+		if oldest == nil {
+			return
+		}
+
+		file := oldest.Value.(diskCacheFile)
+		cache.unindex(file.name)
+		_ = os.Remove(filepath.Join(cache.dir, file.name))
+	}
+}
+
+func diskCacheName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:]) + diskCacheSuffix
+}
+
+// encodeDiskCacheRecord prefixes the JSON record with its SHA-256 so torn or
+// corrupted files are detected on read.
+func encodeDiskCacheRecord(record diskCacheRecord) ([]byte, error) {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(payload)
+
+	return append(sum[:], payload...), nil
+}
+
+func decodeDiskCacheRecord(data []byte) (diskCacheRecord, bool) {
+	var record diskCacheRecord
+
+	if len(data) < sha256.Size {
+		return record, false
+	}
+
+	sum, payload := data[:sha256.Size], data[sha256.Size:]
+	if actual := sha256.Sum256(payload); !bytes.Equal(sum, actual[:]) {
+		return record, false
+	}
+
+	if err := json.Unmarshal(payload, &record); err != nil || record.Entry == nil {
+		return record, false
+	}
+
+	return record, true
+}
+
+func writeDiskCacheFile(file *os.File, data []byte) error {
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
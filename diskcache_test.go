@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// diskCacheEntry truncates the times so entries with equal bodies encode to
+// the same size.
+func diskCacheEntry(body string) *CachedResponse {
+	now := time.Now().Truncate(time.Second)
+
+	return &CachedResponse{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"text/plain"}},
+		Body:       []byte(body),
+		StoredAt:   now,
+		ExpiresAt:  now.Add(time.Hour),
+	}
+}
+
+func diskCacheEntrySize(t *testing.T, key, body string) int64 {
+	t.Helper()
+
+	data, err := encodeDiskCacheRecord(diskCacheRecord{Key: key, Entry: diskCacheEntry(body)})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	return int64(len(data))
+}
+
+func TestDiskCache_RoundTripAndReopen(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	cache, err := NewDiskCache(dir, 1<<20)
+	if err != nil {
+		t.Fatalf("NewDiskCache: %v", err)
+	}
+
+	if err = cache.Set(ctx, "k", diskCacheEntry("hello")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	reopened, err := NewDiskCache(dir, 1<<20)
+	if err != nil {
+		t.Fatalf("NewDiskCache: %v", err)
+	}
+
+	entry, err := reopened.Get(ctx, "k")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(entry.Body) != "hello" || entry.Header.Get("Content-Type") != "text/plain" {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+	if reopened.Size() != cache.Size() {
+		t.Fatalf("size after reopen = %d, want %d", reopened.Size(), cache.Size())
+	}
+
+	if err = reopened.Delete(ctx, "k"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err = reopened.Get(ctx, "k"); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("Get after Delete err = %v, want ErrCacheMiss", err)
+	}
+	if reopened.Size() != 0 {
+		t.Fatalf("size after Delete = %d", reopened.Size())
+	}
+}
+
+func TestDiskCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	size := diskCacheEntrySize(t, "a", "xxxx")
+
+	cache, err := NewDiskCache(t.TempDir(), 2*size)
+	if err != nil {
+		t.Fatalf("NewDiskCache: %v", err)
+	}
+
+	_ = cache.Set(ctx, "a", diskCacheEntry("xxxx"))
+	_ = cache.Set(ctx, "b", diskCacheEntry("xxxx"))
+
+	if _, err = cache.Get(ctx, "a"); err != nil {
+		t.Fatalf("Get(a): %v", err)
+	}
+
+	_ = cache.Set(ctx, "c", diskCacheEntry("xxxx"))
+
+	if _, err = cache.Get(ctx, "b"); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("b should have been evicted, err = %v", err)
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, err = cache.Get(ctx, key); err != nil {
+			t.Fatalf("Get(%s): %v", key, err)
+		}
+	}
+	if cache.Size() > 2*size {
+		t.Fatalf("size = %d exceeds limit %d", cache.Size(), 2*size)
+	}
+}
+
+func TestDiskCache_CorruptEntryIsAMiss(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	cache, err := NewDiskCache(dir, 1<<20)
+	if err != nil {
+		t.Fatalf("NewDiskCache: %v", err)
+	}
+
+	_ = cache.Set(ctx, "k", diskCacheEntry("hello"))
+
+	path := filepath.Join(dir, diskCacheName("k"))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if err = os.WriteFile(path, data[:len(data)-3], 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if _, err = cache.Get(ctx, "k"); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("err = %v, want ErrCacheMiss", err)
+	}
+	if _, err = os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("corrupt entry was not removed")
+	}
+}
+
+func TestDiskCache_RemovesInterruptedWritesAndRejectsLargeEntries(t *testing.T) {
+	dir := t.TempDir()
+	leftover := filepath.Join(dir, diskCacheTempPrefix+"123")
+
+	if err := os.WriteFile(leftover, []byte("partial"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	cache, err := NewDiskCache(dir, 64)
+	if err != nil {
+		t.Fatalf("NewDiskCache: %v", err)
+	}
+
+	if _, err = os.Stat(leftover); !os.IsNotExist(err) {
+		t.Fatalf("leftover temporary file was not removed")
+	}
+
+	if err = cache.Set(context.Background(), "k", diskCacheEntry("hello")); !errors.Is(err, ErrCacheEntryTooLarge) {
+		t.Fatalf("err = %v, want ErrCacheEntryTooLarge", err)
+	}
+}
+
+func TestDiskCache_WithClient(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	cache, err := NewDiskCache(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatalf("NewDiskCache: %v", err)
+	}
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithCache(cache, time.Minute))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/items"}); err != nil {
+			t.Fatalf("Send() error: %v", err)
+		}
+	}
+
+	if hits != 1 {
+		t.Fatalf("hits = %d, want 1", hits)
+	}
+}
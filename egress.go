@@ -0,0 +1,90 @@
+package client
+
+import (
+	"net/http"
+	"sort"
+)
+
+// WithHeaderAllowlist drops every RequestSpec header not named here and
+// logs a warning listing the dropped names, so internal headers copied from
+// inbound requests cannot leak upstream. Headers the client adds itself,
+// such as User-Agent, Content-Type of encoded bodies, auth, digests,
+// signatures and nonces, are always sent, as are the headers, cookies and
+// CSRF token a Session adds. Headers set by the package's helpers, such
+// as webhook signatures, SOAPAction, WebDAV Depth and upload Content-Type,
+// are not filtered either.
+func WithHeaderAllowlist(headers ...string) Option {
+	return func(client *Client) error {
+		if client.allowedHeaders == nil {
+			client.allowedHeaders = map[string]bool{}
+		}
+
+		for _, name := range headers {
+			client.allowedHeaders[http.CanonicalHeaderKey(name)] = true
+		}
+
+		return nil
+	}
+}
+
+func (client *Client) filterHeaders(spec *RequestSpec) {
+	if client.allowedHeaders == nil || len(spec.Headers) == 0 {
+		return
+	}
+
+	var dropped []string
+
+	kept := MultiHeaders{}
+
+	for name, values := range spec.Headers {
+		if client.allowedHeaders[http.CanonicalHeaderKey(name)] {
+			kept[name] = values
+		} else {
+			dropped = append(dropped, name)
+		}
+	}
+
+	if len(dropped) == 0 {
+		return
+	}
+
+	sort.Strings(dropped)
+
+	client.logger.Warn().
+		Str(client.logField("method"), spec.Method).
+		Func(client.logURL(client.baseUrl+spec.Path)).
+		Strs("headers", dropped).
+		Msg("dropped http request headers not in allowlist")
+
+	spec.Headers = kept
+}
+
+// withHeaders sets headers chosen by the package's own helpers. They are
+// added after the allowlist and replace caller headers of the same name.
+func withHeaders(headers MultiHeaders) RequestOption {
+	return func(options *requestOptions) {
+		if options.headers == nil {
+			options.headers = MultiHeaders{}
+		}
+
+		for name, values := range headers {
+			options.headers[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+}
+
+func (options *requestOptions) applyHeaders(spec *RequestSpec) {
+	if len(options.headers) == 0 {
+		return
+	}
+
+	spec.Headers = spec.Headers.Clone()
+	if spec.Headers == nil {
+		spec.Headers = MultiHeaders{}
+	}
+
+	for name, values := range options.headers {
+		spec.Headers.Del(name)
+		spec.Headers[name] = append([]string(nil), values...)
+	}
+}
@@ -0,0 +1,155 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithHeaderAllowlist_DropsOtherHeaders(t *testing.T) {
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	log := zerolog.New(&buf)
+	c, err := New(srv.URL, nil, &log, false, "ua",
+		WithHeaderAllowlist("x-request-id"),
+		WithBearerToken(StaticToken("abc")))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	_, err = c.Send(context.Background(), RequestSpec{
+		Method: http.MethodPost,
+		Path:   "/",
+		Headers: MultiHeaders{
+			"X-Request-Id":    {"r1"},
+			"X-Internal-User": {"admin"},
+			"Cookie":          {"session=secret"},
+		},
+	}, JSONBody(map[string]int{"a": 1}))
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	if header.Get("X-Request-Id") != "r1" || header.Get("X-Internal-User") != "" || header.Get("Cookie") != "" {
+		t.Fatalf("headers=%v", header)
+	}
+	if header.Get(AuthorizationHeader) != "Bearer abc" || header.Get(ContentTypeHeader) != ContentTypeJson || header.Get("User-Agent") != "ua" {
+		t.Fatalf("client headers dropped: %v", header)
+	}
+	if !strings.Contains(buf.String(), `"headers":["Cookie","X-Internal-User"]`) || strings.Contains(buf.String(), "secret") {
+		t.Fatalf("log=%s", buf.String())
+	}
+}
+
+func TestWithHeaderAllowlist_KeepsSessionHeaders(t *testing.T) {
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/csrf" {
+			http.SetCookie(w, &http.Cookie{Name: "sid", Value: "s1", Path: "/"})
+			w.Header().Set(defaultCSRFHeader, "t1")
+			return
+		}
+		header = r.Header.Clone()
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithHeaderAllowlist("x-request-id"))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	session := c.NewSession(context.Background()).SetHeader("X-Tenant", "acme").WithCSRF(CSRFConfig{Path: "/csrf"})
+
+	_, err = session.Send(RequestSpec{
+		Method:  http.MethodPost,
+		Path:    "/orders",
+		Headers: MultiHeaders{"X-Request-Id": {"r1"}, "X-Internal-User": {"admin"}},
+	})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	if header.Get("X-Tenant") != "acme" || header.Get(defaultCSRFHeader) != "t1" || header.Get("Cookie") != "sid=s1" {
+		t.Fatalf("session headers dropped: %v", header)
+	}
+	if header.Get("X-Request-Id") != "r1" || header.Get("X-Internal-User") != "" {
+		t.Fatalf("headers=%v", header)
+	}
+}
+
+func TestWithHeaderAllowlist_KeepsWebhookHeaders(t *testing.T) {
+	secret := []byte("whsec")
+	var header http.Header
+	var valid bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		header = r.Header.Clone()
+		valid = header.Get("Webhook-Signature") == "v1,"+SignWebhook(secret, header.Get("Webhook-Id"), header.Get("Webhook-Timestamp"), body)
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithHeaderAllowlist("x-request-id"))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	hook := Webhook{Secret: secret, Headers: MultiHeaders{"X-Request-Id": {"r1"}, "X-Internal-User": {"admin"}}}
+
+	if _, err = c.Deliver(context.Background(), "/hook", WebhookEvent{Payload: []byte(`{"type":"ping"}`)}, hook); err != nil {
+		t.Fatalf("Deliver error: %v", err)
+	}
+
+	if header.Get("Webhook-Id") == "" || !valid || header.Get(ContentTypeHeader) != ContentTypeJson {
+		t.Fatalf("webhook headers dropped: %v", header)
+	}
+	if header.Get("X-Request-Id") != "r1" || header.Get("X-Internal-User") != "" {
+		t.Fatalf("headers=%v", header)
+	}
+}
+
+func TestWithHeaderAllowlist_KeepsSendFileContentType(t *testing.T) {
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+	}))
+	defer srv.Close()
+
+	name := filepath.Join(t.TempDir(), "data.json")
+	if err := os.WriteFile(name, []byte(`{"a":1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithHeaderAllowlist("x-request-id"))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	upload := FileUpload{Headers: MultiHeaders{"X-Request-Id": {"r1"}, "X-Internal-User": {"admin"}}}
+
+	if _, err = c.SendFile(context.Background(), http.MethodPut, "/upload", name, upload); err != nil {
+		t.Fatalf("SendFile error: %v", err)
+	}
+
+	if header.Get(ContentTypeHeader) != "application/json" {
+		t.Fatalf("content type dropped: %v", header)
+	}
+	if header.Get("X-Request-Id") != "r1" || header.Get("X-Internal-User") != "" {
+		t.Fatalf("headers=%v", header)
+	}
+}
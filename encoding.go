@@ -0,0 +1,136 @@
+package client
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	EncodingGzip   = "gzip"
+	EncodingBrotli = "br"
+	EncodingZstd   = "zstd"
+
+	// EncodingIdentity asks for an uncompressed response.
+	EncodingIdentity = "identity"
+
+	acceptEncodingHeader  = "Accept-Encoding"
+	contentEncodingHeader = "Content-Encoding"
+)
+
+type bodyDecoder func(io.Reader) (io.ReadCloser, error)
+
+var bodyDecoders = map[string]bodyDecoder{
+	EncodingGzip: func(r io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(r)
+	},
+	EncodingBrotli: func(r io.Reader) (io.ReadCloser, error) {
+		return io.NopCloser(brotli.NewReader(r)), nil
+	},
+	EncodingZstd: func(r io.Reader) (io.ReadCloser, error) {
+		decoder, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return decoder.IOReadCloser(), nil
+	},
+}
+
+// WithContentDecoding advertises the given encodings (EncodingBrotli,
+// EncodingZstd) in Accept-Encoding, in order of preference, and decodes
+// matching responses. gzip is always offered as the last fallback since
+// setting Accept-Encoding turns off the transport's own gzip handling.
+func WithContentDecoding(encodings ...string) Option {
+	return func(client *Client) error {
+		for _, encoding := range encodings {
+			if _, ok := bodyDecoders[encoding]; !ok {
+				return fmt.Errorf("unsupported content encoding %q", encoding)
+			}
+
+			if encoding != EncodingGzip {
+				client.encodings = append(client.encodings, encoding)
+			}
+		}
+
+		client.encodings = append(client.encodings, EncodingGzip)
+
+		return nil
+	}
+}
+
+// AcceptEncoding overrides Accept-Encoding for a single request, in order of
+// preference, and decodes responses in any of the encodings. Without
+// encodings it asks for EncodingIdentity, for downloads that are compressed
+// already. Encodings other than gzip, br and zstd are passed through as is.
+func AcceptEncoding(encodings ...string) RequestOption {
+	return func(options *requestOptions) {
+		options.acceptEncoding = append([]string{}, encodings...)
+	}
+}
+
+func (client *Client) negotiateEncoding(request *http.Request, options *requestOptions) {
+	if options.acceptEncoding != nil {
+		encodings := options.acceptEncoding
+		if len(encodings) == 0 {
+			encodings = []string{EncodingIdentity}
+		}
+
+		request.Header.Set(acceptEncodingHeader, strings.Join(encodings, ", "))
+
+		return
+	}
+
+	if len(client.encodings) == 0 || request.Header.Get(acceptEncodingHeader) != "" {
+		return
+	}
+
+	request.Header.Set(acceptEncodingHeader, strings.Join(client.encodings, ", "))
+}
+
+func (client *Client) decodeResponse(response *http.Response, options *requestOptions) error {
+	if len(client.encodings) == 0 && len(options.acceptEncoding) == 0 {
+		return nil
+	}
+
+	encoding := strings.ToLower(strings.TrimSpace(response.Header.Get(contentEncodingHeader)))
+
+	decode, ok := bodyDecoders[encoding]
+	if !ok {
+		return nil
+	}
+
+	reader, err := decode(response.Body)
+	if err != nil {
+		_ = response.Body.Close()
+		return err
+	}
+
+	response.Body = &decodedBody{ReadCloser: reader, raw: response.Body}
+	response.Header.Del(contentEncodingHeader)
+	response.Header.Del("Content-Length")
+	response.ContentLength = -1
+	response.Uncompressed = true
+
+	return nil
+}
+
+type decodedBody struct {
+	io.ReadCloser
+	raw io.Closer
+}
+
+func (body *decodedBody) Close() error {
+	err := body.ReadCloser.Close()
+
+	if rawErr := body.raw.Close(); err == nil {
+		err = rawErr
+	}
+
+	return err
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/rs/zerolog"
+)
+
+func compressed(t *testing.T, encoding string, payload []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var w io.WriteCloser
+
+	switch encoding {
+	case EncodingBrotli:
+		w = brotli.NewWriter(&buf)
+	case EncodingZstd:
+		enc, err := zstd.NewWriter(&buf)
+		if err != nil {
+			t.Fatalf("zstd writer: %v", err)
+		}
+		w = enc
+	default:
+		w = gzip.NewWriter(&buf)
+	}
+
+	if _, err := w.Write(payload); err != nil {
+		t.Fatalf("compress: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("compress close: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestWithContentDecoding(t *testing.T) {
+	payload := []byte(`{"ok":true}`)
+	var gotAccept string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAccept = r.Header.Get("Accept-Encoding")
+		encoding := r.URL.Query().Get("enc")
+		w.Header().Set("Content-Encoding", encoding)
+		_, _ = w.Write(compressed(t, encoding, payload))
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithContentDecoding(EncodingBrotli, EncodingZstd))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	for _, encoding := range []string{EncodingBrotli, EncodingZstd, EncodingGzip} {
+		resp, err := c.Send(context.Background(), RequestSpec{
+			Method: http.MethodGet,
+			Path:   "/",
+			Params: MultiParams{"enc": {encoding}},
+		})
+		if err != nil {
+			t.Fatalf("%s: Send error: %v", encoding, err)
+		}
+		if !bytes.Equal(resp.Body, payload) {
+			t.Errorf("%s: body=%q", encoding, resp.Body)
+		}
+		if resp.Header.Get("Content-Encoding") != "" {
+			t.Errorf("%s: Content-Encoding not removed", encoding)
+		}
+	}
+
+	if gotAccept != "br, zstd, gzip" {
+		t.Errorf("Accept-Encoding=%q", gotAccept)
+	}
+
+	if _, err = New(srv.URL, nil, &log, false, "ua", WithContentDecoding("lz4")); err == nil {
+		t.Error("expected error for unsupported encoding")
+	}
+}
+
+func TestAcceptEncoding_PerRequestOverride(t *testing.T) {
+	payload := []byte("archive-bytes")
+	var gotAccept string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAccept = r.Header.Get("Accept-Encoding")
+		for _, encoding := range []string{EncodingZstd, EncodingGzip} {
+			if strings.Contains(gotAccept, encoding) {
+				w.Header().Set("Content-Encoding", encoding)
+				_, _ = w.Write(compressed(t, encoding, payload))
+				return
+			}
+		}
+		_, _ = w.Write(payload)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	spec := RequestSpec{Method: http.MethodGet, Path: "/"}
+
+	tests := []struct {
+		name         string
+		opts         []RequestOption
+		accept       string
+		decompressed bool
+	}{
+		{"transport default", nil, "gzip", true},
+		{"identity", []RequestOption{AcceptEncoding()}, EncodingIdentity, false},
+		{"zstd", []RequestOption{AcceptEncoding(EncodingZstd)}, EncodingZstd, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := c.Send(context.Background(), spec, tt.opts...)
+			if err != nil {
+				t.Fatalf("Send() error: %v", err)
+			}
+			if gotAccept != tt.accept {
+				t.Fatalf("Accept-Encoding = %q, want %q", gotAccept, tt.accept)
+			}
+			if !bytes.Equal(resp.Body, payload) || resp.Decompressed != tt.decompressed {
+				t.Fatalf("body=%q decompressed=%v, want %v", resp.Body, resp.Decompressed, tt.decompressed)
+			}
+		})
+	}
+}
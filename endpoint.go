@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var ErrUnboundPathParam = errors.New("path parameter is not bound")
+
+// Codec encodes request bodies and decodes response bodies of an Endpoint.
+type Codec interface {
+	BodyMarshaler
+	Unmarshal(data []byte, v any) error
+}
+
+// Endpoint is a typed operation declared once and called many times:
+//
+//	var GetUser = client.NewEndpoint[UserParams, User](c, http.MethodGet, "/users/{id}")
+//	user, _, err := GetUser.Call(ctx, UserParams{ID: "42"})
+//
+// P is bound like BindRequest. When P has no query, header or body tags and
+// the method carries a body, P itself is the body. Calls go through Send, so
+// every option, validator and hook of the client applies.
+type Endpoint[P, R any] struct {
+	Client *Client
+	Method string
+	Path   string
+	// Codec defaults to the client's body marshaler and JSON engine.
+	Codec Codec
+}
+
+func NewEndpoint[P, R any](client *Client, method, path string) *Endpoint[P, R] {
+	return &Endpoint[P, R]{Client: client, Method: method, Path: path}
+}
+
+func (endpoint *Endpoint[P, R]) Call(ctx context.Context, params P, opts ...RequestOption) (R, *Response, error) {
+	var result R
+
+	binding := bind(params)
+	if !binding.tagged && hasRequestBody(endpoint.Method) {
+		binding.body = params
+		binding.hasBody = true
+	}
+
+	spec, err := binding.spec(endpoint.Method, endpoint.Path)
+	if err != nil {
+		return result, nil, err
+	}
+
+	if binding.hasBody && endpoint.Codec != nil {
+		spec.Options = []RequestOption{BodyWith(binding.body, endpoint.Codec)}
+	}
+
+	if endpoint.Codec == nil {
+		return SendAs[R](ctx, endpoint.Client, spec, opts...)
+	}
+
+	response, err := endpoint.Client.Send(ctx, spec, opts...)
+	if err != nil || len(response.Body) == 0 {
+		return result, response, err
+	}
+
+	err = endpoint.Codec.Unmarshal(response.Body, &result)
+
+	return result, response, err
+}
+
+func hasRequestBody(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+func expandPath(template string, values map[string]string) (string, error) {
+	if !strings.Contains(template, "{") {
+		return template, nil
+	}
+
+	var expanded strings.Builder
+
+	rest := template
+
+	for {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			expanded.WriteString(rest)
+			return expanded.String(), nil
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("%w: unterminated placeholder in %q", ErrUnboundPathParam, template)
+		}
+
+		name := rest[start+1 : start+end]
+
+		value, ok := values[name]
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrUnboundPathParam, name)
+		}
+
+		expanded.WriteString(rest[:start])
+		expanded.WriteString(url.PathEscape(value))
+		rest = rest[start+end+1:]
+	}
+}
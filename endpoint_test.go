@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type userParams struct {
+	ID   int    `path:"id" json:"-"`
+	Name string `json:"name"`
+}
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestEndpoint_BindsPathAndDecodes(t *testing.T) {
+	var gotPath, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		fmt.Fprint(w, `{"id":42,"name":"Ann"}`)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	getUser := NewEndpoint[userParams, user](c, http.MethodGet, "/users/{id}")
+	got, response, err := getUser.Call(context.Background(), userParams{ID: 42})
+	if err != nil {
+		t.Fatalf("Call error: %v", err)
+	}
+	if gotPath != "/users/42" || gotBody != "" || got.Name != "Ann" || response.StatusCode != http.StatusOK {
+		t.Fatalf("path=%s body=%s got=%+v", gotPath, gotBody, got)
+	}
+
+	updateUser := NewEndpoint[userParams, user](c, http.MethodPut, "/users/{id}")
+	if _, _, err = updateUser.Call(context.Background(), userParams{ID: 42, Name: "Ann"}); err != nil {
+		t.Fatalf("Call error: %v", err)
+	}
+	if gotBody != `{"name":"Ann"}` {
+		t.Fatalf("body=%s", gotBody)
+	}
+}
+
+func TestEndpoint_UnboundPathParam(t *testing.T) {
+	c := newTestClient(t, "http://example.com")
+
+	endpoint := NewEndpoint[userParams, user](c, http.MethodGet, "/orgs/{org}/users/{id}")
+	if _, _, err := endpoint.Call(context.Background(), userParams{ID: 1}); !errors.Is(err, ErrUnboundPathParam) {
+		t.Fatalf("expected ErrUnboundPathParam, got %v", err)
+	}
+}
+
+func TestEndpoint_EscapesPathOnceWithQuery(t *testing.T) {
+	var gotPath, gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.EscapedPath(), r.URL.RawQuery
+	}))
+	defer srv.Close()
+
+	type searchParams struct {
+		Name string `path:"name"`
+		Page int    `query:"page"`
+	}
+
+	c := newTestClient(t, srv.URL+"/api")
+
+	endpoint := NewEndpoint[searchParams, struct{}](c, http.MethodGet, "/users/{name}")
+	if _, _, err := endpoint.Call(context.Background(), searchParams{Name: "a b/c", Page: 2}); err != nil {
+		t.Fatalf("Call error: %v", err)
+	}
+	if gotPath != "/api/users/a%20b%2Fc" || gotQuery != "page=2" {
+		t.Fatalf("path=%s query=%s", gotPath, gotQuery)
+	}
+}
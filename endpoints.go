@@ -0,0 +1,404 @@
+package client
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	defaultConsecutiveFailures = 5
+	defaultEjectionTime        = 30 * time.Second
+)
+
+// OutlierDetection ejects an endpoint from rotation after ConsecutiveFailures
+// transport errors or 5xx responses in a row. Once EjectionTime has passed a
+// single probe request is let through; success puts the endpoint back into
+// rotation and failure ejects it again.
+type OutlierDetection struct {
+	ConsecutiveFailures int
+	EjectionTime        time.Duration
+}
+
+type endpoint struct {
+	url      string
+	priority int
+	weight   int
+}
+
+type endpointHealth struct {
+	consecutiveFailures int
+	ejectedUntil        time.Time
+	probing             bool
+	manuallyEjected     bool
+	successRate         float64
+	latency             time.Duration
+	observed            bool
+}
+
+type endpointPool struct {
+	mu        sync.Mutex
+	endpoints []endpoint
+	health    map[string]*endpointHealth
+	outlier   *OutlierDetection
+	logger    *zerolog.Logger
+
+	resolve    func(ctx context.Context) ([]endpoint, error)
+	onError    func(err error)
+	interval   time.Duration
+	refreshed  time.Time
+	refreshing atomic.Bool
+}
+
+// WithEndpoints adds alternative base URLs; requests are spread evenly across
+// them and the base URL passed to New.
+func WithEndpoints(urls ...string) Option {
+	return func(client *Client) error {
+		client.extraEndpoints = append(client.extraEndpoints, urls...)
+
+		return nil
+	}
+}
+
+func WithOutlierDetection(config OutlierDetection) Option {
+	return func(client *Client) error {
+		if config.ConsecutiveFailures < 1 {
+			config.ConsecutiveFailures = defaultConsecutiveFailures
+		}
+
+		if config.EjectionTime <= 0 {
+			config.EjectionTime = defaultEjectionTime
+		}
+
+		client.outlier = &config
+
+		return nil
+	}
+}
+
+func (client *Client) setupEndpoints(baseUrl string) error {
+	if isSRVUrl(baseUrl) {
+		return client.resolveSRVEndpoints(baseUrl)
+	}
+
+	if len(client.extraEndpoints) == 0 {
+		return nil
+	}
+
+	pool := &endpointPool{outlier: client.outlier, logger: client.logger}
+	endpoints := []endpoint{{url: baseUrl}}
+
+	for _, extra := range client.extraEndpoints {
+		endpoints = append(endpoints, endpoint{url: extra})
+	}
+
+	pool.set(endpoints)
+	client.endpoints = pool
+
+	return nil
+}
+
+// WithRetryOnSameEndpoint keeps retry attempts on the endpoint that served
+// the first attempt. By default each retry goes to a healthy endpoint that
+// has not been tried yet for the request, falling back to the full pool
+// once all have been tried.
+func WithRetryOnSameEndpoint() Option {
+	return func(client *Client) error {
+		client.retrySameEndpoint = true
+
+		return nil
+	}
+}
+
+// currentBaseUrl returns a base URL requests may go to without reserving
+// it, for callers that only need to know where a request would be sent.
+func (client *Client) currentBaseUrl() string {
+	if client.endpoints != nil {
+		return client.endpoints.lookup("")
+	}
+
+	return client.baseUrl
+}
+
+// selectBaseUrl picks the base URL of an attempt; every call has to be
+// followed by reportEndpoint or releaseEndpoint.
+func (client *Client) selectBaseUrl(sessionKey string, tried ...string) string {
+	if client.endpoints != nil {
+		return client.endpoints.pick(sessionKey, tried...)
+	}
+
+	return client.baseUrl
+}
+
+func (client *Client) attemptBaseUrl(ctx context.Context, spec *RequestSpec, options *requestOptions) string {
+	if client.retrySameEndpoint && len(options.triedEndpoints) > 0 {
+		return options.triedEndpoints[0]
+	}
+
+	baseUrl := client.selectBaseUrl(client.sessionKey(ctx, spec), options.triedEndpoints...)
+	options.triedEndpoints = append(options.triedEndpoints, baseUrl)
+
+	return baseUrl
+}
+
+func (client *Client) reportEndpoint(
+	baseUrl string,
+	spec *RequestSpec,
+	response *http.Response,
+	latency time.Duration,
+	err error,
+) {
+	if client.endpoints == nil {
+		return
+	}
+
+	if isAbsoluteUrl(spec.Path) {
+		client.endpoints.release(baseUrl)
+		return
+	}
+
+	client.endpoints.report(baseUrl, err != nil || response.StatusCode >= http.StatusInternalServerError, latency)
+}
+
+func (client *Client) releaseEndpoint(baseUrl string) {
+	if client.endpoints != nil {
+		client.endpoints.release(baseUrl)
+	}
+}
+
+func (pool *endpointPool) set(endpoints []endpoint) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.endpoints = endpoints
+	pool.refreshed = time.Now()
+}
+
+// pick chooses the endpoint of an attempt. An endpoint whose ejection has
+// expired is taken as the probe until the attempt is reported or released.
+func (pool *endpointPool) pick(sessionKey string, exclude ...string) string {
+	pool.refreshIfStale()
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	picked := pool.choose(sessionKey, exclude)
+
+	if health := pool.health[picked]; health != nil && !health.ejectedUntil.IsZero() {
+		health.probing = true
+	}
+
+	return picked
+}
+
+// lookup chooses an endpoint like pick but leaves the pool unchanged.
+func (pool *endpointPool) lookup(sessionKey string, exclude ...string) string {
+	pool.refreshIfStale()
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return pool.choose(sessionKey, exclude)
+}
+
+// choose must be called with pool.mu held.
+func (pool *endpointPool) choose(sessionKey string, exclude []string) string {
+	now := time.Now()
+	available := make([]endpoint, 0, len(pool.endpoints))
+
+	for _, candidate := range pool.endpoints {
+		if health := pool.health[candidate.url]; health == nil || !health.ejected(now) {
+			available = append(available, candidate)
+		}
+	}
+
+	if len(available) == 0 {
+		available = pool.endpoints
+	}
+
+	if untried := withoutEndpoints(available, exclude); len(untried) > 0 {
+		available = untried
+	}
+
+	if sessionKey != "" {
+		return pickSticky(available, sessionKey)
+	}
+
+	return pickWeighted(available)
+}
+
+func (pool *endpointPool) report(url string, failed bool, latency time.Duration) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	health := pool.healthOf(url)
+	health.observe(failed, latency)
+
+	if pool.outlier == nil {
+		return
+	}
+
+	wasProbing := health.probing
+	health.probing = false
+
+	if !failed {
+		if !health.ejectedUntil.IsZero() {
+			pool.logger.Info().
+				Str("endpoint", url).
+				Msg("endpoint returned to rotation")
+		}
+
+		health.ejectedUntil = time.Time{}
+
+		return
+	}
+
+	if wasProbing || health.consecutiveFailures >= pool.outlier.ConsecutiveFailures {
+		health.ejectedUntil = time.Now().Add(pool.outlier.EjectionTime)
+
+		pool.logger.Warn().
+			Str("endpoint", url).
+			Int("consecutive_failures", health.consecutiveFailures).
+			Dur("ejection_time", pool.outlier.EjectionTime).
+			Msg("endpoint ejected from rotation")
+	}
+}
+
+func (pool *endpointPool) release(url string) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if health := pool.health[url]; health != nil {
+		health.probing = false
+	}
+}
+
+func (health *endpointHealth) ejected(now time.Time) bool {
+	if health.manuallyEjected {
+		return true
+	}
+
+	if health.ejectedUntil.IsZero() {
+		return false
+	}
+
+	return now.Before(health.ejectedUntil) || health.probing
+}
+
+func (pool *endpointPool) refreshIfStale() {
+	if pool.resolve == nil || pool.interval <= 0 {
+		return
+	}
+
+	pool.mu.Lock()
+	stale := time.Since(pool.refreshed) > pool.interval
+	pool.mu.Unlock()
+
+	if !stale || !pool.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer pool.refreshing.Store(false)
+
+		endpoints, err := pool.resolve(context.Background())
+		if err != nil {
+			pool.onError(err)
+			return
+		}
+
+		pool.set(endpoints)
+	}()
+}
+
+// pickWeighted follows RFC 2782: the lowest priority wins and endpoints
+// within it are chosen at random proportionally to their weight.
+func pickWeighted(endpoints []endpoint) string {
+	if len(endpoints) == 0 {
+		return ""
+	}
+
+	best := endpoints[0].priority
+	for _, candidate := range endpoints {
+		if candidate.priority < best {
+			best = candidate.priority
+		}
+	}
+
+	var group []endpoint
+
+	total := 0
+
+	for _, candidate := range endpoints {
+		if candidate.priority == best {
+			group = append(group, candidate)
+			total += candidate.weight
+		}
+	}
+
+	if total == 0 {
+		return group[rand.Intn(len(group))].url //nolint:gosec
+	}
+
+	n := rand.Intn(total) //nolint:gosec
+
+	for _, candidate := range group {
+		if n < candidate.weight {
+			return candidate.url
+		}
+
+		n -= candidate.weight
+	}
+
+	return group[len(group)-1].url
+}
+
+// pickSticky uses rendezvous hashing so a session key keeps its endpoint
+// while the set of endpoints changes around it.
+func pickSticky(endpoints []endpoint, sessionKey string) string {
+	var picked string
+	var best uint64
+
+	for _, candidate := range endpoints {
+		hash := fnv.New64a()
+		_, _ = hash.Write([]byte(sessionKey))
+		_, _ = hash.Write([]byte(candidate.url))
+
+		if score := hash.Sum64(); picked == "" || score > best {
+			picked, best = candidate.url, score
+		}
+	}
+
+	return picked
+}
+
+func withoutEndpoints(endpoints []endpoint, exclude []string) []endpoint {
+	if len(exclude) == 0 {
+		return endpoints
+	}
+
+	kept := make([]endpoint, 0, len(endpoints))
+
+	for _, candidate := range endpoints {
+		excluded := false
+
+		for _, url := range exclude {
+			if candidate.url == url {
+				excluded = true
+				break
+			}
+		}
+
+		if !excluded {
+			kept = append(kept, candidate)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,177 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithOutlierDetection_EjectsAndRestoresEndpoint(t *testing.T) {
+	var healthy atomic.Bool
+	var badHits, goodHits int32
+
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&badHits, 1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer bad.Close()
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&goodHits, 1)
+	}))
+	defer good.Close()
+
+	log := zerolog.Nop()
+	c, err := New(bad.URL, nil, &log, false, "ua",
+		WithEndpoints(good.URL),
+		WithOutlierDetection(OutlierDetection{ConsecutiveFailures: 2, EjectionTime: 100 * time.Millisecond}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	for i := 0; i < 30; i++ {
+		_, _, _ = c.SendGet("/x", nil, nil)
+	}
+	if hits := atomic.LoadInt32(&badHits); hits != 2 {
+		t.Fatalf("bad endpoint should be ejected after 2 failures, hits=%d", hits)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	for i := 0; i < 10; i++ {
+		_, _, _ = c.SendGet("/x", nil, nil)
+	}
+	if hits := atomic.LoadInt32(&badHits); hits != 3 {
+		t.Fatalf("expected exactly one failed probe, hits=%d", hits)
+	}
+
+	healthy.Store(true)
+	time.Sleep(150 * time.Millisecond)
+	for i := 0; i < 40; i++ {
+		if _, _, err = c.SendGet("/x", nil, nil); err != nil {
+			t.Fatalf("SendGet error: %v", err)
+		}
+	}
+	if hits := atomic.LoadInt32(&badHits); hits < 5 {
+		t.Fatalf("recovered endpoint not back in rotation, hits=%d", hits)
+	}
+}
+
+func TestWithEndpoints_SpreadsRequests(t *testing.T) {
+	var hitsA, hitsB int32
+	a := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hitsA, 1)
+	}))
+	defer a.Close()
+	b := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hitsB, 1)
+	}))
+	defer b.Close()
+
+	log := zerolog.Nop()
+	c, err := New(a.URL, nil, &log, false, "ua", WithEndpoints(b.URL))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	for i := 0; i < 40; i++ {
+		if _, _, err = c.SendGet("/x", nil, nil); err != nil {
+			t.Fatalf("SendGet error: %v", err)
+		}
+	}
+	if atomic.LoadInt32(&hitsA) == 0 || atomic.LoadInt32(&hitsB) == 0 {
+		t.Fatalf("hits a=%d b=%d", hitsA, hitsB)
+	}
+}
+
+func TestAttemptBaseUrl_RotatesAcrossEndpoints(t *testing.T) {
+	log := zerolog.Nop()
+	spec := &RequestSpec{Method: http.MethodGet, Path: "/"}
+
+	c, err := New("http://a.invalid", nil, &log, false, "ua", WithEndpoints("http://b.invalid", "http://c.invalid"))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	options := &requestOptions{}
+	seen := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		seen[c.attemptBaseUrl(context.Background(), spec, options)] = true
+	}
+	if len(seen) != 3 {
+		t.Fatalf("attempts did not rotate: %v", options.triedEndpoints)
+	}
+	if next := c.attemptBaseUrl(context.Background(), spec, options); next == "" {
+		t.Fatal("expected a fallback endpoint once all were tried")
+	}
+
+	c, err = New("http://a.invalid", nil, &log, false, "ua",
+		WithEndpoints("http://b.invalid", "http://c.invalid"), WithRetryOnSameEndpoint())
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	options = &requestOptions{}
+	first := c.attemptBaseUrl(context.Background(), spec, options)
+	for i := 0; i < 3; i++ {
+		if got := c.attemptBaseUrl(context.Background(), spec, options); got != first {
+			t.Fatalf("attempt moved from %s to %s", first, got)
+		}
+	}
+}
+
+func TestCurrentBaseUrl_DoesNotHoldRecoveredEndpoint(t *testing.T) {
+	var healthy atomic.Bool
+	var badHits int32
+
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&badHits, 1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer bad.Close()
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer good.Close()
+
+	log := zerolog.Nop()
+	c, err := New(bad.URL, nil, &log, false, "ua",
+		WithEndpoints(good.URL),
+		WithOutlierDetection(OutlierDetection{ConsecutiveFailures: 1, EjectionTime: 50 * time.Millisecond}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		_, _, _ = c.SendGet("/x", nil, nil)
+	}
+
+	healthy.Store(true)
+	time.Sleep(80 * time.Millisecond)
+
+	for i := 0; i < 20; i++ {
+		c.currentBaseUrl()
+	}
+
+	session := c.NewSession(context.Background())
+	for i := 0; i < 5; i++ {
+		if _, err = session.Send(RequestSpec{Method: http.MethodGet, Path: "/x"}); err != nil {
+			t.Fatalf("Session.Send error: %v", err)
+		}
+	}
+
+	before := atomic.LoadInt32(&badHits)
+	for i := 0; i < 40; i++ {
+		if _, _, err = c.SendGet("/x", nil, nil); err != nil {
+			t.Fatalf("SendGet error: %v", err)
+		}
+	}
+	if atomic.LoadInt32(&badHits) == before {
+		t.Fatal("recovered endpoint got no traffic")
+	}
+}
@@ -0,0 +1,54 @@
+package client
+
+import "encoding/json"
+
+const (
+	defaultEnvelopeData  = "data"
+	defaultEnvelopeMeta  = "meta"
+	defaultEnvelopeLinks = "links"
+)
+
+// EnvelopeFields names the keys of a wrapped response body. Empty fields fall
+// back to "data", "meta" and "links".
+type EnvelopeFields struct {
+	Data  string
+	Meta  string
+	Links string
+}
+
+func (fields *EnvelopeFields) unwrap(response *Response) {
+	var envelope map[string]json.RawMessage
+
+	if err := json.Unmarshal(response.Body, &envelope); err != nil {
+		return
+	}
+
+	data, ok := envelope[fieldOrDefault(fields.Data, defaultEnvelopeData)]
+	if !ok {
+		return
+	}
+
+	if raw, found := envelope[fieldOrDefault(fields.Meta, defaultEnvelopeMeta)]; found {
+		var meta MetaResponse
+		if json.Unmarshal(raw, &meta) == nil {
+			response.Pagination = &meta
+		}
+	}
+
+	if raw, found := envelope[fieldOrDefault(fields.Links, defaultEnvelopeLinks)]; found {
+		var links LinksResponse
+		if json.Unmarshal(raw, &links) == nil {
+			response.Links = &links
+		}
+	}
+
+	response.Body = data
+}
+
+func fieldOrDefault(field, fallback string) string {
+	if field == "" {
+		return fallback
+	}
+
+	return field
+}
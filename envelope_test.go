@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestEnvelopeUnwrapping_DefaultFields(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"data":[{"id":1}],"meta":{"totalCount":5,"pageCount":3,"currentPage":1,"perPage":2},`+
+			`"links":{"next":{"href":"/items?page=2"}}}`)
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithEnvelopeUnwrapping(EnvelopeFields{}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	resp, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/items"})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if string(resp.Body) != `[{"id":1}]` {
+		t.Fatalf("body=%s", resp.Body)
+	}
+	if resp.Pagination == nil || resp.Pagination.TotalCount != 5 || resp.Pagination.PageCount != 3 {
+		t.Fatalf("pagination=%+v", resp.Pagination)
+	}
+	if resp.Links == nil || resp.Links.Next.Href != "/items?page=2" {
+		t.Fatalf("links=%+v", resp.Links)
+	}
+}
+
+func TestEnvelopeUnwrapping_CustomFieldsAndPassthrough(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/plain" {
+			io.WriteString(w, `[1,2]`)
+			return
+		}
+		io.WriteString(w, `{"items":[1],"_meta":{"currentPage":2}}`)
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithEnvelopeUnwrapping(EnvelopeFields{
+		Data: "items",
+		Meta: "_meta",
+	}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	body, _, err := c.SendGet("/wrapped", nil, nil)
+	if err != nil || string(body) != `[1]` {
+		t.Fatalf("wrapped body=%s err=%v", body, err)
+	}
+
+	body, _, err = c.SendGet("/plain", nil, nil)
+	if err != nil || string(body) != `[1,2]` {
+		t.Fatalf("plain body=%s err=%v", body, err)
+	}
+}
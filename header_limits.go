@@ -0,0 +1,56 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var ErrHeadersTooLarge = errors.New("request headers exceed limit")
+
+// HeaderLimits bounds outgoing request headers; zero fields are not
+// checked. TotalBytes counts every "Name: value\r\n" line, CookieBytes
+// the Cookie header values and Count the number of header lines.
+type HeaderLimits struct {
+	TotalBytes  int
+	CookieBytes int
+	Count       int
+}
+
+func WithHeaderLimits(limits HeaderLimits) Option {
+	return func(client *Client) error {
+		client.headerLimits = &limits
+
+		return nil
+	}
+}
+
+func (limits *HeaderLimits) check(header http.Header) error {
+	if limits == nil {
+		return nil
+	}
+
+	total, cookies, count := 0, 0, 0
+
+	for name, vals := range header {
+		for _, val := range vals {
+			total += len(name) + len(": ") + len(val) + len("\r\n")
+			count++
+
+			if name == "Cookie" {
+				cookies += len(val)
+			}
+		}
+	}
+
+	switch {
+	case limits.Count > 0 && count > limits.Count:
+		return fmt.Errorf("%w: %d headers, limit %d", ErrHeadersTooLarge, count, limits.Count)
+	case limits.CookieBytes > 0 && cookies > limits.CookieBytes:
+		return fmt.Errorf("%w: cookies are %d bytes, limit %d", ErrHeadersTooLarge, cookies, limits.CookieBytes)
+	case limits.TotalBytes > 0 && total > limits.TotalBytes:
+		return fmt.Errorf("%w: %d bytes, limit %d", ErrHeadersTooLarge, total, limits.TotalBytes)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithHeaderLimits(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithHeaderLimits(HeaderLimits{TotalBytes: 256, CookieBytes: 32, Count: 5}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	cases := []MultiHeaders{
+		{"X-Big": {strings.Repeat("a", 300)}},
+		{"Cookie": {"session=" + strings.Repeat("b", 40)}},
+		{"A": {"1"}, "B": {"2"}, "C": {"3"}, "D": {"4"}, "E": {"5"}, "F": {"6"}},
+	}
+	for i, headers := range cases {
+		_, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/", Headers: headers})
+		if !errors.Is(err, ErrHeadersTooLarge) {
+			t.Errorf("case %d: expected ErrHeadersTooLarge, got %v", i, err)
+		}
+	}
+	if atomic.LoadInt32(&hits) != 0 {
+		t.Fatalf("oversized requests reached the server")
+	}
+
+	if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// PaginationHeaders names the response headers read into
+// Response.Pagination. Empty fields fall back to X-Total-Count, X-Page,
+// X-Per-Page and X-Total-Pages. PageCount is derived from TotalCount and
+// PerPage when its header is missing.
+type PaginationHeaders struct {
+	TotalCount  string
+	CurrentPage string
+	PerPage     string
+	PageCount   string
+}
+
+var defaultPaginationHeaders = PaginationHeaders{
+	TotalCount:  "X-Total-Count",
+	CurrentPage: "X-Page",
+	PerPage:     "X-Per-Page",
+	PageCount:   "X-Total-Pages",
+}
+
+// WithPaginationHeaders renames the headers pagination metadata is read
+// from. A body envelope, when configured and present, takes precedence.
+func WithPaginationHeaders(headers PaginationHeaders) Option {
+	return func(client *Client) error {
+		client.paginationHeaders = &headers
+
+		return nil
+	}
+}
+
+func (client *Client) headerPagination(header http.Header) *MetaResponse {
+	names := defaultPaginationHeaders
+	if client.paginationHeaders != nil {
+		names.TotalCount = fieldOrDefault(client.paginationHeaders.TotalCount, names.TotalCount)
+		names.CurrentPage = fieldOrDefault(client.paginationHeaders.CurrentPage, names.CurrentPage)
+		names.PerPage = fieldOrDefault(client.paginationHeaders.PerPage, names.PerPage)
+		names.PageCount = fieldOrDefault(client.paginationHeaders.PageCount, names.PageCount)
+	}
+
+	meta := &MetaResponse{}
+	found := false
+
+	for name, target := range map[string]*int{
+		names.TotalCount:  &meta.TotalCount,
+		names.CurrentPage: &meta.CurrentPage,
+		names.PerPage:     &meta.PerPage,
+		names.PageCount:   &meta.PageCount,
+	} {
+		if value, err := strconv.Atoi(header.Get(name)); err == nil {
+			*target = value
+			found = true
+		}
+	}
+
+	if !found {
+		return nil
+	}
+
+	if meta.PageCount == 0 && meta.PerPage > 0 {
+		meta.PageCount = (meta.TotalCount + meta.PerPage - 1) / meta.PerPage
+	}
+
+	return meta
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestHeaderPagination_Defaults(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Total-Count", "45")
+		w.Header().Set("X-Page", "2")
+		w.Header().Set("X-Per-Page", "20")
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	response, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/items"})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	want := MetaResponse{TotalCount: 45, CurrentPage: 2, PerPage: 20, PageCount: 3}
+	if response.Pagination == nil || *response.Pagination != want {
+		t.Fatalf("pagination=%+v", response.Pagination)
+	}
+}
+
+func TestHeaderPagination_CustomNamesAndAbsent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/paged" {
+			w.Header().Set("Total", "7")
+		}
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithPaginationHeaders(PaginationHeaders{TotalCount: "Total"}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	response, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/paged"})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if response.Pagination == nil || response.Pagination.TotalCount != 7 {
+		t.Fatalf("pagination=%+v", response.Pagination)
+	}
+
+	response, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/plain"})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if response.Pagination != nil {
+		t.Fatalf("pagination=%+v", response.Pagination)
+	}
+}
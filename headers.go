@@ -3,6 +3,8 @@ package client
 const (
 	ContentTypeHeader = "Content-Type"
 	ContentTypeJson   = "application/json"
+	ContentTypeXml    = "application/xml"
+	ContentTypeForm   = "application/x-www-form-urlencoded"
 
 	AuthorizationHeader = "Authorization"
 )
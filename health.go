@@ -0,0 +1,151 @@
+package client
+
+import (
+	"errors"
+	"time"
+)
+
+// healthDecay is the weight of the newest observation in the success rate
+// and latency moving averages.
+const healthDecay = 0.2
+
+var ErrUnknownEndpoint = errors.New("unknown endpoint")
+
+// EndpointStatus is a snapshot of an endpoint's health. SuccessRate and
+// Latency are exponentially weighted moving averages, so recent requests
+// count more than old ones.
+type EndpointStatus struct {
+	URL                 string
+	SuccessRate         float64
+	Latency             time.Duration
+	ConsecutiveFailures int
+	Ejected             bool
+	// EjectedUntil is zero for manual ejections, which last until
+	// ReinstateEndpoint.
+	EjectedUntil time.Time
+}
+
+// EndpointHealth reports the health of every endpoint in rotation. It is
+// empty for clients with a single base URL.
+func (client *Client) EndpointHealth() []EndpointStatus {
+	if client.endpoints == nil {
+		return nil
+	}
+
+	return client.endpoints.status()
+}
+
+// EjectEndpoint takes url out of rotation until ReinstateEndpoint is called.
+// Requests still reach it if every endpoint is ejected.
+func (client *Client) EjectEndpoint(url string) error {
+	return client.setEjected(url, true)
+}
+
+// ReinstateEndpoint puts url back into rotation, clearing both manual and
+// outlier detection ejections.
+func (client *Client) ReinstateEndpoint(url string) error {
+	return client.setEjected(url, false)
+}
+
+func (client *Client) setEjected(url string, ejected bool) error {
+	if client.endpoints == nil || !client.endpoints.has(url) {
+		return ErrUnknownEndpoint
+	}
+
+	client.endpoints.mu.Lock()
+	health := client.endpoints.healthOf(url)
+	health.manuallyEjected = ejected
+
+	if !ejected {
+		health.ejectedUntil = time.Time{}
+		health.consecutiveFailures = 0
+		health.probing = false
+	}
+	client.endpoints.mu.Unlock()
+
+	client.logger.Info().
+		Str("endpoint", url).
+		Bool("ejected", ejected).
+		Msg("endpoint rotation changed manually")
+
+	return nil
+}
+
+func (pool *endpointPool) has(url string) bool {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	for _, candidate := range pool.endpoints {
+		if candidate.url == url {
+			return true
+		}
+	}
+
+	return false
+}
+
+// healthOf must be called with pool.mu held.
+func (pool *endpointPool) healthOf(url string) *endpointHealth {
+	if pool.health == nil {
+		pool.health = map[string]*endpointHealth{}
+	}
+
+	health, ok := pool.health[url]
+	if !ok {
+		health = &endpointHealth{}
+		pool.health[url] = health
+	}
+
+	return health
+}
+
+func (pool *endpointPool) status() []EndpointStatus {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	now := time.Now()
+	statuses := make([]EndpointStatus, 0, len(pool.endpoints))
+
+	for _, candidate := range pool.endpoints {
+		status := EndpointStatus{URL: candidate.url, SuccessRate: 1}
+
+		if health := pool.health[candidate.url]; health != nil {
+			if health.observed {
+				status.SuccessRate = health.successRate
+				status.Latency = health.latency
+			}
+
+			status.ConsecutiveFailures = health.consecutiveFailures
+			status.Ejected = health.manuallyEjected || now.Before(health.ejectedUntil)
+
+			if !health.manuallyEjected {
+				status.EjectedUntil = health.ejectedUntil
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+func (health *endpointHealth) observe(failed bool, latency time.Duration) {
+	success := 1.0
+	if failed {
+		success = 0
+		health.consecutiveFailures++
+	} else {
+		health.consecutiveFailures = 0
+	}
+
+	if !health.observed {
+		health.successRate = success
+		health.latency = latency
+		health.observed = true
+
+		return
+	}
+
+	health.successRate += healthDecay * (success - health.successRate)
+	health.latency += time.Duration(healthDecay * float64(latency-health.latency))
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestEndpointHealth_TracksFailuresAndManualEjection(t *testing.T) {
+	var badHits int32
+
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&badHits, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer bad.Close()
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer good.Close()
+
+	log := zerolog.Nop()
+	c, err := New(bad.URL, nil, &log, false, "ua", WithEndpoints(good.URL))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		_, _, _ = c.SendGet("/x", nil, nil)
+	}
+
+	statuses := c.EndpointHealth()
+	if len(statuses) != 2 {
+		t.Fatalf("statuses=%+v", statuses)
+	}
+	for _, status := range statuses {
+		switch status.URL {
+		case bad.URL:
+			if status.SuccessRate != 0 || status.ConsecutiveFailures == 0 || status.Latency <= 0 {
+				t.Fatalf("bad=%+v", status)
+			}
+		case good.URL:
+			if status.SuccessRate != 1 || status.ConsecutiveFailures != 0 {
+				t.Fatalf("good=%+v", status)
+			}
+		}
+	}
+
+	if err = c.EjectEndpoint(bad.URL); err != nil {
+		t.Fatalf("EjectEndpoint error: %v", err)
+	}
+	hits := atomic.LoadInt32(&badHits)
+	for i := 0; i < 20; i++ {
+		if _, _, err = c.SendGet("/x", nil, nil); err != nil {
+			t.Fatalf("SendGet error: %v", err)
+		}
+	}
+	if atomic.LoadInt32(&badHits) != hits {
+		t.Fatal("ejected endpoint still received requests")
+	}
+
+	if err = c.ReinstateEndpoint(bad.URL); err != nil {
+		t.Fatalf("ReinstateEndpoint error: %v", err)
+	}
+	for i := 0; i < 40; i++ {
+		_, _, _ = c.SendGet("/x", nil, nil)
+	}
+	if atomic.LoadInt32(&badHits) == hits {
+		t.Fatal("reinstated endpoint received no requests")
+	}
+
+	if err = c.EjectEndpoint("http://unknown"); !errors.Is(err, ErrUnknownEndpoint) {
+		t.Fatalf("expected ErrUnknownEndpoint, got %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package client
+
+import "net/http"
+
+// OnRequest registers hook to run on every attempt once the request is
+// prepared, before it is signed and sent. Headers it sets are sent and
+// signed like any other; an error aborts the attempt. Hooks are not safe to
+// register while requests are in flight.
+func (client *Client) OnRequest(hook func(*http.Request) error) *Client {
+	client.requestHooks = append(client.requestHooks, hook)
+
+	return client
+}
+
+// OnResponse registers hook to run after every attempt that was sent, with
+// the result and error the attempt returned.
+func (client *Client) OnResponse(hook func(*http.Request, *Response, error)) *Client {
+	client.responseHooks = append(client.responseHooks, hook)
+
+	return client
+}
+
+func (client *Client) runRequestHooks(request *http.Request) error {
+	for _, hook := range client.requestHooks {
+		if err := hook(request); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (client *Client) runResponseHooks(request *http.Request, result *Response, err error) {
+	for _, hook := range client.responseHooks {
+		hook(request, result, err)
+	}
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHooks_InjectHeadersAndObserveResponses(t *testing.T) {
+	var gotHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Dynamic")
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	var seenStatus int
+	var seenErr error
+	var seenURL string
+
+	c.OnRequest(func(r *http.Request) error {
+		r.Header.Set("X-Dynamic", "v1")
+		return nil
+	}).OnResponse(func(r *http.Request, resp *Response, err error) {
+		seenURL = r.URL.String()
+		seenStatus = resp.StatusCode
+		seenErr = err
+	})
+
+	_, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/hooked"})
+	if !errors.Is(err, ErrRequestFailed) {
+		t.Fatalf("err=%v", err)
+	}
+
+	if gotHeader != "v1" || seenURL != srv.URL+"/hooked" || seenStatus != http.StatusTeapot || !errors.Is(seenErr, ErrRequestFailed) {
+		t.Fatalf("header=%q url=%q status=%d err=%v", gotHeader, seenURL, seenStatus, seenErr)
+	}
+}
+
+func TestOnRequest_ErrorAbortsAttempt(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	denied := errors.New("denied")
+	c.OnRequest(func(*http.Request) error { return denied })
+
+	if _, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); !errors.Is(err, denied) {
+		t.Fatalf("err=%v", err)
+	}
+	if called {
+		t.Fatal("request was sent despite hook error")
+	}
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"net"
+	"net/url"
+
+	"golang.org/x/net/idna"
+)
+
+// asciiHost rewrites an internationalized host of u to its punycode form
+// and reports whether anything changed.
+func asciiHost(u *url.URL) (bool, error) {
+	hostname := u.Hostname()
+	if isASCII(hostname) {
+		return false, nil
+	}
+
+	encoded, err := idna.Lookup.ToASCII(hostname)
+	if err != nil {
+		return false, err
+	}
+
+	if port := u.Port(); port != "" {
+		encoded = net.JoinHostPort(encoded, port)
+	}
+
+	u.Host = encoded
+
+	return true, nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+
+	return true
+}
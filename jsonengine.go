@@ -0,0 +1,143 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+var (
+	ErrDecodeOptionsUnsupported = errors.New("json engine does not support decode options")
+	ErrTrailingJSON             = errors.New("unexpected data after top-level json value")
+)
+
+// JSONEngine lets high-throughput users plug a faster JSON implementation,
+// such as sonic or json-iterator, into the typed helpers without this package
+// depending on it.
+type JSONEngine interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONDecoder is implemented by engines that honour DecodeOptions. Engines
+// without it fail DecodeJSON once any option is set; Unmarshal is still used
+// when none are.
+type JSONDecoder interface {
+	UnmarshalWith(data []byte, v any, options DecodeOptions) error
+}
+
+// DecodeOptions tune DecodeJSON. The zero value is tolerant: fields the
+// target type does not declare are ignored, so servers can add fields
+// without breaking callers.
+type DecodeOptions struct {
+	// Strict fails on unknown fields to surface contract drift early.
+	Strict bool
+	// UseNumber decodes numbers into any as json.Number instead of float64,
+	// so int64 IDs keep every digit.
+	UseNumber bool
+}
+
+// StdJSON is the encoding/json engine used by default.
+type StdJSON struct{}
+
+func (StdJSON) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+func (StdJSON) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+func (StdJSON) UnmarshalWith(data []byte, v any, options DecodeOptions) error {
+	if options == (DecodeOptions{}) {
+		return json.Unmarshal(data, v)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	if options.Strict {
+		decoder.DisallowUnknownFields()
+	}
+
+	if options.UseNumber {
+		decoder.UseNumber()
+	}
+
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return ErrTrailingJSON
+	}
+
+	return nil
+}
+
+// WithJSONEngine sets the engine used for JSON request bodies and DecodeJSON.
+func WithJSONEngine(engine JSONEngine) Option {
+	return func(client *Client) error {
+		client.jsonEngine = engine
+
+		return nil
+	}
+}
+
+// WithStrictDecoding makes DecodeJSON and the typed helpers fail on fields
+// the target type does not declare. StrictDecoding overrides it per request.
+func WithStrictDecoding(strict bool) Option {
+	return func(client *Client) error {
+		client.decodeOptions.Strict = strict
+
+		return nil
+	}
+}
+
+// WithUseNumber makes DecodeJSON and the typed helpers decode numbers held
+// in interface values as json.Number.
+func WithUseNumber() Option {
+	return func(client *Client) error {
+		client.decodeOptions.UseNumber = true
+
+		return nil
+	}
+}
+
+// StrictDecoding overrides WithStrictDecoding when the response is decoded
+// with DecodeJSON.
+func StrictDecoding(strict bool) RequestOption {
+	return func(options *requestOptions) {
+		options.strictDecoding = &strict
+	}
+}
+
+// DecodeJSON unmarshals the response body into out with the client's engine.
+func (client *Client) DecodeJSON(response *Response, out any) error {
+	options := client.decodeOptions
+
+	if response.strictDecoding != nil {
+		options.Strict = *response.strictDecoding
+	}
+
+	return client.unmarshalJSON(response.Body, out, options)
+}
+
+func (client *Client) unmarshalJSON(data []byte, out any, options DecodeOptions) error {
+	engine := client.json()
+
+	if options == (DecodeOptions{}) {
+		return engine.Unmarshal(data, out)
+	}
+
+	decoder, ok := engine.(JSONDecoder)
+	if !ok {
+		return ErrDecodeOptionsUnsupported
+	}
+
+	return decoder.UnmarshalWith(data, out, options)
+}
+
+func (client *Client) json() JSONEngine {
+	if client.jsonEngine == nil {
+		return StdJSON{}
+	}
+
+	return client.jsonEngine
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type countingEngine struct {
+	StdJSON
+	marshals, unmarshals int
+}
+
+func (engine *countingEngine) Marshal(v any) ([]byte, error) {
+	engine.marshals++
+	return engine.StdJSON.Marshal(v)
+}
+
+func (engine *countingEngine) Unmarshal(data []byte, v any) error {
+	engine.unmarshals++
+	return engine.StdJSON.Unmarshal(data, v)
+}
+
+func TestWithJSONEngine_UsedForBodiesAndDecoding(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(w, r.Body)
+	}))
+	defer srv.Close()
+
+	engine := &countingEngine{}
+	c, err := NewSimple(srv.URL, WithJSONEngine(engine))
+	if err != nil {
+		t.Fatalf("NewSimple error: %v", err)
+	}
+
+	var out struct{ ID int }
+	if err = c.PostJSON("/echo", map[string]int{"id": 7}, &out); err != nil {
+		t.Fatalf("PostJSON error: %v", err)
+	}
+	if out.ID != 7 || engine.marshals != 1 || engine.unmarshals != 1 {
+		t.Fatalf("out=%+v engine=%+v", out, engine)
+	}
+
+	response, err := c.Client.Send(context.Background(), RequestSpec{Method: http.MethodPost, Path: "/echo"}, JSONBody(map[string]int{"id": 8}))
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if err = c.Client.DecodeJSON(response, &out); err != nil {
+		t.Fatalf("DecodeJSON error: %v", err)
+	}
+	if out.ID != 8 || engine.marshals != 2 || engine.unmarshals != 2 {
+		t.Fatalf("out=%+v engine=%+v", out, engine)
+	}
+}
+
+func TestStrictDecoding_GlobalAndPerRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":1,"added":true}`))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	spec := RequestSpec{Method: http.MethodGet, Path: "/"}
+
+	var out struct{ ID int }
+
+	resp, err := c.Send(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if err = c.DecodeJSON(resp, &out); err != nil || out.ID != 1 {
+		t.Fatalf("tolerant decode: out=%+v err=%v", out, err)
+	}
+
+	resp, _ = c.Send(context.Background(), spec, StrictDecoding(true))
+	if err = c.DecodeJSON(resp, &out); err == nil {
+		t.Fatalf("strict request decoded unknown field")
+	}
+
+	if err = WithStrictDecoding(true)(c); err != nil {
+		t.Fatalf("WithStrictDecoding error: %v", err)
+	}
+
+	resp, _ = c.Send(context.Background(), spec)
+	if err = c.DecodeJSON(resp, &out); err == nil {
+		t.Fatalf("strict client decoded unknown field")
+	}
+
+	resp, _ = c.Send(context.Background(), spec, StrictDecoding(false))
+	if err = c.DecodeJSON(resp, &out); err != nil {
+		t.Fatalf("tolerant request override: %v", err)
+	}
+}
+
+type plainEngine struct{}
+
+func (plainEngine) Marshal(v any) ([]byte, error) { return StdJSON{}.Marshal(v) }
+
+func (plainEngine) Unmarshal(data []byte, v any) error { return StdJSON{}.Unmarshal(data, v) }
+
+func TestStrictDecoding_UnsupportedEngine(t *testing.T) {
+	c := newTestClient(t, "http://example.com")
+	c.jsonEngine = plainEngine{}
+	c.decodeOptions.Strict = true
+
+	var out struct{ ID int }
+	if err := c.DecodeJSON(&Response{Body: []byte(`{"id":1}`)}, &out); err != ErrDecodeOptionsUnsupported {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestStdJSON_StrictRejectsTrailingData(t *testing.T) {
+	var out struct{ ID int }
+	if err := (StdJSON{}).UnmarshalWith([]byte(`{"id":1} {}`), &out, DecodeOptions{Strict: true}); err != ErrTrailingJSON {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestWithUseNumber_KeepsInt64Precision(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":9007199254740993}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewSimple(srv.URL, WithUseNumber())
+	if err != nil {
+		t.Fatalf("NewSimple error: %v", err)
+	}
+
+	var out map[string]any
+	if err = c.GetJSON("/", &out); err != nil {
+		t.Fatalf("GetJSON error: %v", err)
+	}
+
+	id, ok := out["id"].(json.Number)
+	if !ok || id.String() != "9007199254740993" {
+		t.Fatalf("id=%#v", out["id"])
+	}
+}
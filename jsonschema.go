@@ -0,0 +1,306 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// JSONSchema is a compiled subset of JSON Schema: type, properties, required,
+// additionalProperties, items, enum, minimum/maximum, minLength/maxLength,
+// minItems/maxItems and pattern. Unknown keywords are ignored, and so is
+// $ref outside OpenAPI documents.
+type JSONSchema struct {
+	Ref                  string                 `json:"$ref"`
+	Type                 schemaTypes            `json:"type"`
+	Properties           map[string]*JSONSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *JSONSchema            `json:"items"`
+	Enum                 []any                  `json:"enum"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	MinItems             *int                   `json:"minItems"`
+	MaxItems             *int                   `json:"maxItems"`
+	Pattern              string                 `json:"pattern"`
+
+	pattern *regexp.Regexp
+	// target is the schema Ref points at; it replaces the other keywords.
+	target *JSONSchema
+}
+
+type schemaTypes []string
+
+func (types *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+
+	if err := json.Unmarshal(data, &single); err == nil {
+		*types = schemaTypes{single}
+		return nil
+	}
+
+	var many []string
+
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+
+	*types = many
+
+	return nil
+}
+
+type SchemaViolation struct {
+	Path    string
+	Message string
+}
+
+type SchemaError struct {
+	Violations []SchemaViolation
+}
+
+func (e *SchemaError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Path+": "+violation.Message)
+	}
+
+	return "schema validation failed: " + strings.Join(messages, "; ")
+}
+
+func CompileJSONSchema(raw []byte) (*JSONSchema, error) {
+	var schema JSONSchema
+
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, err
+	}
+
+	if err := schema.compile(nil); err != nil {
+		return nil, err
+	}
+
+	return &schema, nil
+}
+
+func (schema *JSONSchema) compile(refs *schemaRefs) error {
+	if schema.Ref != "" && refs != nil {
+		target, err := refs.resolve(schema.Ref)
+		if err != nil {
+			return err
+		}
+
+		schema.target = target
+
+		return nil
+	}
+
+	if schema.Pattern != "" {
+		pattern, err := regexp.Compile(schema.Pattern)
+		if err != nil {
+			return err
+		}
+
+		schema.pattern = pattern
+	}
+
+	for _, property := range schema.Properties {
+		if err := property.compile(refs); err != nil {
+			return err
+		}
+	}
+
+	if schema.Items != nil {
+		return schema.Items.compile(refs)
+	}
+
+	return nil
+}
+
+func (schema *JSONSchema) Validate(data []byte) error {
+	var value any
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return &SchemaError{Violations: []SchemaViolation{{Path: "$", Message: err.Error()}}}
+	}
+
+	return schema.ValidateValue(value)
+}
+
+func (schema *JSONSchema) ValidateValue(value any) error {
+	var violations []SchemaViolation
+
+	schema.validate("$", value, &violations)
+
+	if len(violations) > 0 {
+		return &SchemaError{Violations: violations}
+	}
+
+	return nil
+}
+
+func JSONSchemaValidator(schema *JSONSchema) func(*Response) error {
+	return func(response *Response) error {
+		return schema.Validate(response.Body)
+	}
+}
+
+// resolved follows Ref to the schema that actually holds the keywords.
+func (schema *JSONSchema) resolved() *JSONSchema {
+	for schema.target != nil {
+		schema = schema.target
+	}
+
+	return schema
+}
+
+func (schema *JSONSchema) validate(path string, value any, violations *[]SchemaViolation) {
+	schema = schema.resolved()
+
+	report := func(format string, args ...any) {
+		*violations = append(*violations, SchemaViolation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(schema.Type) > 0 && !schema.matchesType(value) {
+		report("expected %s, got %s", strings.Join(schema.Type, " or "), jsonTypeOf(value))
+		return
+	}
+
+	if len(schema.Enum) > 0 && !containsValue(schema.Enum, value) {
+		report("value is not one of the allowed values")
+	}
+
+	switch typed := value.(type) {
+	case map[string]any:
+		schema.validateObject(path, typed, violations)
+	case []any:
+		schema.validateArray(path, typed, violations)
+	case string:
+		length := utf8.RuneCountInString(typed)
+
+		if schema.MinLength != nil && length < *schema.MinLength {
+			report("length %d is less than %d", length, *schema.MinLength)
+		}
+
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			report("length %d is greater than %d", length, *schema.MaxLength)
+		}
+
+		if schema.pattern != nil && !schema.pattern.MatchString(typed) {
+			report("does not match pattern %q", schema.Pattern)
+		}
+	case float64:
+		if schema.Minimum != nil && typed < *schema.Minimum {
+			report("%v is less than %v", typed, *schema.Minimum)
+		}
+
+		if schema.Maximum != nil && typed > *schema.Maximum {
+			report("%v is greater than %v", typed, *schema.Maximum)
+		}
+	}
+}
+
+func (schema *JSONSchema) validateObject(path string, object map[string]any, violations *[]SchemaViolation) {
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			*violations = append(*violations, SchemaViolation{Path: path + "." + name, Message: "is required"})
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := schema.Properties[name]
+
+		switch {
+		case ok:
+			property.validate(path+"."+name, object[name], violations)
+		case schema.AdditionalProperties != nil && !*schema.AdditionalProperties:
+			*violations = append(*violations, SchemaViolation{Path: path + "." + name, Message: "is not allowed"})
+		}
+	}
+}
+
+func (schema *JSONSchema) validateArray(path string, list []any, violations *[]SchemaViolation) {
+	if schema.MinItems != nil && len(list) < *schema.MinItems {
+		*violations = append(*violations, SchemaViolation{
+			Path:    path,
+			Message: fmt.Sprintf("has %d items, less than %d", len(list), *schema.MinItems),
+		})
+	}
+
+	if schema.MaxItems != nil && len(list) > *schema.MaxItems {
+		*violations = append(*violations, SchemaViolation{
+			Path:    path,
+			Message: fmt.Sprintf("has %d items, more than %d", len(list), *schema.MaxItems),
+		})
+	}
+
+	if schema.Items == nil {
+		return
+	}
+
+	for i, item := range list {
+		schema.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, violations)
+	}
+}
+
+func (schema *JSONSchema) matchesType(value any) bool {
+	actual := jsonTypeOf(value)
+
+	for _, expected := range schema.Type {
+		if expected == actual {
+			return true
+		}
+
+		if expected == "number" && actual == "integer" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func jsonTypeOf(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case float64:
+		if typed == float64(int64(typed)) {
+			return "integer"
+		}
+
+		return "number"
+	default:
+		return "unknown"
+	}
+}
+
+func containsValue(values []any, value any) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+const testUserSchema = `{
+	"type": "object",
+	"required": ["id", "name"],
+	"additionalProperties": false,
+	"properties": {
+		"id": {"type": "integer", "minimum": 1},
+		"name": {"type": "string", "minLength": 2},
+		"role": {"enum": ["admin", "user"]},
+		"tags": {"type": "array", "maxItems": 2, "items": {"type": "string"}}
+	}
+}`
+
+func TestJSONSchema_Validate(t *testing.T) {
+	schema, err := CompileJSONSchema([]byte(testUserSchema))
+	if err != nil {
+		t.Fatalf("CompileJSONSchema error: %v", err)
+	}
+
+	if err = schema.Validate([]byte(`{"id":1,"name":"bob","role":"admin","tags":["a"]}`)); err != nil {
+		t.Fatalf("valid document rejected: %v", err)
+	}
+
+	err = schema.Validate([]byte(`{"id":0.5,"role":"root","tags":["a",1,"c"],"extra":true}`))
+
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("expected SchemaError, got %v", err)
+	}
+
+	want := map[string]bool{
+		"$.name":    true,
+		"$.id":      true,
+		"$.role":    true,
+		"$.tags":    true,
+		"$.tags[1]": true,
+		"$.extra":   true,
+	}
+	for _, violation := range schemaErr.Violations {
+		delete(want, violation.Path)
+	}
+	if len(want) != 0 {
+		t.Fatalf("missing violations for %v in %v", want, schemaErr.Violations)
+	}
+}
+
+func TestWithResponseValidator_SurfacesTypedError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"id":"1"}`)
+	}))
+	defer srv.Close()
+
+	schema, err := CompileJSONSchema([]byte(testUserSchema))
+	if err != nil {
+		t.Fatalf("CompileJSONSchema error: %v", err)
+	}
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithResponseValidator(JSONSchemaValidator(schema)))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	resp, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/user"})
+
+	var validationErr *ResponseValidationError
+	if !errors.As(err, &validationErr) || validationErr.StatusCode != http.StatusOK {
+		t.Fatalf("expected ResponseValidationError, got %v", err)
+	}
+
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("expected wrapped SchemaError, got %v", err)
+	}
+	if resp == nil || string(resp.Body) != `{"id":"1"}` {
+		t.Fatalf("response must be returned alongside validation error")
+	}
+}
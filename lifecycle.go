@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrClientClosed    = errors.New("http client is closed")
+	ErrRequestCanceled = errors.New("http request canceled by client")
+)
+
+type lifecycle struct {
+	mu      sync.Mutex
+	closed  bool
+	active  int
+	idle    chan struct{}
+	nextID  uint64
+	cancels map[uint64]context.CancelCauseFunc
+}
+
+// Drain stops accepting new requests, which then fail with ErrClientClosed,
+// waits for in-flight requests until ctx is done and closes idle connections.
+func (client *Client) Drain(ctx context.Context) error {
+	var err error
+
+	select {
+	case <-client.lifecycle.close():
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	client.httpClient.CloseIdleConnections()
+
+	return err
+}
+
+// CancelAll aborts every in-flight request; they fail with
+// ErrRequestCanceled. New requests are still accepted.
+func (client *Client) CancelAll() {
+	client.lifecycle.cancelAll()
+}
+
+// Close stops accepting new requests. Without force it behaves like Drain
+// with no deadline; with force it cancels in-flight requests first.
+func (client *Client) Close(force bool) error {
+	if force {
+		idle := client.lifecycle.close()
+		client.lifecycle.cancelAll()
+		<-idle
+
+		client.httpClient.CloseIdleConnections()
+
+		return nil
+	}
+
+	return client.Drain(context.Background())
+}
+
+func (l *lifecycle) enter(ctx context.Context) (context.Context, uint64, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return ctx, 0, false
+	}
+
+	if l.cancels == nil {
+		l.cancels = map[uint64]context.CancelCauseFunc{}
+	}
+
+	l.nextID++
+	l.active++
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	l.cancels[l.nextID] = cancel
+
+	return ctx, l.nextID, true
+}
+
+func (l *lifecycle) leave(id uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if cancel, ok := l.cancels[id]; ok {
+		cancel(nil)
+		delete(l.cancels, id)
+	}
+
+	l.active--
+
+	if l.closed && l.active == 0 && l.idle != nil {
+		close(l.idle)
+		l.idle = nil
+	}
+}
+
+func (l *lifecycle) close() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.closed = true
+
+	idle := make(chan struct{})
+
+	if l.active == 0 {
+		close(idle)
+		return idle
+	}
+
+	if l.idle == nil {
+		l.idle = make(chan struct{})
+	}
+
+	return l.idle
+}
+
+func (l *lifecycle) cancelAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, cancel := range l.cancels {
+		cancel(ErrRequestCanceled)
+	}
+}
+
+func canceledByClient(ctx context.Context, err error) error {
+	if err == nil || !errors.Is(context.Cause(ctx), ErrRequestCanceled) || errors.Is(err, ErrRequestCanceled) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", ErrRequestCanceled, err)
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDrain_WaitsForInFlightAndRejectsNew(t *testing.T) {
+	started := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("done"))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	result := make(chan error, 1)
+	go func() {
+		body, _, err := c.SendGet("/slow", nil, nil)
+		if err == nil && string(body) != "done" {
+			err = errors.New("unexpected body " + string(body))
+		}
+		result <- err
+	}()
+
+	<-started
+
+	if err := c.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain error: %v", err)
+	}
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Fatalf("in-flight request failed: %v", err)
+		}
+	default:
+		t.Fatal("Drain returned before the in-flight request finished")
+	}
+
+	if _, _, err := c.SendGet("/x", nil, nil); !errors.Is(err, ErrClientClosed) {
+		t.Fatalf("expected ErrClientClosed, got %v", err)
+	}
+}
+
+func TestDrain_StopsWaitingAtDeadline(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	c := newTestClient(t, srv.URL)
+	go func() { _, _, _ = c.SendGet("/hang", nil, nil) }()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	if err := c.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestClose_ForceCancelsInFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	c := newTestClient(t, srv.URL)
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/download"})
+		result <- err
+	}()
+
+	<-started
+
+	if err := c.Close(true); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, ErrRequestCanceled) {
+			t.Fatalf("expected ErrRequestCanceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("in-flight request was not canceled")
+	}
+
+	if _, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); !errors.Is(err, ErrClientClosed) {
+		t.Fatalf("expected ErrClientClosed, got %v", err)
+	}
+}
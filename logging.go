@@ -0,0 +1,170 @@
+package client
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+const redactedValue = "[REDACTED]"
+
+var (
+	defaultRedactedParams = []string{"token", "access_token", "api_key", "apikey", "signature", "password", "secret"}
+
+	defaultRedactedHeaders = []string{
+		AuthorizationHeader,
+		"Proxy-Authorization",
+		"Cookie",
+		"Set-Cookie",
+		"X-API-Key",
+		defaultCSRFHeader,
+		"X-XSRF-Token",
+		signatureHeader,
+		webhookSignatureHeader,
+	}
+)
+
+// WithLogFields adds static fields, such as service or team, to every log
+// entry of the client.
+func WithLogFields(fields map[string]string) Option {
+	return func(client *Client) error {
+		logContext := client.logger.With()
+
+		for key, value := range fields {
+			logContext = logContext.Str(key, value)
+		}
+
+		logger := logContext.Logger()
+		client.logger = &logger
+
+		return nil
+	}
+}
+
+// WithLogFieldNames renames the request fields the client logs ("method",
+// "url", "query" and "status"), e.g. {"method": "http.method"}.
+func WithLogFieldNames(names map[string]string) Option {
+	return func(client *Client) error {
+		if client.logFieldNames == nil {
+			client.logFieldNames = map[string]string{}
+		}
+
+		for field, name := range names {
+			client.logFieldNames[field] = name
+		}
+
+		return nil
+	}
+}
+
+func (client *Client) logField(name string) string {
+	if mapped, ok := client.logFieldNames[name]; ok {
+		return mapped
+	}
+
+	return name
+}
+
+// WithRedactedQueryParams adds query parameter names (case-insensitive)
+// whose values are masked in logs, on top of token, access_token, api_key,
+// apikey, signature, password and secret. It shares its list with
+// WithRedactedHeaders, so a name masks both.
+func WithRedactedQueryParams(names ...string) Option {
+	return addRedactedNames(names)
+}
+
+// WithRedactedHeaders adds header names whose values are masked in
+// RecentRequests, on top of credential, cookie, CSRF and signature headers
+// and the header set by WithAPIKey.
+func WithRedactedHeaders(names ...string) Option {
+	return addRedactedNames(names)
+}
+
+func addRedactedNames(names []string) Option {
+	return func(client *Client) error {
+		if client.redactedNames == nil {
+			client.redactedNames = map[string]bool{}
+		}
+
+		for _, name := range names {
+			client.redactedNames[strings.ToLower(name)] = true
+		}
+
+		return nil
+	}
+}
+
+func (client *Client) isRedactedParam(name string) bool {
+	name = strings.ToLower(name)
+
+	for _, redacted := range defaultRedactedParams {
+		if name == redacted {
+			return true
+		}
+	}
+
+	return client.redactedNames[name]
+}
+
+func (client *Client) isRedactedHeader(name string) bool {
+	for _, redacted := range defaultRedactedHeaders {
+		if strings.EqualFold(name, redacted) {
+			return true
+		}
+	}
+
+	if client.auth != nil && strings.EqualFold(name, client.auth.header) {
+		return true
+	}
+
+	return client.isRedactedParam(name)
+}
+
+// logURL logs rawUrl without its query string as "url" and the query
+// parameters as a "query" object with secret values masked.
+func (client *Client) logURL(rawUrl string) func(event *zerolog.Event) {
+	return func(event *zerolog.Event) {
+		u, err := url.Parse(rawUrl)
+		if err != nil {
+			event.Str(client.logField("url"), rawUrl)
+			return
+		}
+
+		query := u.Query()
+		u.RawQuery = ""
+		u.ForceQuery = false
+
+		event.Str(client.logField("url"), u.Redacted())
+
+		if len(query) == 0 {
+			return
+		}
+
+		keys := make([]string, 0, len(query))
+		for key := range query {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		fields := zerolog.Dict()
+
+		for _, key := range keys {
+			vals := query[key]
+
+			if client.isRedactedParam(key) {
+				vals = []string{redactedValue}
+			}
+
+			if len(vals) == 1 {
+				fields.Str(key, vals[0])
+			} else {
+				fields.Strs(key, vals)
+			}
+		}
+
+		event.Dict(client.logField("query"), fields)
+	}
+}
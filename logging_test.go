@@ -0,0 +1,79 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestLogging_RedactsQueryParams(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	log := zerolog.New(&buf)
+	c, err := New(srv.URL, nil, &log, false, "ua", WithRedactedQueryParams("X-Session"))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	_, err = c.Send(context.Background(), RequestSpec{
+		Method: http.MethodGet,
+		Path:   "/items",
+		Params: MultiParams{"token": {"abc123"}, "x-session": {"s3cr3t"}, "page": {"2"}},
+	})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	if strings.Contains(buf.String(), "abc123") || strings.Contains(buf.String(), "s3cr3t") {
+		t.Fatalf("secret leaked into logs: %s", buf.String())
+	}
+
+	var entry struct {
+		URL   string            `json:"url"`
+		Query map[string]string `json:"query"`
+	}
+	if err = json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry); err != nil {
+		t.Fatalf("bad log line: %v", err)
+	}
+	if entry.URL != srv.URL+"/items" || entry.Query["page"] != "2" || entry.Query["token"] != "[REDACTED]" {
+		t.Fatalf("entry=%+v", entry)
+	}
+}
+
+func TestWithLogFieldsAndNames(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	log := zerolog.New(&buf)
+	c, err := New(srv.URL, nil, &log, false, "ua",
+		WithLogFields(map[string]string{"service": "billing"}),
+		WithLogFieldNames(map[string]string{"method": "http.method", "status": "http.status_code"}),
+	)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	var entry map[string]any
+	if err = json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry); err != nil {
+		t.Fatalf("bad log line: %v", err)
+	}
+	if entry["service"] != "billing" || entry["http.method"] != "GET" || entry["http.status_code"] != float64(200) {
+		t.Fatalf("entry=%v", entry)
+	}
+	if _, ok := entry["method"]; ok {
+		t.Fatalf("unmapped field name still logged: %v", entry)
+	}
+}
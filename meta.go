@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+type metaContextKey struct{}
+
+// Meta is a per-request key/value store. Code that sees the request
+// context (cache key functions, dial hooks, custom transports) can record
+// values with MetaFromContext; callers read them from Response.Meta and
+// metrics hooks from RequestMetrics.Meta. A nil *Meta is empty.
+type Meta struct {
+	mu     sync.RWMutex
+	values map[string]any
+}
+
+func MetaFromContext(ctx context.Context) *Meta {
+	meta, _ := ctx.Value(metaContextKey{}).(*Meta)
+
+	return meta
+}
+
+func (meta *Meta) Set(key string, value any) {
+	if meta == nil {
+		return
+	}
+
+	meta.mu.Lock()
+	defer meta.mu.Unlock()
+
+	if meta.values == nil {
+		meta.values = map[string]any{}
+	}
+
+	meta.values[key] = value
+}
+
+func (meta *Meta) Get(key string) (any, bool) {
+	if meta == nil {
+		return nil, false
+	}
+
+	meta.mu.RLock()
+	defer meta.mu.RUnlock()
+
+	value, ok := meta.values[key]
+
+	return value, ok
+}
+
+func (meta *Meta) String(key string) string {
+	value, _ := meta.Get(key)
+	text, _ := value.(string)
+
+	return text
+}
+
+func (meta *Meta) Values() map[string]any {
+	values := map[string]any{}
+
+	if meta == nil {
+		return values
+	}
+
+	meta.mu.RLock()
+	defer meta.mu.RUnlock()
+
+	for key, value := range meta.values {
+		values[key] = value
+	}
+
+	return values
+}
+
+// WithDefaultContextTags records static identity tags, such as service,
+// environment or region, in the Meta of every request so metrics hooks and
+// Response.Meta carry them, and adds them to every log entry. Values set on
+// the request's own Meta win.
+func WithDefaultContextTags(tags map[string]string) Option {
+	return func(client *Client) error {
+		if client.defaultTags == nil {
+			client.defaultTags = map[string]string{}
+		}
+
+		for key, value := range tags {
+			client.defaultTags[key] = value
+		}
+
+		return WithLogFields(tags)(client)
+	}
+}
+
+func (client *Client) tagMeta(meta *Meta) {
+	if len(client.defaultTags) == 0 {
+		return
+	}
+
+	meta.mu.Lock()
+	defer meta.mu.Unlock()
+
+	if meta.values == nil {
+		meta.values = map[string]any{}
+	}
+
+	for key, value := range client.defaultTags {
+		if _, ok := meta.values[key]; !ok {
+			meta.values[key] = value
+		}
+	}
+}
+
+func withMeta(ctx context.Context) (context.Context, *Meta) {
+	if meta := MetaFromContext(ctx); meta != nil {
+		return ctx, meta
+	}
+
+	meta := &Meta{}
+
+	return context.WithValue(ctx, metaContextKey{}, meta), meta
+}
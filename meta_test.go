@@ -0,0 +1,107 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestMeta_SharedWithHooksAndResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	var hookSaw string
+	keyFunc := func(ctx context.Context, spec *RequestSpec, header http.Header) string {
+		MetaFromContext(ctx).Set("principal", "tenant-a")
+		return DefaultCacheKey(ctx, spec, header)
+	}
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua",
+		WithCache(NewMemoryCache(), 0),
+		WithCacheKeyFunc(keyFunc),
+		WithMetricsHook(func(m RequestMetrics) { hookSaw = m.Meta.String("principal") }),
+	)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	resp, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if resp.Meta.String("principal") != "tenant-a" || hookSaw != "tenant-a" {
+		t.Fatalf("response meta=%v hook=%q", resp.Meta.Values(), hookSaw)
+	}
+
+	var empty *Meta
+	if _, ok := empty.Get("x"); ok || len(empty.Values()) != 0 {
+		t.Fatal("nil Meta should be empty")
+	}
+}
+
+func TestResponse_EndpointAndCacheHit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithCache(NewMemoryCache(), time.Minute))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	spec := RequestSpec{Method: http.MethodGet, Path: "/"}
+
+	first, err := c.Send(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if first.Endpoint != srv.URL || first.CacheHit || first.Duration <= 0 || first.Retries != 0 {
+		t.Fatalf("first: endpoint=%q hit=%v duration=%v", first.Endpoint, first.CacheHit, first.Duration)
+	}
+
+	second, err := c.Send(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if !second.CacheHit || second.Endpoint != "" {
+		t.Fatalf("second: endpoint=%q hit=%v", second.Endpoint, second.CacheHit)
+	}
+}
+
+func TestWithDefaultContextTags(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	var hookSaw map[string]any
+	var buf bytes.Buffer
+	log := zerolog.New(&buf)
+	c, err := New(srv.URL, nil, &log, false, "ua",
+		WithDefaultContextTags(map[string]string{"service": "billing", "region": "eu"}),
+		WithMetricsHook(func(m RequestMetrics) { hookSaw = m.Meta.Values() }),
+	)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	ctx, meta := withMeta(context.Background())
+	meta.Set("region", "us")
+
+	resp, err := c.Send(ctx, RequestSpec{Method: http.MethodGet, Path: "/"})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	if resp.Meta.String("service") != "billing" || resp.Meta.String("region") != "us" || hookSaw["service"] != "billing" {
+		t.Fatalf("meta=%v hook=%v", resp.Meta.Values(), hookSaw)
+	}
+	if !strings.Contains(buf.String(), `"service":"billing"`) {
+		t.Fatalf("log=%s", buf.String())
+	}
+}
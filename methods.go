@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	MethodPropFind  = "PROPFIND"
+	MethodPropPatch = "PROPPATCH"
+	MethodMkCol     = "MKCOL"
+	MethodCopy      = "COPY"
+	MethodMove      = "MOVE"
+	MethodLock      = "LOCK"
+	MethodUnlock    = "UNLOCK"
+	MethodReport    = "REPORT"
+
+	depthHeader       = "Depth"
+	destinationHeader = "Destination"
+	overwriteHeader   = "Overwrite"
+)
+
+var ErrMethodNotAllowed = errors.New("http method not allowed")
+
+var standardMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+	http.MethodTrace,
+	http.MethodConnect,
+}
+
+// WebDAVMethods lists the RFC 4918 and RFC 3253 (REPORT) extension verbs.
+var WebDAVMethods = []string{
+	MethodPropFind,
+	MethodPropPatch,
+	MethodMkCol,
+	MethodCopy,
+	MethodMove,
+	MethodLock,
+	MethodUnlock,
+	MethodReport,
+}
+
+// WithAllowedMethods registers extension methods accepted in addition to
+// the standard ones.
+func WithAllowedMethods(methods ...string) Option {
+	return func(client *Client) error {
+		if client.allowedMethods == nil {
+			client.allowedMethods = map[string]struct{}{}
+		}
+
+		for _, method := range methods {
+			client.allowedMethods[strings.ToUpper(method)] = struct{}{}
+		}
+
+		return nil
+	}
+}
+
+// WithWebDAV allows WebDAVMethods, which the PropFind, MkCol, Copy and Move
+// helpers need.
+func WithWebDAV() Option {
+	return WithAllowedMethods(WebDAVMethods...)
+}
+
+// WithPermissiveMethods turns off method validation so vendor-specific
+// verbs pass through as-is. net/http still rejects malformed method tokens.
+func WithPermissiveMethods() Option {
+	return func(client *Client) error {
+		client.permissiveMethods = true
+
+		return nil
+	}
+}
+
+func (client *Client) validateMethod(method string) error {
+	if method == "" || client.permissiveMethods {
+		return nil
+	}
+
+	for _, standard := range standardMethods {
+		if method == standard {
+			return nil
+		}
+	}
+
+	if _, ok := client.allowedMethods[method]; ok {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrMethodNotAllowed, method)
+}
+
+func (client *Client) PropFind(ctx context.Context, path, depth string, body io.Reader) (*Response, error) {
+	headers := MultiHeaders{}
+	headers.Set(depthHeader, depth)
+
+	if body != nil {
+		headers.Set(ContentTypeHeader, ContentTypeXml)
+	}
+
+	return client.Send(ctx, RequestSpec{Method: MethodPropFind, Path: path, Body: body}, withHeaders(headers))
+}
+
+func (client *Client) MkCol(ctx context.Context, path string) (*Response, error) {
+	return client.Send(ctx, RequestSpec{Method: MethodMkCol, Path: path})
+}
+
+func (client *Client) Copy(ctx context.Context, path, destination string, overwrite bool) (*Response, error) {
+	return client.Send(ctx, client.transferSpec(MethodCopy, path, destination, overwrite))
+}
+
+func (client *Client) Move(ctx context.Context, path, destination string, overwrite bool) (*Response, error) {
+	return client.Send(ctx, client.transferSpec(MethodMove, path, destination, overwrite))
+}
+
+// transferSpec resolves a relative destination against the endpoint that
+// serves the request, so it names the same server as the source.
+func (client *Client) transferSpec(method, path, destination string, overwrite bool) RequestSpec {
+	headers := MultiHeaders{}
+	headers.Set(overwriteHeader, "F")
+
+	if overwrite {
+		headers.Set(overwriteHeader, "T")
+	}
+
+	spec := RequestSpec{Method: method, Path: path}
+
+	if isAbsoluteUrl(destination) {
+		headers.Set(destinationHeader, destination)
+	} else {
+		spec.Options = []RequestOption{destinationPath(destination)}
+	}
+
+	spec.Options = append(spec.Options, withHeaders(headers))
+
+	return spec
+}
+
+func destinationPath(path string) RequestOption {
+	return func(options *requestOptions) {
+		options.destination = path
+	}
+}
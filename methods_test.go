@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestValidateMethod_RejectsUnknownVerbs(t *testing.T) {
+	c := newTestClient(t, "http://example.invalid")
+
+	_, err := c.Send(context.Background(), RequestSpec{Method: MethodPropFind, Path: "/"})
+	if !errors.Is(err, ErrMethodNotAllowed) {
+		t.Fatalf("expected ErrMethodNotAllowed, got %v", err)
+	}
+}
+
+func TestWebDAVHelpers(t *testing.T) {
+	type seen struct{ method, depth, destination, overwrite string }
+	var got []seen
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, seen{r.Method, r.Header.Get("Depth"), r.Header.Get("Destination"), r.Header.Get("Overwrite")})
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithWebDAV())
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err = c.PropFind(ctx, "/dir/", "1", nil); err != nil {
+		t.Fatalf("PropFind error: %v", err)
+	}
+	if _, err = c.MkCol(ctx, "/new/"); err != nil {
+		t.Fatalf("MkCol error: %v", err)
+	}
+	if _, err = c.Move(ctx, "/a", "/b", true); err != nil {
+		t.Fatalf("Move error: %v", err)
+	}
+
+	want := []seen{
+		{MethodPropFind, "1", "", ""},
+		{MethodMkCol, "", "", ""},
+		{MethodMove, "", srv.URL + "/b", "T"},
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("request %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWebDAVCopy_DestinationFollowsServingEndpoint(t *testing.T) {
+	var mismatched int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Destination") != "http://"+r.Host+"/b" {
+			atomic.AddInt32(&mismatched, 1)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}
+	a := httptest.NewServer(http.HandlerFunc(handler))
+	defer a.Close()
+	b := httptest.NewServer(http.HandlerFunc(handler))
+	defer b.Close()
+
+	log := zerolog.Nop()
+	c, err := New(a.URL, nil, &log, false, "ua", WithWebDAV(), WithEndpoints(b.URL))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		if _, err = c.Copy(context.Background(), "/a", "/b", false); err != nil {
+			t.Fatalf("Copy error: %v", err)
+		}
+	}
+	if n := atomic.LoadInt32(&mismatched); n != 0 {
+		t.Fatalf("%d requests named another endpoint as destination", n)
+	}
+}
+
+func TestWithPermissiveMethods_PassesVendorVerbs(t *testing.T) {
+	var gotMethod string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithPermissiveMethods())
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	if _, err = c.Send(context.Background(), RequestSpec{Method: "XPURGE", Path: "/"}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if gotMethod != "XPURGE" {
+		t.Fatalf("method=%s", gotMethod)
+	}
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Middleware wraps the transport of every request, after headers, encoding
+// and body limits are applied, so it sees the request as it goes out.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn RoundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return fn(request)
+}
+
+// Matcher selects requests by method and path. Middleware is matched
+// against the path as sent, including the path of the base URL; request and
+// response validators against RequestSpec.Path.
+type Matcher func(method, path string) bool
+
+// MatchMethods matches any of methods.
+func MatchMethods(methods ...string) Matcher {
+	return func(method, _ string) bool {
+		for _, candidate := range methods {
+			if strings.EqualFold(candidate, method) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// MatchPathPrefix matches prefix and the paths below it: "/admin" matches
+// "/admin" and "/admin/users" but not "/administrators".
+func MatchPathPrefix(prefix string) Matcher {
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	return func(_, path string) bool {
+		if i := strings.IndexAny(path, "?#"); i >= 0 {
+			path = path[:i]
+		}
+
+		return path == prefix || strings.HasPrefix(path, prefix+"/")
+	}
+}
+
+// MatchAll matches when every matcher does.
+func MatchAll(matchers ...Matcher) Matcher {
+	return func(method, path string) bool {
+		for _, matcher := range matchers {
+			if !matcher(method, path) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// MatchAny matches when at least one matcher does.
+func MatchAny(matchers ...Matcher) Matcher {
+	return func(method, path string) bool {
+		for _, matcher := range matchers {
+			if matcher(method, path) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// WithMiddleware adds middleware; the first one added is the outermost.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(client *Client) error {
+		client.middleware = append(client.middleware, middleware...)
+
+		return nil
+	}
+}
+
+// When applies middleware only to requests matched by matcher.
+func When(matcher Matcher, middleware Middleware) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		wrapped := middleware(next)
+
+		return RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+			if matcher(request.Method, request.URL.Path) {
+				return wrapped.RoundTrip(request)
+			}
+
+			return next.RoundTrip(request)
+		})
+	}
+}
+
+// RequestValidatorWhen runs validator only for requests matched by matcher.
+func RequestValidatorWhen(matcher Matcher, validator func(*RequestSpec) error) func(*RequestSpec) error {
+	return func(spec *RequestSpec) error {
+		if !matcher(spec.Method, spec.Path) {
+			return nil
+		}
+
+		return validator(spec)
+	}
+}
+
+// ResponseValidatorWhen runs validator only for responses to requests
+// matched by matcher.
+func ResponseValidatorWhen(matcher Matcher, validator func(*Response) error) func(*Response) error {
+	return func(response *Response) error {
+		if response.Request == nil || !matcher(response.Request.Method, response.Request.Path) {
+			return nil
+		}
+
+		return validator(response)
+	}
+}
+
+type middlewareTransport struct {
+	http.RoundTripper
+	base http.RoundTripper
+}
+
+func (transport *middlewareTransport) CloseIdleConnections() {
+	if closer, ok := transport.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+func (client *Client) applyMiddleware() {
+	if len(client.middleware) == 0 {
+		return
+	}
+
+	base := client.httpClient.Transport
+	if base == nil {
+		base = client.mutableTransport()
+	}
+
+	next := base
+
+	for i := len(client.middleware) - 1; i >= 0; i-- {
+		next = client.middleware[i](next)
+	}
+
+	client.httpClient.Transport = &middlewareTransport{RoundTripper: next, base: base}
+}
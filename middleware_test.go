@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWhen_AppliesMiddlewareToMatchedRequests(t *testing.T) {
+	signed := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signed[r.Method+" "+r.URL.Path] = r.Header.Get("X-Signature")
+	}))
+	defer srv.Close()
+
+	sign := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+			request = request.Clone(request.Context())
+			request.Header.Set("X-Signature", "sig")
+			return next.RoundTrip(request)
+		})
+	}
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL+"/v1", nil, &log, false, "ua",
+		WithMiddleware(When(MatchAll(MatchMethods(http.MethodPost), MatchPathPrefix("/v1/admin")), sign)))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	for _, spec := range []RequestSpec{
+		{Method: http.MethodPost, Path: "/admin/users"},
+		{Method: http.MethodGet, Path: "/admin/users"},
+		{Method: http.MethodPost, Path: "/administrators"},
+	} {
+		if _, err = c.Send(context.Background(), spec); err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+	}
+
+	want := map[string]string{
+		"POST /v1/admin/users":    "sig",
+		"GET /v1/admin/users":     "",
+		"POST /v1/administrators": "",
+	}
+	for key, sig := range want {
+		if signed[key] != sig {
+			t.Fatalf("%s signature=%q, all=%v", key, signed[key], signed)
+		}
+	}
+}
+
+func TestRequestValidatorWhen(t *testing.T) {
+	errReadOnly := errors.New("read only")
+	validator := RequestValidatorWhen(MatchMethods(http.MethodDelete), func(*RequestSpec) error { return errReadOnly })
+
+	if err := validator(&RequestSpec{Method: http.MethodGet, Path: "/x"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := validator(&RequestSpec{Method: http.MethodDelete, Path: "/x"}); !errors.Is(err, errReadOnly) {
+		t.Fatalf("expected errReadOnly, got %v", err)
+	}
+}
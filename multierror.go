@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ItemError is the failure of one item of a batch, at Index in the input.
+type ItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError collects the per-item failures of a batch, ordered by index.
+// errors.Is and errors.As look through every item.
+type MultiError struct {
+	Total  int
+	Errors []*ItemError
+}
+
+func (e *MultiError) Error() string {
+	parts := make([]string, 0, len(e.Errors))
+	for _, item := range e.Errors {
+		parts = append(parts, item.Error())
+	}
+
+	return fmt.Sprintf("%d of %d failed: %s", len(e.Errors), e.Total, strings.Join(parts, "; "))
+}
+
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, item := range e.Errors {
+		errs = append(errs, item)
+	}
+
+	return errs
+}
+
+// Err returns the error of item index, or nil if it succeeded.
+func (e *MultiError) Err(index int) error {
+	for _, item := range e.Errors {
+		if item.Index == index {
+			return item.Err
+		}
+	}
+
+	return nil
+}
+
+type multiErrorCollector struct {
+	mu    sync.Mutex
+	total int
+	items []*ItemError
+}
+
+func (collector *multiErrorCollector) add(index int, err error) {
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	collector.items = append(collector.items, &ItemError{Index: index, Err: err})
+}
+
+func (collector *multiErrorCollector) err() error {
+	if len(collector.items) == 0 {
+		return nil
+	}
+
+	sort.Slice(collector.items, func(i, j int) bool { return collector.items[i].Index < collector.items[j].Index })
+
+	return &MultiError{Total: collector.total, Errors: collector.items}
+}
+
+// BatchGet fetches paths with at most concurrency requests in flight.
+// Responses are returned in input order, nil where the request failed, with
+// a *MultiError describing every failure.
+func (client *Client) BatchGet(ctx context.Context, paths []string, concurrency int) ([]*Response, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	responses := make([]*Response, len(paths))
+	collector := &multiErrorCollector{total: len(paths)}
+	slots := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for i, path := range paths {
+		wg.Add(1)
+		slots <- struct{}{}
+
+		go func(index int, path string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			response, err := client.Send(ctx, RequestSpec{Method: http.MethodGet, Path: path})
+			if err != nil {
+				collector.add(index, err)
+				return
+			}
+
+			responses[index] = response
+		}(i, path)
+	}
+
+	wg.Wait()
+
+	return responses, collector.err()
+}
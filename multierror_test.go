@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBatchGet_ReturnsMultiError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/missing") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	responses, err := c.BatchGet(context.Background(), []string{"/a", "/missing/1", "/b", "/missing/2"}, 2)
+
+	var multi *MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("expected *MultiError, got %v", err)
+	}
+	if multi.Total != 4 || len(multi.Errors) != 2 || multi.Errors[0].Index != 1 || multi.Errors[1].Index != 3 {
+		t.Fatalf("multi=%v", multi)
+	}
+	if !errors.Is(err, ErrRequestFailed) || multi.Err(0) != nil || multi.Err(3) == nil {
+		t.Fatalf("unwrap failed: %v", err)
+	}
+	if string(responses[0].Body) != "/a" || responses[1] != nil || string(responses[2].Body) != "/b" {
+		t.Fatalf("responses out of order")
+	}
+}
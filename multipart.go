@@ -0,0 +1,352 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultPartSize        = 8 << 20
+	defaultPartConcurrency = 4
+	defaultPartAttempts    = 3
+	partRetryDelay         = 200 * time.Millisecond
+	abortTimeout           = 30 * time.Second
+)
+
+var ErrMultipartUpload = errors.New("multipart upload failed")
+
+// partRetryStatusCodes add 500 to the defaults, since S3 answers transient
+// failures with InternalError.
+var partRetryStatusCodes = append([]int{http.StatusInternalServerError}, defaultRetryStatusCodes...)
+
+// MultipartConfig tunes UploadMultipart. Headers are sent with the initiate
+// request (Content-Type, x-amz-* metadata). Progress, if set, is called
+// with the total number of bytes uploaded after every finished part.
+// Parts are retried like WithRetry, using the client's RetryPolicy with
+// PartAttempts as its MaxAttempts.
+type MultipartConfig struct {
+	PartSize     int64
+	Concurrency  int
+	PartAttempts int
+	Headers      MultiHeaders
+	Progress     func(uploaded int64)
+}
+
+type MultipartResult struct {
+	UploadID string
+	Location string
+	ETag     string
+	Parts    int
+	Size     int64
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+// UploadMultipart uploads body to the S3-compatible object at path using
+// the multipart API: it initiates the upload, sends parts in parallel
+// (retrying each part up to PartAttempts times), then completes it, or
+// aborts it when any part fails. Authentication is whatever the client
+// already adds to its requests, such as headers or a presigning proxy.
+func (client *Client) UploadMultipart(
+	ctx context.Context,
+	path string,
+	body io.Reader,
+	config MultipartConfig,
+) (*MultipartResult, error) {
+	config = config.withDefaults()
+
+	uploadID, err := client.initiateMultipart(ctx, path, config.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	parts, size, err := client.uploadParts(ctx, path, uploadID, body, config)
+	if err != nil {
+		client.abortMultipart(ctx, path, uploadID)
+
+		return nil, err
+	}
+
+	result, err := client.completeMultipart(ctx, path, uploadID, parts)
+	if err != nil {
+		client.abortMultipart(ctx, path, uploadID)
+
+		return nil, err
+	}
+
+	result.Parts = len(parts)
+	result.Size = size
+
+	return result, nil
+}
+
+func (config MultipartConfig) withDefaults() MultipartConfig {
+	if config.PartSize <= 0 {
+		config.PartSize = defaultPartSize
+	}
+
+	if config.Concurrency < 1 {
+		config.Concurrency = defaultPartConcurrency
+	}
+
+	if config.PartAttempts < 1 {
+		config.PartAttempts = defaultPartAttempts
+	}
+
+	return config
+}
+
+func (client *Client) initiateMultipart(ctx context.Context, path string, headers MultiHeaders) (string, error) {
+	response, err := client.Send(ctx, RequestSpec{
+		Method:  http.MethodPost,
+		Path:    path,
+		Params:  MultiParams{"uploads": {""}},
+		Headers: headers,
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: initiate: %w", ErrMultipartUpload, err)
+	}
+
+	var initiated struct {
+		UploadID string `xml:"UploadId"`
+	}
+
+	if err = xml.Unmarshal(response.Body, &initiated); err != nil || initiated.UploadID == "" {
+		return "", fmt.Errorf("%w: initiate: no upload id in response", ErrMultipartUpload)
+	}
+
+	return initiated.UploadID, nil
+}
+
+func (client *Client) uploadParts(
+	ctx context.Context,
+	path string,
+	uploadID string,
+	body io.Reader,
+	config MultipartConfig,
+) ([]completedPart, int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		parts    []completedPart
+		firstErr error
+		uploaded atomic.Int64
+		size     int64
+	)
+
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	slots := make(chan struct{}, config.Concurrency)
+
+	for number := 1; ctx.Err() == nil; number++ {
+		chunk := make([]byte, config.PartSize)
+
+		n, readErr := io.ReadFull(body, chunk)
+		if n == 0 && number > 1 {
+			break
+		}
+
+		if readErr != nil && readErr != io.EOF && readErr != io.ErrUnexpectedEOF {
+			fail(fmt.Errorf("%w: read part %d: %w", ErrMultipartUpload, number, readErr))
+			break
+		}
+
+		size += int64(n)
+		slots <- struct{}{}
+		wg.Add(1)
+
+		go func(number int, chunk []byte) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			etag, err := client.uploadPart(ctx, path, uploadID, number, chunk, config.PartAttempts)
+			if err != nil {
+				fail(err)
+				return
+			}
+
+			mu.Lock()
+			parts = append(parts, completedPart{PartNumber: number, ETag: etag})
+			mu.Unlock()
+
+			if config.Progress != nil {
+				config.Progress(uploaded.Add(int64(len(chunk))))
+			}
+		}(number, chunk[:n])
+
+		if readErr != nil {
+			break
+		}
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, size, firstErr
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, size, err
+	}
+
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+
+	return parts, size, nil
+}
+
+func (client *Client) uploadPart(
+	ctx context.Context,
+	path string,
+	uploadID string,
+	number int,
+	chunk []byte,
+	attempts int,
+) (string, error) {
+	policy := client.partRetryPolicy(attempts)
+	backoff := policy.Backoff
+
+	for attempt := 1; ; attempt++ {
+		// The part is retried here, so the client's own retries are off.
+		response, err := client.Send(ctx, RequestSpec{
+			Method: http.MethodPut,
+			Path:   path,
+			Params: MultiParams{"partNumber": {strconv.Itoa(number)}, "uploadId": {uploadID}},
+			Body:   bytes.NewReader(chunk),
+		}, NoRetry())
+		if err == nil {
+			return response.Header.Get(etagHeader), nil
+		}
+
+		if attempt >= policy.MaxAttempts || ctx.Err() != nil || !policy.retryable(response, err, &requestOptions{}) {
+			return "", fmt.Errorf("%w: part %d: %w", ErrMultipartUpload, number, err)
+		}
+
+		delay := retryDelay(response, backoff)
+
+		client.logger.Warn().
+			Err(err).
+			Func(client.logURL(client.baseUrl+path)).
+			Int("part", number).
+			Int("attempt", attempt).
+			Dur("backoff", delay).
+			Msg("retrying multipart upload part")
+
+		if err = sleepContext(ctx, delay); err != nil {
+			return "", err
+		}
+
+		if backoff *= 2; backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+// partRetryPolicy is the client's RetryPolicy, or one that backs off from
+// partRetryDelay and retries partRetryStatusCodes, allowing attempts
+// attempts.
+func (client *Client) partRetryPolicy(attempts int) *RetryPolicy {
+	policy := RetryPolicy{Backoff: partRetryDelay, StatusCodes: partRetryStatusCodes}.withDefaults()
+	if client.retry != nil {
+		copied := *client.retry
+		policy = &copied
+	}
+
+	policy.MaxAttempts = attempts
+
+	return policy
+}
+
+func (client *Client) completeMultipart(
+	ctx context.Context,
+	path string,
+	uploadID string,
+	parts []completedPart,
+) (*MultipartResult, error) {
+	payload, err := xml.Marshal(completeMultipartUpload{Parts: parts})
+	if err != nil {
+		return nil, err
+	}
+
+	headers := MultiHeaders{}
+	headers.Set(ContentTypeHeader, ContentTypeXml)
+
+	response, err := client.Send(ctx, RequestSpec{
+		Method: http.MethodPost,
+		Path:   path,
+		Params: MultiParams{"uploadId": {uploadID}},
+		Body:   bytes.NewReader(payload),
+	}, withHeaders(headers))
+	if err != nil {
+		return nil, fmt.Errorf("%w: complete: %w", ErrMultipartUpload, err)
+	}
+
+	// S3 can report a failed completion inside a 200 response.
+	var completed struct {
+		XMLName  xml.Name
+		Location string `xml:"Location"`
+		ETag     string `xml:"ETag"`
+		Code     string `xml:"Code"`
+		Message  string `xml:"Message"`
+	}
+
+	if err = xml.Unmarshal(response.Body, &completed); err != nil {
+		return nil, fmt.Errorf("%w: complete: %w", ErrMultipartUpload, err)
+	}
+
+	if completed.XMLName.Local == "Error" {
+		return nil, fmt.Errorf("%w: complete: %s: %s", ErrMultipartUpload, completed.Code, completed.Message)
+	}
+
+	return &MultipartResult{UploadID: uploadID, Location: completed.Location, ETag: completed.ETag}, nil
+}
+
+// abortMultipart runs even when ctx was canceled, so the parts uploaded so
+// far do not linger, but it is bounded by abortTimeout.
+func (client *Client) abortMultipart(ctx context.Context, path, uploadID string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), abortTimeout)
+	defer cancel()
+
+	_, err := client.Send(ctx, RequestSpec{
+		Method: http.MethodDelete,
+		Path:   path,
+		Params: MultiParams{"uploadId": {uploadID}},
+	})
+	if err != nil {
+		client.logger.Error().
+			Err(err).
+			Func(client.logURL(client.baseUrl+path)).
+			Str("upload_id", uploadID).
+			Msg("failed to abort multipart upload")
+	}
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type fakeS3 struct {
+	mu        sync.Mutex
+	parts     map[string][]byte
+	failures  map[string]int
+	completed []completedPart
+	aborted   bool
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		_, _ = w.Write([]byte(`<InitiateMultipartUploadResult><UploadId>up-1</UploadId></InitiateMultipartUploadResult>`))
+	case r.Method == http.MethodPut:
+		number := q.Get("partNumber")
+		if s.failures[number] > 0 {
+			s.failures[number]--
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		s.parts[number] = body
+		w.Header().Set("ETag", `"etag-`+number+`"`)
+	case r.Method == http.MethodPost && q.Get("uploadId") == "up-1":
+		var complete completeMultipartUpload
+		_ = xml.NewDecoder(r.Body).Decode(&complete)
+		s.completed = complete.Parts
+		_, _ = w.Write([]byte(`<CompleteMultipartUploadResult><Location>/b/k</Location><ETag>"final"</ETag></CompleteMultipartUploadResult>`))
+	case r.Method == http.MethodDelete:
+		s.aborted = true
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestUploadMultipart(t *testing.T) {
+	s3 := &fakeS3{parts: map[string][]byte{}, failures: map[string]int{"2": 1}}
+	srv := httptest.NewServer(s3)
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	payload := strings.Repeat("abcdefghij", 25)
+
+	var progress []int64
+	var progressMu sync.Mutex
+	result, err := c.UploadMultipart(context.Background(), "/b/k", strings.NewReader(payload), MultipartConfig{
+		PartSize: 100,
+		Progress: func(n int64) {
+			progressMu.Lock()
+			progress = append(progress, n)
+			progressMu.Unlock()
+		},
+	})
+	if err != nil {
+		t.Fatalf("UploadMultipart error: %v", err)
+	}
+
+	if result.Parts != 3 || result.Size != int64(len(payload)) || result.ETag != `"final"` {
+		t.Fatalf("result=%+v", result)
+	}
+	joined := append(append(append([]byte{}, s3.parts["1"]...), s3.parts["2"]...), s3.parts["3"]...)
+	if !bytes.Equal(joined, []byte(payload)) {
+		t.Fatal("uploaded parts do not match payload")
+	}
+	for i, part := range s3.completed {
+		if part.PartNumber != i+1 {
+			t.Fatalf("complete parts out of order: %+v", s3.completed)
+		}
+	}
+	if len(progress) != 3 || progress[len(progress)-1] != int64(len(payload)) {
+		t.Fatalf("progress=%v", progress)
+	}
+}
+
+func TestUploadMultipart_AbortsOnPartFailure(t *testing.T) {
+	s3 := &fakeS3{parts: map[string][]byte{}, failures: map[string]int{"1": 10}}
+	srv := httptest.NewServer(s3)
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	_, err := c.UploadMultipart(context.Background(), "/b/k", strings.NewReader("data"), MultipartConfig{PartAttempts: 2})
+	if !errors.Is(err, ErrMultipartUpload) {
+		t.Fatalf("expected ErrMultipartUpload, got %v", err)
+	}
+	if !s3.aborted {
+		t.Fatal("upload was not aborted")
+	}
+}
+
+func TestUploadMultipart_DoesNotRetryClientErrors(t *testing.T) {
+	var puts atomic.Int32
+	var aborted atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			_, _ = w.Write([]byte(`<InitiateMultipartUploadResult><UploadId>up-1</UploadId></InitiateMultipartUploadResult>`))
+		case http.MethodPut:
+			puts.Add(1)
+			w.WriteHeader(http.StatusForbidden)
+		case http.MethodDelete:
+			aborted.Store(true)
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	_, err := c.UploadMultipart(context.Background(), "/b/k", strings.NewReader("data"), MultipartConfig{PartAttempts: 3})
+	if !errors.Is(err, ErrMultipartUpload) || puts.Load() != 1 {
+		t.Fatalf("err=%v puts=%d", err, puts.Load())
+	}
+	if !aborted.Load() {
+		t.Fatal("upload was not aborted")
+	}
+}
+
+func TestAbortMultipart_RunsAfterCancel(t *testing.T) {
+	var aborted atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		aborted.Store(r.Method == http.MethodDelete)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c.abortMultipart(ctx, "/b/k", "up-1")
+	if !aborted.Load() {
+		t.Fatal("abort was not sent after cancel")
+	}
+}
@@ -0,0 +1,177 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrNotMultiStatus = errors.New("response is not a multi-status body")
+
+// MultiStatusItem is one entry of a 207 Multi-Status or bulk-operation
+// response. Body holds the raw item (XML for WebDAV, JSON otherwise).
+type MultiStatusItem struct {
+	Href        string
+	StatusCode  int
+	Description string
+	PropStats   []PropStat
+	Body        []byte
+}
+
+type PropStat struct {
+	StatusCode int
+	Prop       []byte
+}
+
+func (item MultiStatusItem) OK() bool {
+	return item.StatusCode >= 200 && item.StatusCode < 300
+}
+
+type davMultiStatus struct {
+	Responses []davResponse `xml:"response"`
+}
+
+type davResponse struct {
+	Inner       []byte        `xml:",innerxml"`
+	Hrefs       []string      `xml:"href"`
+	Status      string        `xml:"status"`
+	Description string        `xml:"responsedescription"`
+	PropStats   []davPropStat `xml:"propstat"`
+}
+
+type davPropStat struct {
+	Prop   davInner `xml:"prop"`
+	Status string   `xml:"status"`
+}
+
+type davInner struct {
+	Inner []byte `xml:",innerxml"`
+}
+
+// MultiStatus parses a WebDAV multistatus XML document or a JSON bulk
+// response (an array, or an object holding a "responses", "items" or
+// "results" array, whose items carry a "status" and an "href" or "id").
+func (response *Response) MultiStatus() ([]MultiStatusItem, error) {
+	body := bytes.TrimSpace(response.Body)
+
+	if len(body) == 0 {
+		return nil, ErrNotMultiStatus
+	}
+
+	if body[0] == '<' {
+		return parseDAVMultiStatus(body)
+	}
+
+	return parseJSONMultiStatus(body)
+}
+
+func parseDAVMultiStatus(body []byte) ([]MultiStatusItem, error) {
+	var doc davMultiStatus
+
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotMultiStatus, err)
+	}
+
+	items := make([]MultiStatusItem, 0, len(doc.Responses))
+
+	for _, r := range doc.Responses {
+		item := MultiStatusItem{
+			StatusCode:  parseStatusLine(r.Status),
+			Description: strings.TrimSpace(r.Description),
+			Body:        r.Inner,
+		}
+
+		if len(r.Hrefs) > 0 {
+			item.Href = strings.TrimSpace(r.Hrefs[0])
+		}
+
+		for _, ps := range r.PropStats {
+			item.PropStats = append(item.PropStats, PropStat{
+				StatusCode: parseStatusLine(ps.Status),
+				Prop:       ps.Prop.Inner,
+			})
+		}
+
+		if item.StatusCode == 0 && len(item.PropStats) > 0 {
+			item.StatusCode = item.PropStats[0].StatusCode
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func parseJSONMultiStatus(body []byte) ([]MultiStatusItem, error) {
+	var raw []json.RawMessage
+
+	if err := json.Unmarshal(body, &raw); err != nil {
+		var wrapper map[string]json.RawMessage
+		if json.Unmarshal(body, &wrapper) != nil {
+			return nil, fmt.Errorf("%w: %w", ErrNotMultiStatus, err)
+		}
+
+		found := false
+		for _, field := range []string{"responses", "items", "results"} {
+			if list, ok := wrapper[field]; ok && json.Unmarshal(list, &raw) == nil {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, ErrNotMultiStatus
+		}
+	}
+
+	items := make([]MultiStatusItem, 0, len(raw))
+
+	for _, entry := range raw {
+		var fields struct {
+			Href        string `json:"href"`
+			ID          any    `json:"id"`
+			Status      any    `json:"status"`
+			Description string `json:"description"`
+		}
+
+		if err := json.Unmarshal(entry, &fields); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrNotMultiStatus, err)
+		}
+
+		item := MultiStatusItem{
+			Href:        fields.Href,
+			Description: fields.Description,
+			Body:        entry,
+		}
+
+		if item.Href == "" && fields.ID != nil {
+			item.Href = jsonScalarString(fields.ID)
+		}
+
+		switch status := fields.Status.(type) {
+		case float64:
+			item.StatusCode = int(status)
+		case string:
+			item.StatusCode = parseStatusLine(status)
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// parseStatusLine accepts "HTTP/1.1 404 Not Found" as well as a bare code.
+func parseStatusLine(line string) int {
+	for _, field := range strings.Fields(line) {
+		if code, err := strconv.Atoi(field); err == nil {
+			return code
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"testing"
+)
+
+func TestMultiStatus_DAV(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<D:multistatus xmlns:D="DAV:">
+  <D:response>
+    <D:href>/a.txt</D:href>
+    <D:propstat><D:prop><D:getetag>"1"</D:getetag></D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat>
+  </D:response>
+  <D:response>
+    <D:href>/b.txt</D:href>
+    <D:status>HTTP/1.1 423 Locked</D:status>
+    <D:responsedescription>locked</D:responsedescription>
+  </D:response>
+</D:multistatus>`
+
+	items, err := (&Response{StatusCode: 207, Body: []byte(body)}).MultiStatus()
+	if err != nil {
+		t.Fatalf("MultiStatus error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items", len(items))
+	}
+	if items[0].Href != "/a.txt" || !items[0].OK() || len(items[0].PropStats) != 1 {
+		t.Errorf("item 0: %+v", items[0])
+	}
+	if items[1].StatusCode != 423 || items[1].Description != "locked" || items[1].OK() {
+		t.Errorf("item 1: %+v", items[1])
+	}
+}
+
+func TestMultiStatus_JSON(t *testing.T) {
+	body := `{"results":[{"id":1,"status":201},{"id":2,"status":"409 Conflict","description":"dup"}]}`
+
+	items, err := (&Response{StatusCode: 207, Body: []byte(body)}).MultiStatus()
+	if err != nil {
+		t.Fatalf("MultiStatus error: %v", err)
+	}
+	if len(items) != 2 || items[0].Href != "1" || items[0].StatusCode != 201 || items[1].StatusCode != 409 {
+		t.Fatalf("items: %+v", items)
+	}
+
+	if _, err = (&Response{Body: []byte(`{"ok":true}`)}).MultiStatus(); err == nil {
+		t.Fatal("expected error for non multi-status body")
+	}
+}
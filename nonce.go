@@ -0,0 +1,56 @@
+package client
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	nonceBytes = 16
+
+	defaultNonceHeader     = "X-Nonce"
+	defaultTimestampHeader = "X-Timestamp"
+)
+
+// NonceHeaders names the replay protection headers; X-Nonce and
+// X-Timestamp by default. The timestamp is in Unix seconds.
+type NonceHeaders struct {
+	Nonce     string
+	Timestamp string
+}
+
+// WithRequestNonce attaches a random nonce and the current time to every
+// attempt. With WithMessageSignatures they also become the nonce and
+// created parameters of the signature.
+func WithRequestNonce(headers NonceHeaders) Option {
+	return func(client *Client) error {
+		headers.Nonce = fieldOrDefault(headers.Nonce, defaultNonceHeader)
+		headers.Timestamp = fieldOrDefault(headers.Timestamp, defaultTimestampHeader)
+		client.nonce = &headers
+
+		return nil
+	}
+}
+
+func (client *Client) attachNonce(request *http.Request) (string, time.Time, error) {
+	now := time.Now()
+
+	if client.nonce == nil {
+		return "", now, nil
+	}
+
+	raw := make([]byte, nonceBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", now, err
+	}
+
+	nonce := base64.RawURLEncoding.EncodeToString(raw)
+
+	request.Header.Set(client.nonce.Nonce, nonce)
+	request.Header.Set(client.nonce.Timestamp, strconv.FormatInt(now.Unix(), 10))
+
+	return nonce, now, nil
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithRequestNonce_UniquePerRequestAndSigned(t *testing.T) {
+	seen := map[string]bool{}
+	var timestamp, input string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen[r.Header.Get("X-Request-Nonce")] = true
+		timestamp = r.Header.Get(defaultTimestampHeader)
+		input = r.Header.Get("Signature-Input")
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua",
+		WithRequestNonce(NonceHeaders{Nonce: "X-Request-Nonce"}),
+		WithMessageSignatures(MessageSignatures{Signer: HMACSigner([]byte("k"))}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+	}
+
+	if len(seen) != 3 || seen[""] {
+		t.Fatalf("nonces=%v", seen)
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(unix, 0)) > time.Minute {
+		t.Fatalf("timestamp=%q", timestamp)
+	}
+	if !strings.Contains(input, ";created="+timestamp) || !strings.Contains(input, ";nonce=") {
+		t.Fatalf("signature input=%s", input)
+	}
+}
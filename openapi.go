@@ -0,0 +1,524 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const maxRefDepth = 32
+
+var errOpenAPIRefDepth = errors.New("openapi: $ref nesting too deep")
+
+// openAPIMethods are the path item keys that hold operations; the others,
+// such as parameters, servers or x- extensions, are not operations.
+var openAPIMethods = map[string]bool{
+	"get":     true,
+	"put":     true,
+	"post":    true,
+	"delete":  true,
+	"options": true,
+	"head":    true,
+	"patch":   true,
+	"trace":   true,
+}
+
+// OpenAPIValidator checks outgoing requests against the operations of an
+// OpenAPI 3 document in JSON form. Schemas are checked with JSONSchema, so
+// the same keyword subset applies.
+type OpenAPIValidator struct {
+	operations []openAPIOperation
+}
+
+type openAPIOperation struct {
+	method       string
+	segments     []string
+	parameters   []openAPIParameter
+	body         *JSONSchema
+	bodyRequired bool
+}
+
+type openAPIParameter struct {
+	Name     string      `json:"name"`
+	In       string      `json:"in"`
+	Required bool        `json:"required"`
+	Schema   *JSONSchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool `json:"required"`
+	Content  map[string]struct {
+		Schema *JSONSchema `json:"schema"`
+	} `json:"content"`
+}
+
+type openAPIPathItem struct {
+	Parameters []openAPIParameter `json:"parameters"`
+}
+
+type openAPIOperationItem struct {
+	Parameters  []openAPIParameter  `json:"parameters"`
+	RequestBody *openAPIRequestBody `json:"requestBody"`
+}
+
+func LoadOpenAPISpec(raw []byte) (*OpenAPIValidator, error) {
+	var document map[string]any
+
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return nil, err
+	}
+
+	resolved, err := resolveRefs(document["paths"], document, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, _ := resolved.(map[string]any)
+	refs := &schemaRefs{document: document, compiled: map[string]*JSONSchema{}}
+	validator := &OpenAPIValidator{}
+
+	for path, rawItem := range paths {
+		operations, err := parseOpenAPIPath(path, rawItem, refs)
+		if err != nil {
+			return nil, fmt.Errorf("openapi: path %s: %w", path, err)
+		}
+
+		validator.operations = append(validator.operations, operations...)
+	}
+
+	sort.Slice(validator.operations, func(i, j int) bool {
+		return validator.operations[i].before(&validator.operations[j])
+	})
+
+	return validator, nil
+}
+
+// before orders operations so that literal segments are matched ahead of
+// templated ones, as OpenAPI requires: /users/me wins over /users/{id}.
+func (operation *openAPIOperation) before(other *openAPIOperation) bool {
+	for i := 0; i < len(operation.segments) && i < len(other.segments); i++ {
+		templated, otherTemplated := isTemplateSegment(operation.segments[i]), isTemplateSegment(other.segments[i])
+		if templated != otherTemplated {
+			return !templated
+		}
+	}
+
+	path, otherPath := strings.Join(operation.segments, "/"), strings.Join(other.segments, "/")
+	if path != otherPath {
+		return path < otherPath
+	}
+
+	return operation.method < other.method
+}
+
+func isTemplateSegment(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func parseOpenAPIPath(path string, rawItem any, refs *schemaRefs) ([]openAPIOperation, error) {
+	var item openAPIPathItem
+
+	if err := remarshal(rawItem, &item); err != nil {
+		return nil, err
+	}
+
+	methods, _ := rawItem.(map[string]any)
+	operations := make([]openAPIOperation, 0, len(methods))
+
+	for method, rawOperation := range methods {
+		if !openAPIMethods[method] {
+			continue
+		}
+
+		var operationItem openAPIOperationItem
+
+		if err := remarshal(rawOperation, &operationItem); err != nil {
+			return nil, err
+		}
+
+		operation := openAPIOperation{
+			method:     strings.ToUpper(method),
+			segments:   splitPath(path),
+			parameters: mergeParameters(item.Parameters, operationItem.Parameters),
+		}
+
+		if body := operationItem.RequestBody; body != nil {
+			operation.bodyRequired = body.Required
+
+			operation.body = jsonBodySchema(body)
+		}
+
+		if err := operation.compile(refs); err != nil {
+			return nil, err
+		}
+
+		operations = append(operations, operation)
+	}
+
+	return operations, nil
+}
+
+// jsonBodySchema returns the schema of the JSON media type of body, such as
+// application/json; charset=utf-8 or application/*+json, preferring plain
+// application/json.
+func jsonBodySchema(body *openAPIRequestBody) *JSONSchema {
+	keys := make([]string, 0, len(body.Content))
+	for key := range body.Content {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var schema *JSONSchema
+
+	for _, key := range keys {
+		mediaType, _, err := mime.ParseMediaType(key)
+		if err != nil {
+			continue
+		}
+
+		if mediaType == ContentTypeJson {
+			return body.Content[key].Schema
+		}
+
+		if schema == nil && strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json") {
+			schema = body.Content[key].Schema
+		}
+	}
+
+	return schema
+}
+
+func (operation *openAPIOperation) compile(refs *schemaRefs) error {
+	for _, parameter := range operation.parameters {
+		if parameter.Schema == nil {
+			continue
+		}
+
+		if err := parameter.Schema.compile(refs); err != nil {
+			return err
+		}
+	}
+
+	if operation.body != nil {
+		return operation.body.compile(refs)
+	}
+
+	return nil
+}
+
+func (validator *OpenAPIValidator) Validate(spec *RequestSpec) error {
+	path := spec.Path
+	if u, err := url.Parse(spec.Path); err == nil {
+		path = u.Path
+	}
+
+	segments := splitPath(path)
+	pathMatched := false
+
+	for i := range validator.operations {
+		operation := &validator.operations[i]
+
+		pathParams, ok := operation.match(segments)
+		if !ok {
+			continue
+		}
+
+		pathMatched = true
+
+		if operation.method == spec.Method {
+			return operation.validate(spec, pathParams)
+		}
+	}
+
+	if pathMatched {
+		return fmt.Errorf("openapi: method %s is not defined for %s", spec.Method, path)
+	}
+
+	return fmt.Errorf("openapi: no operation matches %s", path)
+}
+
+func (operation *openAPIOperation) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(operation.segments) {
+		return nil, false
+	}
+
+	pathParams := map[string]string{}
+
+	for i, segment := range operation.segments {
+		if isTemplateSegment(segment) {
+			pathParams[strings.Trim(segment, "{}")] = segments[i]
+			continue
+		}
+
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+
+	return pathParams, true
+}
+
+func (operation *openAPIOperation) validate(spec *RequestSpec, pathParams map[string]string) error {
+	var violations []SchemaViolation
+
+	for _, parameter := range operation.parameters {
+		var value string
+		var found bool
+
+		switch parameter.In {
+		case "path":
+			value, found = pathParams[parameter.Name]
+		case "query":
+			value, found = queryParam(spec, parameter.Name)
+		case "header":
+			value = spec.Headers.Get(parameter.Name)
+			found = value != ""
+		default:
+			continue
+		}
+
+		location := parameter.In + "." + parameter.Name
+
+		if !found {
+			if parameter.Required {
+				violations = append(violations, SchemaViolation{Path: location, Message: "is required"})
+			}
+
+			continue
+		}
+
+		if parameter.Schema != nil {
+			parameter.Schema.validate(location, coerceParam(value, parameter.Schema), &violations)
+		}
+	}
+
+	violations = append(violations, operation.validateBody(spec)...)
+
+	if len(violations) > 0 {
+		return &SchemaError{Violations: violations}
+	}
+
+	return nil
+}
+
+func queryParam(spec *RequestSpec, name string) (string, bool) {
+	if spec.Params.Has(name) {
+		return spec.Params.Get(name), true
+	}
+
+	for _, param := range spec.OrderedParams {
+		if param.Key == name {
+			return param.Value, true
+		}
+	}
+
+	return "", false
+}
+
+func (operation *openAPIOperation) validateBody(spec *RequestSpec) []SchemaViolation {
+	var violations []SchemaViolation
+
+	body, err := readSpecBody(spec)
+	if err != nil {
+		return []SchemaViolation{{Path: "body", Message: err.Error()}}
+	}
+
+	if len(body) == 0 {
+		if operation.bodyRequired {
+			violations = append(violations, SchemaViolation{Path: "body", Message: "is required"})
+		}
+
+		return violations
+	}
+
+	if operation.body == nil {
+		return nil
+	}
+
+	var value any
+
+	if err = json.Unmarshal(body, &value); err != nil {
+		return []SchemaViolation{{Path: "body", Message: err.Error()}}
+	}
+
+	operation.body.validate("body", value, &violations)
+
+	return violations
+}
+
+func coerceParam(value string, schema *JSONSchema) any {
+	schema = schema.resolved()
+
+	for _, kind := range schema.Type {
+		switch kind {
+		case "integer", "number":
+			if number, err := strconv.ParseFloat(value, 64); err == nil {
+				return number
+			}
+		case "boolean":
+			if flag, err := strconv.ParseBool(value); err == nil {
+				return flag
+			}
+		}
+	}
+
+	return value
+}
+
+func mergeParameters(shared, own []openAPIParameter) []openAPIParameter {
+	merged := map[string]openAPIParameter{}
+
+	for _, parameter := range append(append([]openAPIParameter{}, shared...), own...) {
+		merged[parameter.In+"."+parameter.Name] = parameter
+	}
+
+	keys := make([]string, 0, len(merged))
+	for key := range merged {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	result := make([]openAPIParameter, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, merged[key])
+	}
+
+	return result
+}
+
+// schemaRefs compiles each schema a $ref points at once and hands out the
+// same *JSONSchema for every use, so recursive schemas point back at
+// themselves instead of being expanded.
+type schemaRefs struct {
+	document any
+	compiled map[string]*JSONSchema
+}
+
+func (refs *schemaRefs) resolve(ref string) (*JSONSchema, error) {
+	if schema, ok := refs.compiled[ref]; ok {
+		return schema, nil
+	}
+
+	target, err := lookupRef(refs.document, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &JSONSchema{}
+	if err = remarshal(target, schema); err != nil {
+		return nil, fmt.Errorf("openapi: $ref %q: %w", ref, err)
+	}
+
+	// Cache before compiling so that refs back to this schema resolve to it.
+	refs.compiled[ref] = schema
+
+	if err = schema.compile(refs); err != nil {
+		return nil, err
+	}
+
+	// A chain of schemas that only hold $ref never reaches any keywords.
+	depth := 0
+	for next := schema; next.target != nil; next = next.target {
+		if depth++; depth > maxRefDepth {
+			return nil, errOpenAPIRefDepth
+		}
+	}
+
+	return schema, nil
+}
+
+// resolveRefs inlines the $refs of path items, parameters and request
+// bodies. Schemas are left as they are and resolved by schemaRefs.
+func resolveRefs(node, document any, depth int) (any, error) {
+	if depth > maxRefDepth {
+		return nil, errOpenAPIRefDepth
+	}
+
+	switch typed := node.(type) {
+	case map[string]any:
+		if ref, ok := typed["$ref"].(string); ok {
+			target, err := lookupRef(document, ref)
+			if err != nil {
+				return nil, err
+			}
+
+			return resolveRefs(target, document, depth+1)
+		}
+
+		resolved := make(map[string]any, len(typed))
+
+		for key, val := range typed {
+			if key == "schema" {
+				resolved[key] = val
+				continue
+			}
+
+			value, err := resolveRefs(val, document, depth)
+			if err != nil {
+				return nil, err
+			}
+
+			resolved[key] = value
+		}
+
+		return resolved, nil
+	case []any:
+		resolved := make([]any, len(typed))
+
+		for i, val := range typed {
+			value, err := resolveRefs(val, document, depth)
+			if err != nil {
+				return nil, err
+			}
+
+			resolved[i] = value
+		}
+
+		return resolved, nil
+	default:
+		return node, nil
+	}
+}
+
+func lookupRef(document any, ref string) (any, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("openapi: unsupported $ref %q", ref)
+	}
+
+	node := document
+
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+
+		object, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("openapi: unresolved $ref %q", ref)
+		}
+
+		if node, ok = object[part]; !ok {
+			return nil, fmt.Errorf("openapi: unresolved $ref %q", ref)
+		}
+	}
+
+	return node, nil
+}
+
+func remarshal(from, to any) error {
+	encoded, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(encoded, to)
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
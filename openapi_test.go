@@ -0,0 +1,205 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+const testOpenAPISpec = `{
+	"openapi": "3.0.0",
+	"paths": {
+		"/users/me": {
+			"get": {}
+		},
+		"/users/{id}": {
+			"x-owner": "accounts",
+			"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+			"get": {
+				"parameters": [{"name": "expand", "in": "query", "schema": {"enum": ["roles"]}}]
+			},
+			"put": {
+				"parameters": [{"name": "X-Tenant", "in": "header", "required": true}],
+				"requestBody": {
+					"required": true,
+					"content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}
+				}
+			}
+		}
+	},
+	"components": {
+		"schemas": {
+			"User": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}
+		}
+	}
+}`
+
+func TestOpenAPIValidator_Validate(t *testing.T) {
+	validator, err := LoadOpenAPISpec([]byte(testOpenAPISpec))
+	if err != nil {
+		t.Fatalf("LoadOpenAPISpec error: %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		spec  RequestSpec
+		valid bool
+	}{
+		{"valid get", RequestSpec{Method: http.MethodGet, Path: "/users/7", Params: MultiParams{"expand": {"roles"}}}, true},
+		{"bad path param", RequestSpec{Method: http.MethodGet, Path: "/users/abc"}, false},
+		{"bad query param", RequestSpec{Method: http.MethodGet, Path: "/users/7", Params: MultiParams{"expand": {"x"}}}, false},
+		{"bad ordered query param", RequestSpec{
+			Method:        http.MethodGet,
+			Path:          "/users/7",
+			OrderedParams: OrderedParams{{Key: "expand", Value: "x"}},
+		}, false},
+		{"literal path wins", RequestSpec{Method: http.MethodGet, Path: "/users/me"}, true},
+		{"unknown method", RequestSpec{Method: http.MethodDelete, Path: "/users/7"}, false},
+		{"unknown path", RequestSpec{Method: http.MethodGet, Path: "/groups"}, false},
+		{"valid put", RequestSpec{
+			Method:  http.MethodPut,
+			Path:    "/users/7",
+			Headers: Headers{"x-tenant": "t1"}.Multi(),
+			Body:    strings.NewReader(`{"name":"bob"}`),
+		}, true},
+		{"missing header and bad body", RequestSpec{
+			Method: http.MethodPut,
+			Path:   "/users/7",
+			Body:   strings.NewReader(`{"name":1}`),
+		}, false},
+		{"missing body", RequestSpec{Method: http.MethodPut, Path: "/users/7", Headers: MultiHeaders{"X-Tenant": {"t"}}}, false},
+	}
+
+	for _, tc := range cases {
+		spec := tc.spec
+		err = validator.Validate(&spec)
+		if (err == nil) != tc.valid {
+			t.Errorf("%s: err=%v", tc.name, err)
+		}
+	}
+}
+
+func TestLoadOpenAPISpec_LiteralSegmentsFirst(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		validator, err := LoadOpenAPISpec([]byte(testOpenAPISpec))
+		if err != nil {
+			t.Fatalf("LoadOpenAPISpec error: %v", err)
+		}
+
+		if err = validator.Validate(&RequestSpec{Method: http.MethodGet, Path: "/users/me"}); err != nil {
+			t.Fatalf("/users/me matched a templated path: %v", err)
+		}
+	}
+}
+
+func TestLoadOpenAPISpec_RecursiveSchema(t *testing.T) {
+	validator, err := LoadOpenAPISpec([]byte(`{
+		"paths": {
+			"/trees": {
+				"post": {
+					"requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Node"}}}}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Node": {
+					"type": "object",
+					"required": ["name"],
+					"properties": {
+						"name": {"type": "string"},
+						"children": {"type": "array", "items": {"$ref": "#/components/schemas/Node"}}
+					}
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("LoadOpenAPISpec error: %v", err)
+	}
+
+	valid := `{"name":"root","children":[{"name":"a","children":[{"name":"b"}]}]}`
+	if err = validator.Validate(&RequestSpec{Method: http.MethodPost, Path: "/trees", Body: strings.NewReader(valid)}); err != nil {
+		t.Fatalf("valid tree rejected: %v", err)
+	}
+
+	invalid := `{"name":"root","children":[{"children":[{"name":1}]}]}`
+	err = validator.Validate(&RequestSpec{Method: http.MethodPost, Path: "/trees", Body: strings.NewReader(invalid)})
+
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) || len(schemaErr.Violations) != 2 {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestLoadOpenAPISpec_RejectsRefCycle(t *testing.T) {
+	_, err := LoadOpenAPISpec([]byte(`{
+		"paths": {"/a": {"post": {"requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/A"}}}}}}},
+		"components": {"schemas": {"A": {"$ref": "#/components/schemas/B"}, "B": {"$ref": "#/components/schemas/A"}}}
+	}`))
+	if !errors.Is(err, errOpenAPIRefDepth) {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestLoadOpenAPISpec_JSONMediaTypes(t *testing.T) {
+	for _, mediaType := range []string{"application/json; charset=utf-8", "application/*+json", "application/merge-patch+json"} {
+		validator, err := LoadOpenAPISpec([]byte(`{"paths": {"/users": {"patch": {"requestBody": {"content": {
+			"text/plain": {"schema": {"type": "string"}},
+			"` + mediaType + `": {"schema": {"type": "object", "required": ["name"]}}
+		}}}}}}`))
+		if err != nil {
+			t.Fatalf("LoadOpenAPISpec error: %v", err)
+		}
+
+		err = validator.Validate(&RequestSpec{Method: http.MethodPatch, Path: "/users", Body: strings.NewReader(`{}`)})
+		if err == nil {
+			t.Errorf("%s: body was not validated", mediaType)
+		}
+	}
+}
+
+func TestWithRequestValidator_BlocksInvalidRequests(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+	}))
+	defer srv.Close()
+
+	validator, err := LoadOpenAPISpec([]byte(testOpenAPISpec))
+	if err != nil {
+		t.Fatalf("LoadOpenAPISpec error: %v", err)
+	}
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithRequestValidator(validator.Validate))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	_, err = c.Send(context.Background(), RequestSpec{
+		Method:  http.MethodPut,
+		Path:    "/users/7",
+		Headers: MultiHeaders{"X-Tenant": {"t"}},
+		Body:    strings.NewReader(`{"name":"bob"}`),
+	})
+	if err != nil {
+		t.Fatalf("valid request rejected: %v", err)
+	}
+
+	_, _, err = c.SendPut("/users/7", []byte(`{}`), nil, Headers{"X-Tenant": "t"})
+
+	var validationErr *RequestValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected RequestValidationError, got %v", err)
+	}
+	if atomic.LoadInt32(&hits) != 1 {
+		t.Fatalf("invalid request reached the server, hits=%d", hits)
+	}
+}
@@ -0,0 +1,38 @@
+package client
+
+type Option func(client *Client) error
+
+func WithEnvelopeUnwrapping(fields EnvelopeFields) Option {
+	return func(client *Client) error {
+		client.envelope = &fields
+
+		return nil
+	}
+}
+
+func WithResponseValidator(validator func(*Response) error) Option {
+	return func(client *Client) error {
+		client.validators = append(client.validators, validator)
+
+		return nil
+	}
+}
+
+func WithRequestValidator(validator func(*RequestSpec) error) Option {
+	return func(client *Client) error {
+		client.requestValidators = append(client.requestValidators, validator)
+
+		return nil
+	}
+}
+
+// WithPreservedQueryOrder keeps query strings already present in a request
+// path (or an absolute URL such as a pagination link) verbatim instead of
+// re-encoding them in sorted key order.
+func WithPreservedQueryOrder() Option {
+	return func(client *Client) error {
+		client.preserveQueryOrder = true
+
+		return nil
+	}
+}
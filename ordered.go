@@ -0,0 +1,57 @@
+package client
+
+import (
+	"net/url"
+	"strings"
+)
+
+type QueryParam struct {
+	Key   string
+	Value string
+}
+
+// OrderedParams encodes query parameters in the order they were added, for
+// servers that sign or validate the original parameter order.
+type OrderedParams []QueryParam
+
+func (params *OrderedParams) Add(key, val string) *OrderedParams {
+	*params = append(*params, QueryParam{Key: key, Value: val})
+
+	return params
+}
+
+func (params OrderedParams) Get(key string) string {
+	for _, param := range params {
+		if param.Key == key {
+			return param.Value
+		}
+	}
+
+	return ""
+}
+
+func (params OrderedParams) Encode() string {
+	var builder strings.Builder
+
+	for i, param := range params {
+		if i > 0 {
+			builder.WriteByte('&')
+		}
+
+		builder.WriteString(url.QueryEscape(param.Key))
+		builder.WriteByte('=')
+		builder.WriteString(url.QueryEscape(param.Value))
+	}
+
+	return builder.String()
+}
+
+func (params OrderedParams) Multi() MultiParams {
+	multi := MultiParams{}
+
+	for _, param := range params {
+		multi.Add(param.Key, param.Value)
+	}
+
+	return multi
+}
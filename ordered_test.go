@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestOrderedParams_KeepInsertionOrder(t *testing.T) {
+	var gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+	}))
+	defer srv.Close()
+
+	ordered := OrderedParams{}
+	ordered.Add("z", "1").Add("a", "2 3").Add("m", "4")
+
+	c := newTestClient(t, srv.URL)
+	_, err := c.Send(context.Background(), RequestSpec{
+		Method:        http.MethodGet,
+		Path:          "/sign",
+		Params:        MultiParams{"b": {"0"}},
+		OrderedParams: ordered,
+	})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if gotQuery != "b=0&z=1&a=2+3&m=4" {
+		t.Fatalf("query=%s", gotQuery)
+	}
+}
+
+func TestWithPreservedQueryOrder_KeepsPathQuery(t *testing.T) {
+	var gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	sorted, err := New(srv.URL, nil, &log, false, "ua")
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	preserved, err := New(srv.URL, nil, &log, false, "ua", WithPreservedQueryOrder())
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	spec := RequestSpec{Method: http.MethodGet, Path: srv.URL + "/next?z=1&a=2", Params: MultiParams{"k": {"v"}}}
+
+	if _, err = sorted.Send(context.Background(), spec, followUrl()); err != nil || gotQuery != "a=2&k=v&z=1" {
+		t.Fatalf("sorted query=%s err=%v", gotQuery, err)
+	}
+	if _, err = preserved.Send(context.Background(), spec, followUrl()); err != nil || gotQuery != "z=1&a=2&k=v" {
+		t.Fatalf("preserved query=%s err=%v", gotQuery, err)
+	}
+}
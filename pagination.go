@@ -0,0 +1,273 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultMetaField = "_meta"
+	linkRelNext      = "next"
+)
+
+type PageStrategy interface {
+	NextRequest(prev *Response) (*RequestSpec, bool)
+}
+
+// OffsetLimitStrategy advances OffsetParam by Limit until a page holds fewer
+// than Limit items. ItemsPath is a dot-separated path to the items array in
+// the response body, empty when the body itself is the array.
+type OffsetLimitStrategy struct {
+	OffsetParam string
+	LimitParam  string
+	Limit       int
+	ItemsPath   string
+}
+
+func (s OffsetLimitStrategy) NextRequest(prev *Response) (*RequestSpec, bool) {
+	if prev == nil || prev.Request == nil || s.Limit < 1 {
+		return nil, false
+	}
+
+	items, ok := lookupJSON(prev.Body, s.ItemsPath)
+	if !ok {
+		return nil, false
+	}
+
+	list, ok := items.([]any)
+	if !ok || len(list) < s.Limit {
+		return nil, false
+	}
+
+	offset, _ := strconv.Atoi(prev.Request.Params.Get(s.OffsetParam))
+
+	next := prev.Request.clone()
+	next.Params.Set(s.OffsetParam, strconv.Itoa(offset+s.Limit))
+	next.Params.Set(s.LimitParam, strconv.Itoa(s.Limit))
+
+	return next, true
+}
+
+// PagePerPageStrategy increments PageParam until the page metadata stored
+// under MetaField (default "_meta") reports the last page.
+type PagePerPageStrategy struct {
+	PageParam    string
+	PerPageParam string
+	PerPage      int
+	MetaField    string
+}
+
+func (s PagePerPageStrategy) NextRequest(prev *Response) (*RequestSpec, bool) {
+	if prev == nil || prev.Request == nil {
+		return nil, false
+	}
+
+	meta, ok := s.pageMeta(prev)
+	if !ok {
+		return nil, false
+	}
+
+	if meta.CurrentPage < 1 || meta.CurrentPage >= meta.PageCount {
+		return nil, false
+	}
+
+	next := prev.Request.clone()
+	next.Params.Set(s.PageParam, strconv.Itoa(meta.CurrentPage+1))
+
+	if s.PerPage > 0 {
+		next.Params.Set(s.PerPageParam, strconv.Itoa(s.PerPage))
+	}
+
+	return next, true
+}
+
+func (s PagePerPageStrategy) pageMeta(prev *Response) (MetaResponse, bool) {
+	var meta MetaResponse
+
+	if prev.Pagination != nil {
+		return *prev.Pagination, true
+	}
+
+	field := s.MetaField
+	if field == "" {
+		field = defaultMetaField
+	}
+
+	raw, ok := lookupJSON(prev.Body, field)
+	if !ok {
+		return meta, false
+	}
+
+	encoded, err := json.Marshal(raw)
+	if err != nil || json.Unmarshal(encoded, &meta) != nil {
+		return meta, false
+	}
+
+	return meta, true
+}
+
+// LinkHeaderStrategy follows the rel="next" URL of the Link response header,
+// resolved against the URL of the previous page as RFC 8288 requires. Links
+// to another origin end the pagination.
+type LinkHeaderStrategy struct{}
+
+func (LinkHeaderStrategy) NextRequest(prev *Response) (*RequestSpec, bool) {
+	if prev == nil || prev.Request == nil {
+		return nil, false
+	}
+
+	href, ok := parseLinkHeader(prev.Header, linkRelNext)
+	if !ok {
+		return nil, false
+	}
+
+	target, ok := resolveSameOrigin(prev.URL, href)
+	if !ok {
+		return nil, false
+	}
+
+	next := prev.Request.clone()
+	next.Path = target
+	next.Params = MultiParams{}
+	next.OrderedParams = nil
+	next.Options = append(next.Options, followUrl())
+
+	return next, true
+}
+
+// BodyCursorStrategy copies the cursor found at CursorPath in the response
+// body into CursorParam, stopping once the cursor is empty or missing.
+type BodyCursorStrategy struct {
+	CursorPath  string
+	CursorParam string
+}
+
+func (s BodyCursorStrategy) NextRequest(prev *Response) (*RequestSpec, bool) {
+	if prev == nil || prev.Request == nil {
+		return nil, false
+	}
+
+	raw, ok := lookupJSON(prev.Body, s.CursorPath)
+	if !ok || raw == nil {
+		return nil, false
+	}
+
+	cursor := jsonScalarString(raw)
+	if cursor == "" {
+		return nil, false
+	}
+
+	next := prev.Request.clone()
+	next.Params.Set(s.CursorParam, cursor)
+
+	return next, true
+}
+
+func (client *Client) Paginate(
+	ctx context.Context,
+	first RequestSpec,
+	strategy PageStrategy,
+	handle func(*Response) error,
+) error {
+	spec := &first
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		response, err := client.Send(ctx, *spec)
+		if err != nil {
+			return err
+		}
+
+		if err = handle(response); err != nil {
+			return err
+		}
+
+		next, ok := strategy.NextRequest(response)
+		if !ok {
+			return nil
+		}
+
+		spec = next
+	}
+}
+
+func (spec *RequestSpec) clone() *RequestSpec {
+	next := *spec
+	next.Params = spec.Params.Clone()
+	next.Headers = spec.Headers.Clone()
+	next.Options = append([]RequestOption(nil), spec.Options...)
+
+	if next.Headers == nil {
+		next.Headers = MultiHeaders{}
+	}
+
+	return &next
+}
+
+func lookupJSON(body []byte, path string) (any, bool) {
+	var value any
+
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, false
+	}
+
+	if path == "" {
+		return value, true
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		if value, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+func jsonScalarString(value any) string {
+	if number, ok := value.(float64); ok {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+
+	return fmt.Sprint(value)
+}
+
+func parseLinkHeader(header http.Header, rel string) (string, bool) {
+	for _, line := range header.Values("Link") {
+		for _, link := range strings.Split(line, ",") {
+			parts := strings.Split(link, ";")
+
+			href := strings.TrimSpace(parts[0])
+			if !strings.HasPrefix(href, "<") || !strings.HasSuffix(href, ">") {
+				continue
+			}
+
+			for _, attr := range parts[1:] {
+				name, value, found := strings.Cut(strings.TrimSpace(attr), "=")
+				if !found || strings.TrimSpace(name) != "rel" {
+					continue
+				}
+
+				for _, r := range strings.Fields(strings.Trim(value, `"`)) {
+					if r == rel {
+						return strings.Trim(href, "<>"), true
+					}
+				}
+			}
+		}
+	}
+
+	return "", false
+}
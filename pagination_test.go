@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func collectPages(t *testing.T, c *Client, first RequestSpec, strategy PageStrategy) []string {
+	t.Helper()
+
+	var pages []string
+
+	err := c.Paginate(context.Background(), first, strategy, func(r *Response) error {
+		pages = append(pages, r.Request.Path+"?"+fmt.Sprint(r.Request.Params))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Paginate error: %v", err)
+	}
+
+	return pages
+}
+
+func TestPaginate_OffsetLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		if offset < 4 {
+			io.WriteString(w, `{"items":[1,2]}`)
+			return
+		}
+		io.WriteString(w, `{"items":[5]}`)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	pages := collectPages(t, c, RequestSpec{Method: http.MethodGet, Path: "/items"}, OffsetLimitStrategy{
+		OffsetParam: "offset",
+		LimitParam:  "limit",
+		Limit:       2,
+		ItemsPath:   "items",
+	})
+	if len(pages) != 3 {
+		t.Fatalf("pages=%v", pages)
+	}
+}
+
+func TestPaginate_PagePerPage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			page = "1"
+		}
+		fmt.Fprintf(w, `{"items":[],"_meta":{"currentPage":%s,"pageCount":3}}`, page)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	pages := collectPages(t, c, RequestSpec{Method: http.MethodGet, Path: "/items"}, PagePerPageStrategy{
+		PageParam:    "page",
+		PerPageParam: "per-page",
+		PerPage:      10,
+	})
+	if len(pages) != 3 {
+		t.Fatalf("pages=%v", pages)
+	}
+}
+
+func TestPaginate_LinkHeader(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") != "2" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=2>; rel="next", <%s/items?page=2>; rel="last"`, srv.URL, srv.URL))
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	pages := collectPages(t, c, RequestSpec{Method: http.MethodGet, Path: "/items"}, LinkHeaderStrategy{})
+	if len(pages) != 2 || pages[1] != srv.URL+"/items?page=2?map[]" {
+		t.Fatalf("pages=%v", pages)
+	}
+}
+
+func TestPaginate_LinkHeaderResolvesRelativeAndRefusesOtherOrigins(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", `<items?page=2>; rel="next"`)
+		case "2":
+			w.Header().Set("Link", `<https://evil.example/v1/items?page=3>; rel="next"`)
+		default:
+			t.Errorf("followed %s", r.URL)
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	pages := collectPages(t, c, RequestSpec{Method: http.MethodGet, Path: "/v1/list"}, LinkHeaderStrategy{})
+	if len(pages) != 2 || pages[1] != srv.URL+"/v1/items?page=2?map[]" {
+		t.Fatalf("pages=%v", pages)
+	}
+}
+
+func TestPaginate_BodyCursor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			io.WriteString(w, `{"meta":{"next":"abc"}}`)
+		case "abc":
+			io.WriteString(w, `{"meta":{"next":null}}`)
+		default:
+			t.Errorf("unexpected cursor %q", r.URL.Query().Get("cursor"))
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	pages := collectPages(t, c, RequestSpec{Method: http.MethodGet, Path: "/items"}, BodyCursorStrategy{
+		CursorPath:  "meta.next",
+		CursorParam: "cursor",
+	})
+	if len(pages) != 2 {
+		t.Fatalf("pages=%v", pages)
+	}
+}
+
+func TestPaginate_StopsOnHandlerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"meta":{"next":"again"}}`)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	stop := fmt.Errorf("stop")
+	calls := 0
+	err := c.Paginate(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/items"},
+		BodyCursorStrategy{CursorPath: "meta.next", CursorParam: "cursor"},
+		func(*Response) error {
+			calls++
+			if calls == 2 {
+				return stop
+			}
+			return nil
+		})
+	if err != stop || calls != 2 {
+		t.Fatalf("err=%v calls=%d", err, calls)
+	}
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// WaitFor sends spec every interval, decodes each response body into T and
+// returns the first value that satisfies predicate. When ctx expires the last
+// decoded value is returned with the context error. Request and decode errors
+// stop polling. An interval of zero or less polls every second. Bodies should
+// be set with a RequestOption such as JSONBody so they are encoded again for
+// every attempt.
+func WaitFor[T any](
+	ctx context.Context,
+	client *Client,
+	spec RequestSpec,
+	predicate func(T) bool,
+	interval time.Duration,
+) (T, error) {
+	var last T
+
+	if interval <= 0 {
+		interval = defaultAsyncPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		response, err := client.Send(ctx, spec)
+		if err != nil {
+			return last, err
+		}
+
+		var value T
+		if err = client.DecodeJSON(response, &value); err != nil {
+			return last, err
+		}
+
+		last = value
+
+		if predicate(value) {
+			return value, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type jobStatus struct {
+	State string `json:"state"`
+}
+
+func TestWaitFor_PollsUntilPredicate(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		state := "running"
+		if atomic.AddInt32(&calls, 1) >= 3 {
+			state = "done"
+		}
+		fmt.Fprintf(w, `{"state":%q}`, state)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	status, err := WaitFor(context.Background(), c, RequestSpec{Method: http.MethodGet, Path: "/jobs/1"},
+		func(status jobStatus) bool { return status.State == "done" }, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitFor error: %v", err)
+	}
+	if status.State != "done" || atomic.LoadInt32(&calls) != 3 {
+		t.Fatalf("status=%+v calls=%d", status, calls)
+	}
+}
+
+func TestWaitFor_ContextExpires(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"state":"running"}`)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	status, err := WaitFor(ctx, c, RequestSpec{Method: http.MethodGet, Path: "/jobs/1"},
+		func(status jobStatus) bool { return status.State == "done" }, 10*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if status.State != "running" {
+		t.Fatalf("last status=%+v", status)
+	}
+}
+
+func TestWaitFor_DefaultsNonPositiveInterval(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"state":"done"}`)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	status, err := WaitFor(context.Background(), c, RequestSpec{Method: http.MethodGet, Path: "/jobs/1"},
+		func(status jobStatus) bool { return status.State == "done" }, 0)
+	if err != nil || status.State != "done" {
+		t.Fatalf("status=%+v err=%v", status, err)
+	}
+}
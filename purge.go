@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+const (
+	MethodPurge = "PURGE"
+	MethodBan   = "BAN"
+
+	hostHeader = "Host"
+)
+
+// InvalidationOptions configures Purge and Ban. Host overrides the Host
+// header so a proxy can be addressed directly while invalidating a virtual
+// host; IgnoreNotFound treats 404 (object not cached) as success.
+type InvalidationOptions struct {
+	Host           string
+	Headers        MultiHeaders
+	IgnoreNotFound bool
+}
+
+// Purge sends PURGE for path. Like the WebDAV helpers it needs the verb to
+// be allowed with WithAllowedMethods(MethodPurge, MethodBan).
+func (client *Client) Purge(ctx context.Context, path string, opts InvalidationOptions) (*Response, error) {
+	return client.invalidate(ctx, MethodPurge, path, opts)
+}
+
+// Ban sends BAN for path; ban expressions are usually passed in
+// opts.Headers (for example X-Ban-Url).
+func (client *Client) Ban(ctx context.Context, path string, opts InvalidationOptions) (*Response, error) {
+	return client.invalidate(ctx, MethodBan, path, opts)
+}
+
+func (client *Client) invalidate(
+	ctx context.Context,
+	method string,
+	path string,
+	opts InvalidationOptions,
+) (*Response, error) {
+	headers := MultiHeaders{}
+
+	if opts.Host != "" {
+		headers.Set(hostHeader, opts.Host)
+	}
+
+	response, err := client.Send(ctx, RequestSpec{Method: method, Path: path, Headers: opts.Headers}, withHeaders(headers))
+
+	if opts.IgnoreNotFound && errors.Is(err, ErrRequestFailed) &&
+		response != nil && response.StatusCode == http.StatusNotFound {
+		return response, nil
+	}
+
+	return response, err
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestPurge_HostOverrideAndNotFound(t *testing.T) {
+	var gotMethod, gotHost, gotBan string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotHost, gotBan = r.Method, r.Host, r.Header.Get("X-Ban-Url")
+		if r.URL.Path == "/cold" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithAllowedMethods(MethodPurge, MethodBan))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err = c.Purge(ctx, "/page", InvalidationOptions{Host: "www.example.com"}); err != nil {
+		t.Fatalf("Purge error: %v", err)
+	}
+	if gotMethod != MethodPurge || gotHost != "www.example.com" {
+		t.Errorf("method=%s host=%s", gotMethod, gotHost)
+	}
+
+	if _, err = c.Purge(ctx, "/cold", InvalidationOptions{}); !errors.Is(err, ErrRequestFailed) {
+		t.Errorf("expected ErrRequestFailed for 404, got %v", err)
+	}
+	if _, err = c.Purge(ctx, "/cold", InvalidationOptions{IgnoreNotFound: true}); err != nil {
+		t.Errorf("expected 404 to be ignored, got %v", err)
+	}
+
+	if _, err = c.Ban(ctx, "/", InvalidationOptions{Headers: MultiHeaders{"X-Ban-Url": {"^/news"}}}); err != nil {
+		t.Fatalf("Ban error: %v", err)
+	}
+	if gotMethod != MethodBan || gotBan != "^/news" {
+		t.Errorf("method=%s ban=%s", gotMethod, gotBan)
+	}
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	querySort    = "sort"
+	queryFields  = "fields"
+	queryInclude = "include"
+	queryFilter  = "filter"
+	queryPage    = "page"
+)
+
+// Query builds JSON:API style query parameters such as filter[status]=open,
+// sort=-created, fields[users]=id,name and page[size]=20.
+type Query struct {
+	values  url.Values
+	sort    []string
+	include []string
+}
+
+func NewQuery() *Query {
+	return &Query{values: url.Values{}}
+}
+
+func (q *Query) Filter(field, value string) *Query {
+	q.values.Set(bracketKey(queryFilter, field), value)
+
+	return q
+}
+
+func (q *Query) FilterOp(field, operator, value string) *Query {
+	q.values.Set(bracketKey(queryFilter, field)+"["+operator+"]", value)
+
+	return q
+}
+
+func (q *Query) Sort(fields ...string) *Query {
+	q.sort = append(q.sort, fields...)
+
+	return q
+}
+
+func (q *Query) SortDesc(field string) *Query {
+	return q.Sort("-" + field)
+}
+
+func (q *Query) Fields(resource string, fields ...string) *Query {
+	key := queryFields
+	if resource != "" {
+		key = bracketKey(queryFields, resource)
+	}
+
+	q.values.Set(key, strings.Join(fields, ","))
+
+	return q
+}
+
+func (q *Query) Include(relations ...string) *Query {
+	q.include = append(q.include, relations...)
+
+	return q
+}
+
+func (q *Query) Page(key, value string) *Query {
+	q.values.Set(bracketKey(queryPage, key), value)
+
+	return q
+}
+
+func (q *Query) PageNumber(number int) *Query {
+	return q.Page("number", strconv.Itoa(number))
+}
+
+func (q *Query) PageSize(size int) *Query {
+	return q.Page("size", strconv.Itoa(size))
+}
+
+func (q *Query) PageCursor(cursor string) *Query {
+	return q.Page("cursor", cursor)
+}
+
+func (q *Query) Set(key, value string) *Query {
+	q.values.Set(key, value)
+
+	return q
+}
+
+func (q *Query) Values() url.Values {
+	values := url.Values{}
+
+	for key, val := range q.values {
+		values[key] = append([]string(nil), val...)
+	}
+
+	if len(q.sort) > 0 {
+		values.Set(querySort, strings.Join(q.sort, ","))
+	}
+
+	if len(q.include) > 0 {
+		values.Set(queryInclude, strings.Join(q.include, ","))
+	}
+
+	return values
+}
+
+func (q *Query) Params() Params {
+	params := Params{}
+	values := q.Values()
+
+	for key := range values {
+		params[key] = values.Get(key)
+	}
+
+	return params
+}
+
+func (q *Query) MultiParams() MultiParams {
+	return ParamsFrom(q.Values())
+}
+
+func (q *Query) Encode() string {
+	return q.Values().Encode()
+}
+
+func bracketKey(prefix, key string) string {
+	return prefix + "[" + key + "]"
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestQuery_Encode(t *testing.T) {
+	q := NewQuery().
+		Filter("status", "open").
+		FilterOp("created", "gte", "2024-01-01").
+		Sort("name").
+		SortDesc("created").
+		Fields("users", "id", "name").
+		Include("roles", "groups").
+		PageSize(20).
+		PageNumber(2)
+
+	values := q.Values()
+
+	want := map[string]string{
+		"filter[status]":       "open",
+		"filter[created][gte]": "2024-01-01",
+		"sort":                 "name,-created",
+		"fields[users]":        "id,name",
+		"include":              "roles,groups",
+		"page[size]":           "20",
+		"page[number]":         "2",
+	}
+	for key, val := range want {
+		if got := values.Get(key); got != val {
+			t.Errorf("%s=%q, want %q", key, got, val)
+		}
+	}
+	if len(values) != len(want) {
+		t.Fatalf("unexpected keys: %v", values)
+	}
+}
+
+func TestQuery_ParamsWithClient(t *testing.T) {
+	var gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	_, _, err := c.SendGet("/articles", NewQuery().Filter("tag", "go").Sort("-id").Params(), nil)
+	if err != nil {
+		t.Fatalf("SendGet error: %v", err)
+	}
+	if gotQuery != "filter%5Btag%5D=go&sort=-id" {
+		t.Fatalf("query=%s", gotQuery)
+	}
+}
@@ -0,0 +1,204 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// minRateLimitSweep is the bucket count at which idle buckets are first
+// looked for; after each sweep the next one waits until the count doubles.
+const minRateLimitSweep = 64
+
+type rateLimitContextKey struct{}
+
+// RateLimit allows Rate requests per second with bursts of up to Burst.
+// A zero Rate means unlimited.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitKeyFunc picks the bucket a request is counted against, for
+// example a tenant ID.
+type RateLimitKeyFunc func(ctx context.Context, spec *RequestSpec) string
+
+// WithRateLimitKey tags ctx with the key used by RateLimitKeyFromContext.
+func WithRateLimitKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, rateLimitContextKey{}, key)
+}
+
+func RateLimitKeyFromContext(ctx context.Context, _ *RequestSpec) string {
+	key, _ := ctx.Value(rateLimitContextKey{}).(string)
+
+	return key
+}
+
+func RateLimitKeyFromHeader(name string) RateLimitKeyFunc {
+	return func(_ context.Context, spec *RequestSpec) string {
+		return spec.Headers.Get(name)
+	}
+}
+
+// WithKeyedRateLimit gives every key its own token bucket so one tenant's
+// burst cannot use up the whole client. Keys without an explicit limit set
+// through SetRateLimit use defaultLimit. keyFunc defaults to
+// RateLimitKeyFromContext.
+func WithKeyedRateLimit(defaultLimit RateLimit, keyFunc RateLimitKeyFunc) Option {
+	return func(client *Client) error {
+		if defaultLimit.Rate < 0 {
+			return errors.New("rate limit must not be negative")
+		}
+
+		if keyFunc == nil {
+			keyFunc = RateLimitKeyFromContext
+		}
+
+		client.rateLimits = &keyedRateLimiter{
+			keyFunc:      keyFunc,
+			defaultLimit: defaultLimit,
+			limits:       map[string]RateLimit{},
+			buckets:      map[string]*tokenBucket{},
+		}
+
+		return nil
+	}
+}
+
+// SetRateLimit changes the limit for key at runtime. It is a no-op unless
+// WithKeyedRateLimit was used.
+func (client *Client) SetRateLimit(key string, limit RateLimit) {
+	if client.rateLimits != nil {
+		client.rateLimits.set(key, limit)
+	}
+}
+
+type keyedRateLimiter struct {
+	keyFunc      RateLimitKeyFunc
+	mu           sync.Mutex
+	defaultLimit RateLimit
+	limits       map[string]RateLimit
+	buckets      map[string]*tokenBucket
+	sweepAt      int
+}
+
+type tokenBucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+func (limiter *keyedRateLimiter) set(key string, limit RateLimit) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	limiter.limits[key] = limit
+
+	if bucket, ok := limiter.buckets[key]; ok {
+		bucket.limit = limit
+	}
+}
+
+func (limiter *keyedRateLimiter) wait(ctx context.Context, spec *RequestSpec) error {
+	if limiter == nil {
+		return nil
+	}
+
+	bucket, delay := limiter.reserve(limiter.keyFunc(ctx, spec))
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		limiter.refund(bucket)
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (limiter *keyedRateLimiter) reserve(key string) (*tokenBucket, time.Duration) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	now := time.Now()
+
+	bucket, ok := limiter.buckets[key]
+	if !ok {
+		limit, ok := limiter.limits[key]
+		if !ok {
+			limit = limiter.defaultLimit
+		}
+
+		if limit.Rate <= 0 {
+			return nil, 0
+		}
+
+		limiter.sweep(now)
+
+		bucket = &tokenBucket{limit: limit, tokens: float64(burstOf(limit)), last: now}
+		limiter.buckets[key] = bucket
+	}
+
+	if bucket.limit.Rate <= 0 {
+		return bucket, 0
+	}
+
+	bucket.refill(now)
+	bucket.tokens--
+
+	if bucket.tokens >= 0 {
+		return bucket, 0
+	}
+
+	return bucket, time.Duration(-bucket.tokens / bucket.limit.Rate * float64(time.Second))
+}
+
+// refund returns the token of a request that gave up waiting for it.
+func (limiter *keyedRateLimiter) refund(bucket *tokenBucket) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	bucket.tokens = min(bucket.tokens+1, float64(burstOf(bucket.limit)))
+}
+
+// sweep drops buckets that have refilled completely, since a new bucket for
+// the key would start in the same state. It must be called with limiter.mu
+// held.
+func (limiter *keyedRateLimiter) sweep(now time.Time) {
+	if len(limiter.buckets) < max(limiter.sweepAt, minRateLimitSweep) {
+		return
+	}
+
+	for key, bucket := range limiter.buckets {
+		if bucket.limit.Rate <= 0 || bucket.refill(now) {
+			delete(limiter.buckets, key)
+		}
+	}
+
+	limiter.sweepAt = 2 * len(limiter.buckets)
+}
+
+// refill adds the tokens earned since the last call and reports whether the
+// bucket is full.
+func (bucket *tokenBucket) refill(now time.Time) bool {
+	burst := float64(burstOf(bucket.limit))
+
+	bucket.tokens = min(bucket.tokens+now.Sub(bucket.last).Seconds()*bucket.limit.Rate, burst)
+	bucket.last = now
+
+	return bucket.tokens >= burst
+}
+
+func burstOf(limit RateLimit) int {
+	if limit.Burst < 1 {
+		return 1
+	}
+
+	return limit.Burst
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithKeyedRateLimit_IsolatesTenants(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithKeyedRateLimit(RateLimit{Rate: 5, Burst: 1}, nil))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	spec := RequestSpec{Method: http.MethodGet, Path: "/"}
+	send := func(tenant string) time.Duration {
+		started := time.Now()
+		if _, err := c.Send(WithRateLimitKey(context.Background(), tenant), spec); err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+		return time.Since(started)
+	}
+
+	send("a")
+	if d := send("a"); d < 150*time.Millisecond {
+		t.Fatalf("second request of tenant a was not delayed: %v", d)
+	}
+	if d := send("b"); d > 100*time.Millisecond {
+		t.Fatalf("tenant b was delayed by tenant a: %v", d)
+	}
+
+	c.SetRateLimit("a", RateLimit{})
+	if d := send("a"); d > 100*time.Millisecond {
+		t.Fatalf("unlimited tenant a was delayed: %v", d)
+	}
+}
+
+func TestRateLimitKeyFromHeader(t *testing.T) {
+	keyFunc := RateLimitKeyFromHeader("X-Tenant")
+	spec := &RequestSpec{Headers: MultiHeaders{"X-Tenant": {"t1"}}}
+
+	if key := keyFunc(context.Background(), spec); key != "t1" {
+		t.Fatalf("key=%q", key)
+	}
+}
+
+func TestKeyedRateLimiter_EvictsIdleBuckets(t *testing.T) {
+	limiter := &keyedRateLimiter{
+		defaultLimit: RateLimit{Rate: 1000, Burst: 1},
+		limits:       map[string]RateLimit{},
+		buckets:      map[string]*tokenBucket{},
+	}
+
+	for i := 0; i < 10*minRateLimitSweep; i++ {
+		limiter.reserve(strconv.Itoa(i))
+		if i%minRateLimitSweep == 0 {
+			time.Sleep(2 * time.Millisecond)
+		}
+	}
+
+	if n := len(limiter.buckets); n > 2*minRateLimitSweep {
+		t.Fatalf("buckets = %d, idle ones were not evicted", n)
+	}
+}
+
+func TestKeyedRateLimiter_RefundsCancelledWaits(t *testing.T) {
+	limiter := &keyedRateLimiter{
+		keyFunc:      RateLimitKeyFromContext,
+		defaultLimit: RateLimit{Rate: 1, Burst: 1},
+		limits:       map[string]RateLimit{},
+		buckets:      map[string]*tokenBucket{},
+	}
+	spec := &RequestSpec{}
+
+	if err := limiter.wait(context.Background(), spec); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		if err := limiter.wait(ctx, spec); err == nil {
+			t.Fatalf("wait %d did not time out", i)
+		}
+		cancel()
+	}
+
+	if _, delay := limiter.reserve(""); delay > time.Second {
+		t.Fatalf("delay = %v, cancelled waits kept their tokens", delay)
+	}
+}
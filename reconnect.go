@@ -0,0 +1,170 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	lastEventIDHeader = "Last-Event-ID"
+
+	defaultReconnectBackoff    = time.Second
+	defaultMaxReconnectBackoff = 30 * time.Second
+)
+
+// Reconnect is the reconnect policy of a stream. A stream that delivered
+// events and ended cleanly is sent again right away; failures, stalls and
+// attempts without events back off exponentially from Backoff up to
+// MaxBackoff, or from the SSE retry field when the server set one. Error
+// responses other than 408, 429 and 5xx are not retried, nor are local
+// errors such as ErrClientClosed or a failed request validation.
+type Reconnect struct {
+	// MaxAttempts limits consecutive attempts that failed or delivered no
+	// events. An attempt that delivered events resets the count. Zero means
+	// no limit.
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	// Resume adjusts the request before every reconnect. SSE streams get the
+	// Last-Event-ID header before it runs.
+	Resume func(spec *RequestSpec, state ResumeState)
+	// OnReconnect is called before waiting for the next attempt.
+	OnReconnect func(StreamReconnect)
+}
+
+// ResumeState is what a stream consumed so far, across all attempts.
+type ResumeState struct {
+	// Attempt counts reconnects, starting at 1.
+	Attempt     int
+	Events      int
+	LastEventID string
+	// Offset counts the raw body bytes of every delivered event.
+	Offset int64
+}
+
+type StreamReconnect struct {
+	Method string
+	Path   string
+	ResumeState
+	Delay time.Duration
+	// Err is nil when the server ended the stream cleanly.
+	Err error
+}
+
+// ResumeFromOffset is a Reconnect.Resume that asks for the rest of the body
+// with a Range header, for servers that support byte ranges on streams.
+func ResumeFromOffset(spec *RequestSpec, state ResumeState) {
+	if state.Offset > 0 {
+		spec.Headers["Range"] = []string{"bytes=" + strconv.FormatInt(state.Offset, 10) + "-"}
+	}
+}
+
+func (reconnect *Reconnect) delay(failures int, retry time.Duration) time.Duration {
+	backoff := reconnect.Backoff
+	if retry > 0 {
+		backoff = retry
+	} else if backoff <= 0 {
+		backoff = defaultReconnectBackoff
+	}
+
+	limit := reconnect.MaxBackoff
+	if limit <= 0 {
+		limit = defaultMaxReconnectBackoff
+	}
+
+	for i := 1; i < failures && backoff < limit; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, limit)
+}
+
+func (state *streamState) resume(attempt int) ResumeState {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	return ResumeState{
+		Attempt:     attempt,
+		Events:      state.events,
+		LastEventID: state.lastEventID,
+		Offset:      state.offset,
+	}
+}
+
+func (config StreamConfig) resumeSpec(spec RequestSpec, state ResumeState) RequestSpec {
+	headers := make(MultiHeaders, len(spec.Headers)+1)
+	for key, values := range spec.Headers {
+		headers[key] = values
+	}
+
+	spec.Headers = headers
+
+	if config.Format == StreamSSE && state.LastEventID != "" {
+		spec.Headers[lastEventIDHeader] = []string{state.LastEventID}
+	}
+
+	if config.Reconnect.Resume != nil {
+		config.Reconnect.Resume(&spec, state)
+	}
+
+	return spec
+}
+
+func reconnectable(response *Response, err error) bool {
+	if err == nil {
+		return true
+	}
+
+	if response != nil {
+		if !errors.Is(err, ErrRequestFailed) {
+			return true
+		}
+
+		status := response.StatusCode
+
+		return status == http.StatusRequestTimeout || status == http.StatusTooManyRequests ||
+			status >= http.StatusInternalServerError
+	}
+
+	var (
+		urlErr      *url.Error
+		redirectErr *RedirectError
+		timeoutErr  *TimeoutError
+	)
+
+	if errors.As(err, &redirectErr) {
+		return false
+	}
+
+	return errors.As(err, &urlErr) || errors.As(err, &timeoutErr) ||
+		errors.Is(err, ErrStreamStalled) || errors.Is(err, ErrCircuitOpen)
+}
+
+func (client *Client) reconnecting(
+	spec RequestSpec,
+	config StreamConfig,
+	state ResumeState,
+	delay time.Duration,
+	err error,
+) {
+	client.logger.Info().
+		Err(err).
+		Str(client.logField("method"), spec.Method).
+		Str("path", spec.Path).
+		Int("attempt", state.Attempt).
+		Dur("delay", delay).
+		Msg("http stream reconnecting")
+
+	if config.Reconnect.OnReconnect != nil {
+		config.Reconnect.OnReconnect(StreamReconnect{
+			Method:      spec.Method,
+			Path:        spec.Path,
+			ResumeState: state,
+			Delay:       delay,
+			Err:         err,
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStream_ReconnectResumesSSE(t *testing.T) {
+	var calls atomic.Int32
+	var lastEventID atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			_, _ = w.Write([]byte("id: 1\ndata: a\n\n"))
+		case 2:
+			lastEventID.Store(r.Header.Get("Last-Event-ID"))
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			_, _ = w.Write([]byte("id: 2\ndata: b\n\n"))
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	var data []string
+	var reconnects []StreamReconnect
+	stop := errors.New("stop")
+
+	err := c.Stream(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}, StreamConfig{
+		Reconnect: &Reconnect{
+			Backoff:     time.Millisecond,
+			OnReconnect: func(r StreamReconnect) { reconnects = append(reconnects, r) },
+		},
+	}, func(event StreamEvent) error {
+		data = append(data, string(event.Data))
+		if len(data) == 2 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("err=%v", err)
+	}
+
+	if len(data) != 2 || data[1] != "b" || lastEventID.Load() != "1" {
+		t.Fatalf("data=%v lastEventID=%v", data, lastEventID.Load())
+	}
+	if len(reconnects) != 2 || reconnects[0].Err != nil || reconnects[1].Err == nil || reconnects[1].Attempt != 2 {
+		t.Fatalf("reconnects=%+v", reconnects)
+	}
+}
+
+func TestStream_ReconnectGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	err := c.Stream(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}, StreamConfig{
+		Format:    StreamLongPoll,
+		Reconnect: &Reconnect{MaxAttempts: 2, Backoff: time.Millisecond},
+	}, func(StreamEvent) error { return nil })
+	if !errors.Is(err, ErrRequestFailed) || calls.Load() != 3 {
+		t.Fatalf("err=%v calls=%d", err, calls.Load())
+	}
+}
+
+func TestStream_ReconnectSkipsClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	err := c.Stream(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}, StreamConfig{
+		Reconnect: &Reconnect{Backoff: time.Millisecond},
+	}, func(StreamEvent) error { return nil })
+	if !errors.Is(err, ErrRequestFailed) || calls.Load() != 1 {
+		t.Fatalf("err=%v calls=%d", err, calls.Load())
+	}
+}
+
+func TestStream_ResumeFromOffset(t *testing.T) {
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if len(ranges) == 1 {
+			_, _ = w.Write([]byte("{\"n\":1}\n{\"n\":2}\n"))
+			return
+		}
+		_, _ = w.Write([]byte("{\"n\":3}\n"))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	stop := errors.New("stop")
+
+	var lines int
+	err := c.Stream(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}, StreamConfig{
+		Format:    StreamNDJSON,
+		Reconnect: &Reconnect{Resume: ResumeFromOffset},
+	}, func(StreamEvent) error {
+		lines++
+		if lines == 3 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) || len(ranges) != 2 || ranges[1] != "bytes=16-" {
+		t.Fatalf("err=%v ranges=%q", err, ranges)
+	}
+}
+
+func TestReconnect_Delay(t *testing.T) {
+	reconnect := &Reconnect{Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	if d := reconnect.delay(1, 0); d != 100*time.Millisecond {
+		t.Fatalf("first delay=%v", d)
+	}
+	if d := reconnect.delay(3, 0); d != 400*time.Millisecond {
+		t.Fatalf("third delay=%v", d)
+	}
+	if d := reconnect.delay(10, 0); d != time.Second {
+		t.Fatalf("capped delay=%v", d)
+	}
+	if d := reconnect.delay(1, 50*time.Millisecond); d != 50*time.Millisecond {
+		t.Fatalf("retry field delay=%v", d)
+	}
+}
+
+func TestStream_ReconnectBacksOffOnEmptyStreams(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	var delays []time.Duration
+	err := c.Stream(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}, StreamConfig{
+		Reconnect: &Reconnect{
+			MaxAttempts: 3,
+			Backoff:     time.Millisecond,
+			OnReconnect: func(reconnect StreamReconnect) { delays = append(delays, reconnect.Delay) },
+		},
+	}, func(StreamEvent) error { return nil })
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+
+	if got := calls.Load(); got != 4 {
+		t.Fatalf("calls = %d, want 4", got)
+	}
+	for _, delay := range delays {
+		if delay <= 0 {
+			t.Fatalf("reconnect without backoff: %v", delays)
+		}
+	}
+}
+
+func TestStream_ReconnectStopsOnLocalErrors(t *testing.T) {
+	c := newTestClient(t, "http://127.0.0.1:1")
+	if err := c.Close(false); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	err := c.Stream(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}, StreamConfig{
+		Reconnect: &Reconnect{Backoff: time.Millisecond},
+	}, func(StreamEvent) error { return nil })
+	if !errors.Is(err, ErrClientClosed) {
+		t.Fatalf("err = %v, want ErrClientClosed", err)
+	}
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// defaultMaxRedirects matches the net/http default.
+	defaultMaxRedirects = 10
+	// redirectLoopVisits is how often a URL may already be in the chain
+	// before another redirect to it is a loop. One earlier visit is allowed
+	// so that a server can redirect to the same URL after setting a cookie.
+	redirectLoopVisits = 2
+)
+
+var (
+	ErrRedirectLoop     = errors.New("redirect loop")
+	ErrTooManyRedirects = errors.New("too many redirects")
+)
+
+// RedirectError reports a failed redirect chain. Chain lists the visited
+// URLs, first request first, with secret query parameters redacted.
+type RedirectError struct {
+	Err   error
+	Chain []string
+}
+
+func (e *RedirectError) Error() string {
+	return fmt.Sprintf("%v: %s", e.Err, strings.Join(e.Chain, " -> "))
+}
+
+func (e *RedirectError) Unwrap() error {
+	return e.Err
+}
+
+// WithMaxRedirects fails requests with ErrTooManyRedirects on their
+// limit-th redirect, as net/http does with its default of 10. Zero also
+// fails on the first redirect.
+func WithMaxRedirects(limit int) Option {
+	return func(client *Client) error {
+		if limit < 0 {
+			return errors.New("max redirects must not be negative")
+		}
+
+		client.maxRedirects = &limit
+
+		return nil
+	}
+}
+
+func (client *Client) checkRedirect(request *http.Request, via []*http.Request) error {
+	limit := defaultMaxRedirects
+	if client.maxRedirects != nil {
+		limit = *client.maxRedirects
+	}
+
+	chain := make([]string, 0, len(via)+1)
+	for _, previous := range via {
+		chain = append(chain, client.redactURL(previous.URL))
+	}
+
+	chain = append(chain, client.redactURL(request.URL))
+
+	visits := 0
+
+	for _, previous := range via {
+		if previous.Method == request.Method && previous.URL.String() == request.URL.String() {
+			visits++
+		}
+	}
+
+	if visits >= redirectLoopVisits {
+		return &RedirectError{Err: ErrRedirectLoop, Chain: chain}
+	}
+
+	if len(via) >= limit {
+		return &RedirectError{Err: ErrTooManyRedirects, Chain: chain}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestRedirect_LoopError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/a" {
+			http.Redirect(w, r, "/b?token=secret", http.StatusFound)
+			return
+		}
+		http.Redirect(w, r, "/a", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	_, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/a"})
+
+	var redirectErr *RedirectError
+	if !errors.As(err, &redirectErr) || !errors.Is(err, ErrRedirectLoop) {
+		t.Fatalf("err=%v", err)
+	}
+	b := srv.URL + "/b?token=%5BREDACTED%5D"
+	want := []string{srv.URL + "/a", b, srv.URL + "/a", b, srv.URL + "/a"}
+	if strings.Join(redirectErr.Chain, " ") != strings.Join(want, " ") {
+		t.Fatalf("chain=%q", redirectErr.Chain)
+	}
+}
+
+func TestRedirect_TooMany(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/"))
+		http.Redirect(w, r, "/"+strconv.Itoa(n+1), http.StatusFound)
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithMaxRedirects(2))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	_, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/0"})
+
+	var redirectErr *RedirectError
+	if !errors.As(err, &redirectErr) || !errors.Is(err, ErrTooManyRedirects) || len(redirectErr.Chain) != 3 {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestRedirect_ToSelfAfterSettingCookie(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("session"); err != nil {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "1", Path: "/"})
+			http.Redirect(w, r, r.URL.Path, http.StatusFound)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatalf("cookiejar.New: %v", err)
+	}
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithHTTPClient(&http.Client{Jar: jar}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	response, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/login"})
+	if err != nil || string(response.Body) != "ok" {
+		t.Fatalf("response=%v err=%v", response, err)
+	}
+}
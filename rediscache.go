@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// RedisClient is the subset of a Redis client RedisCache needs, so any
+// driver can be plugged in with a thin wrapper. Get returns ErrCacheMiss, or
+// a nil value, for keys that do not exist. A zero ttl means no expiry.
+type RedisClient interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Del(ctx context.Context, key string) error
+}
+
+// RedisCache is a Cache shared by every client that uses the same Redis.
+// Entries are stored as JSON under prefix+key and expire in Redis together
+// with the cached response.
+type RedisCache struct {
+	client RedisClient
+	prefix string
+}
+
+func NewRedisCache(client RedisClient, prefix string) *RedisCache {
+	return &RedisCache{client: client, prefix: prefix}
+}
+
+func (cache *RedisCache) Get(ctx context.Context, key string) (*CachedResponse, error) {
+	value, err := cache.client.Get(ctx, cache.prefix+key)
+	if err != nil {
+		return nil, err
+	}
+
+	if value == nil {
+		return nil, ErrCacheMiss
+	}
+
+	var entry CachedResponse
+	if err = json.Unmarshal(value, &entry); err != nil {
+		return nil, fmt.Errorf("decode redis cache entry: %w", err)
+	}
+
+	if !entry.ExpiresAt.IsZero() && time.Now().After(entry.ExpiresAt) {
+		return nil, ErrCacheMiss
+	}
+
+	return &entry, nil
+}
+
+func (cache *RedisCache) Set(ctx context.Context, key string, entry *CachedResponse) error {
+	var ttl time.Duration
+
+	if !entry.ExpiresAt.IsZero() {
+		if ttl = time.Until(entry.ExpiresAt); ttl <= 0 {
+			return nil
+		}
+	}
+
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return cache.client.Set(ctx, cache.prefix+key, value, ttl)
+}
+
+func (cache *RedisCache) Delete(ctx context.Context, key string) error {
+	return cache.client.Del(ctx, cache.prefix+key)
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type fakeRedis struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	ttls   map[string]time.Duration
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{values: map[string][]byte{}, ttls: map[string]time.Duration{}}
+}
+
+func (redis *fakeRedis) Get(_ context.Context, key string) ([]byte, error) {
+	redis.mu.Lock()
+	defer redis.mu.Unlock()
+
+	return redis.values[key], nil
+}
+
+func (redis *fakeRedis) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	redis.mu.Lock()
+	defer redis.mu.Unlock()
+
+	redis.values[key] = value
+	redis.ttls[key] = ttl
+
+	return nil
+}
+
+func (redis *fakeRedis) Del(_ context.Context, key string) error {
+	redis.mu.Lock()
+	defer redis.mu.Unlock()
+
+	delete(redis.values, key)
+
+	return nil
+}
+
+func TestRedisCache_SharedBetweenClients(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		_, _ = w.Write([]byte("shared"))
+	}))
+	defer srv.Close()
+
+	redis := newFakeRedis()
+	log := zerolog.Nop()
+
+	for i := 0; i < 2; i++ {
+		c, err := New(srv.URL, nil, &log, false, "ua", WithCache(NewRedisCache(redis, "http:"), time.Minute))
+		if err != nil {
+			t.Fatalf("New() error: %v", err)
+		}
+
+		resp, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/items"})
+		if err != nil {
+			t.Fatalf("Send() error: %v", err)
+		}
+		if string(resp.Body) != "shared" {
+			t.Fatalf("body = %q", resp.Body)
+		}
+	}
+
+	if hits != 1 {
+		t.Fatalf("hits = %d, want 1", hits)
+	}
+
+	for key, ttl := range redis.ttls {
+		if len(key) < 5 || key[:5] != "http:" {
+			t.Fatalf("key %q lacks prefix", key)
+		}
+		if ttl <= 0 || ttl > time.Minute {
+			t.Fatalf("ttl = %v, want up to a minute", ttl)
+		}
+	}
+}
+
+func TestRedisCache_MissesAndDelete(t *testing.T) {
+	ctx := context.Background()
+	cache := NewRedisCache(newFakeRedis(), "")
+
+	if _, err := cache.Get(ctx, "missing"); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("err = %v, want ErrCacheMiss", err)
+	}
+
+	_ = cache.Set(ctx, "k", &CachedResponse{StatusCode: http.StatusOK, Body: []byte("x")})
+
+	if entry, err := cache.Get(ctx, "k"); err != nil || string(entry.Body) != "x" {
+		t.Fatalf("Get = %+v, %v", entry, err)
+	}
+
+	if err := cache.Delete(ctx, "k"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := cache.Get(ctx, "k"); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("err after Delete = %v, want ErrCacheMiss", err)
+	}
+
+	if err := cache.Set(ctx, "old", &CachedResponse{ExpiresAt: time.Now().Add(-time.Second)}); err != nil {
+		t.Fatalf("Set expired: %v", err)
+	}
+	if _, err := cache.Get(ctx, "old"); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("expired entry was stored")
+	}
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type RequestOption func(options *requestOptions)
+
+type requestOptions struct {
+	body          any
+	hasBody       bool
+	bodyMarshaler BodyMarshaler
+	sink          io.Writer
+	keepBody      int64
+	stripHeaders  []string
+	contentLength int64
+	formFields    url.Values
+
+	strictDecoding *bool
+	timeout        time.Duration
+	cacheMode      CacheMode
+	retry          *RetryPolicy
+	success        func(*Response) bool
+	// streaming exempts the body from the client timeout; see Stream.
+	streaming bool
+	// acceptEncoding is nil unless AcceptEncoding was used.
+	acceptEncoding []string
+	// absoluteUrl allows an absolute Path; see followUrl.
+	absoluteUrl bool
+	// headers are set by helpers and bypass the allowlist.
+	headers MultiHeaders
+	// jar holds the cookies of the Session sending the request.
+	jar http.CookieJar
+	// destination is a relative WebDAV Destination, joined with the base
+	// URL of each attempt.
+	destination string
+
+	// sent is set once an attempt reached the transport.
+	sent bool
+	// staleConnection reports that the last attempt failed on a reused
+	// connection before any response arrived.
+	staleConnection bool
+	// triedEndpoints records the base URLs used by earlier attempts.
+	triedEndpoints []string
+}
+
+// followUrl lets Path be an absolute URL. Only pagination links and async
+// status URLs use it, once resolveSameOrigin checked them.
+func followUrl() RequestOption {
+	return func(options *requestOptions) {
+		options.absoluteUrl = true
+	}
+}
+
+func newRequestOptions(opts []RequestOption) *requestOptions {
+	options := &requestOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return options
+}
+
+// SuccessWhen replaces the status check that fails responses of 300 and
+// above with ErrRequestFailed. For requests with a Sink, predicate decides
+// whether the body is streamed and sees no body.
+func SuccessWhen(predicate func(*Response) bool) RequestOption {
+	return func(options *requestOptions) {
+		options.success = predicate
+	}
+}
+
+func (options *requestOptions) streams(response *http.Response) bool {
+	if options.success == nil {
+		return response.StatusCode < http.StatusMultipleChoices
+	}
+
+	return options.success(&Response{StatusCode: response.StatusCode, Header: response.Header})
+}
+
+// judge applies SuccessWhen to a response whose body was read.
+func (options *requestOptions) judge(response *Response, err error) error {
+	if options.success == nil || (err != nil && !errors.Is(err, ErrRequestFailed)) {
+		return err
+	}
+
+	if options.success(response) {
+		return nil
+	}
+
+	return ErrRequestFailed
+}
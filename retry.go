@@ -0,0 +1,222 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+)
+
+const (
+	defaultRetryAttempts   = 3
+	defaultRetryBackoff    = 100 * time.Millisecond
+	defaultRetryMaxBackoff = 5 * time.Second
+)
+
+var defaultRetryStatusCodes = []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+
+// RetryAttempt is one failed attempt of a retried request.
+type RetryAttempt struct {
+	Err error
+	// StatusCode is zero for transport errors.
+	StatusCode int
+	Endpoint   string
+	Duration   time.Duration
+}
+
+// RetriesExhaustedError is returned once WithRetry gave up. errors.Is and
+// errors.As look through the error of every attempt.
+type RetriesExhaustedError struct {
+	Attempts []RetryAttempt
+}
+
+func (e *RetriesExhaustedError) Error() string {
+	last := e.Attempts[len(e.Attempts)-1]
+
+	return fmt.Sprintf("retries exhausted after %d attempts: %v", len(e.Attempts), last.Err)
+}
+
+func (e *RetriesExhaustedError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Attempts))
+	for _, attempt := range e.Attempts {
+		errs = append(errs, attempt.Err)
+	}
+
+	return errs
+}
+
+// RetryPolicy configures WithRetry.
+type RetryPolicy struct {
+	// MaxAttempts counts every attempt including the first; 3 by default.
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	// StatusCodes are retried besides transport errors; 502, 503 and 504
+	// by default.
+	StatusCodes []int
+	// NonIdempotent also retries POST, PATCH and other unsafe methods.
+	NonIdempotent bool
+}
+
+// WithRetry re-sends requests that failed with a transport error or one of
+// the policy's status codes, waiting with exponential backoff between
+// attempts. A Retry-After header on 429 and 503 answers takes precedence.
+// Request bodies are buffered so every attempt sends them again. Running out
+// of attempts returns a RetriesExhaustedError.
+func WithRetry(policy RetryPolicy) Option {
+	return func(client *Client) error {
+		client.retry = policy.withDefaults()
+
+		return nil
+	}
+}
+
+// Retry overrides the client's retry policy for a single request.
+func Retry(policy RetryPolicy) RequestOption {
+	return func(options *requestOptions) {
+		options.retry = policy.withDefaults()
+	}
+}
+
+// NoRetry sends a single attempt even when the client retries.
+func NoRetry() RequestOption {
+	return func(options *requestOptions) {
+		options.retry = &RetryPolicy{MaxAttempts: 1}
+	}
+}
+
+func (policy RetryPolicy) withDefaults() *RetryPolicy {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = defaultRetryAttempts
+	}
+
+	if policy.Backoff <= 0 {
+		policy.Backoff = defaultRetryBackoff
+	}
+
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = defaultRetryMaxBackoff
+	}
+
+	if policy.StatusCodes == nil {
+		policy.StatusCodes = defaultRetryStatusCodes
+	}
+
+	return &policy
+}
+
+func (client *Client) exchangeWithRetry(
+	ctx context.Context,
+	spec *RequestSpec,
+	options *requestOptions,
+	cacheKey string,
+) (*Response, error) {
+	policy := client.retry
+	if options.retry != nil {
+		policy = options.retry
+	}
+
+	if policy == nil || policy.MaxAttempts < 2 || (!policy.NonIdempotent && !isIdempotent(spec.Method)) {
+		return client.exchangeWithStaleRetry(ctx, spec, options, cacheKey)
+	}
+
+	rewind, err := rewindSpecBody(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	backoff := policy.Backoff
+
+	var attempts []RetryAttempt
+
+	for attempt := 1; ; attempt++ {
+		rewind()
+
+		started := time.Now()
+		result, err := client.exchangeWithStaleRetry(ctx, spec, options, cacheKey)
+		attempts = append(attempts, client.retryAttempt(result, err, options, time.Since(started)))
+
+		if result != nil {
+			result.Retries += attempt - 1
+		}
+
+		if ctx.Err() != nil || !policy.retryable(result, err, options) {
+			return result, err
+		}
+
+		if attempt >= policy.MaxAttempts {
+			return result, &RetriesExhaustedError{Attempts: attempts}
+		}
+
+		delay := retryDelay(result, backoff)
+
+		client.logger.Warn().
+			Err(err).
+			Str(client.logField("method"), spec.Method).
+			Func(client.logURL(client.baseUrl+spec.Path)).
+			Int("attempt", attempt).
+			Dur("backoff", delay).
+			Msg("retrying http request")
+
+		if err = sleepContext(ctx, delay); err != nil {
+			return result, err
+		}
+
+		if backoff *= 2; backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+// retryDelay is backoff, or the Retry-After of a 429 or 503 answer.
+func retryDelay(result *Response, backoff time.Duration) time.Duration {
+	if result != nil && (result.StatusCode == http.StatusTooManyRequests ||
+		result.StatusCode == http.StatusServiceUnavailable) {
+		return retryAfter(result.Header, backoff)
+	}
+
+	return backoff
+}
+
+func (client *Client) retryAttempt(
+	result *Response,
+	err error,
+	options *requestOptions,
+	duration time.Duration,
+) RetryAttempt {
+	attempt := RetryAttempt{Err: err, Endpoint: client.baseUrl, Duration: duration}
+
+	if tried := options.triedEndpoints; len(tried) > 0 {
+		attempt.Endpoint = tried[len(tried)-1]
+	}
+
+	if result != nil {
+		attempt.StatusCode = result.StatusCode
+	}
+
+	return attempt
+}
+
+func (policy *RetryPolicy) retryable(result *Response, err error, options *requestOptions) bool {
+	if err == nil {
+		return false
+	}
+
+	if result == nil {
+		var (
+			urlErr      *url.Error
+			redirectErr *RedirectError
+		)
+
+		return errors.As(err, &urlErr) && !errors.As(err, &redirectErr)
+	}
+
+	if options.sink != nil && result.BytesWritten > 0 {
+		return false
+	}
+
+	return errors.Is(err, ErrRequestFailed) && slices.Contains(policy.StatusCodes, result.StatusCode)
+}
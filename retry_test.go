@@ -0,0 +1,154 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func newRetryClient(t *testing.T, url string, policy RetryPolicy) *Client {
+	t.Helper()
+	log := zerolog.Nop()
+	c, err := New(url, nil, &log, false, "ua", WithRetry(policy))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	return c
+}
+
+func TestWithRetry_RetriesStatusAndResendsBody(t *testing.T) {
+	var calls atomic.Int32
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	c := newRetryClient(t, srv.URL, RetryPolicy{Backoff: time.Millisecond})
+
+	resp, err := c.Send(context.Background(), RequestSpec{Method: http.MethodPut, Path: "/", Body: strings.NewReader("payload")})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if string(resp.Body) != "ok" || resp.Retries != 2 {
+		t.Fatalf("body=%q retries=%d", resp.Body, resp.Retries)
+	}
+	for _, body := range bodies {
+		if body != "payload" {
+			t.Fatalf("bodies=%q", bodies)
+		}
+	}
+}
+
+func TestWithRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	c := newRetryClient(t, srv.URL, RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond})
+
+	resp, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"})
+	if !errors.Is(err, ErrRequestFailed) || calls.Load() != 2 || resp.Retries != 1 {
+		t.Fatalf("err=%v calls=%d", err, calls.Load())
+	}
+
+	var exhausted *RetriesExhaustedError
+	if !errors.As(err, &exhausted) || len(exhausted.Attempts) != 2 {
+		t.Fatalf("err=%#v", err)
+	}
+	for _, attempt := range exhausted.Attempts {
+		if attempt.StatusCode != http.StatusBadGateway || attempt.Endpoint != srv.URL || attempt.Duration <= 0 {
+			t.Fatalf("attempt=%+v", attempt)
+		}
+	}
+}
+
+func TestWithRetry_SkipsUnlistedStatusAndPost(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := newRetryClient(t, srv.URL, RetryPolicy{Backoff: time.Millisecond})
+
+	if _, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); err == nil || calls.Load() != 1 {
+		t.Fatalf("500 retried: calls=%d", calls.Load())
+	}
+
+	calls.Store(0)
+
+	if _, err := c.Send(context.Background(), RequestSpec{Method: http.MethodPost, Path: "/"}); err == nil || calls.Load() != 1 {
+		t.Fatalf("POST retried: calls=%d", calls.Load())
+	}
+}
+
+func TestWithRetry_TransportError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := srv.URL
+	srv.Close()
+
+	var buf bytes.Buffer
+	log := zerolog.New(&buf)
+	c, err := New(url, nil, &log, false, "ua", WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if n := strings.Count(buf.String(), "retrying http request"); n != 2 {
+		t.Fatalf("retries logged=%d", n)
+	}
+}
+
+func TestRetryRequestOptions_OverrideClientPolicy(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := newRetryClient(t, srv.URL, RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond})
+
+	if _, _, err := c.SendGet("/", nil, nil, NoRetry()); !errors.Is(err, ErrRequestFailed) {
+		t.Fatalf("err = %v, want ErrRequestFailed", err)
+	}
+	if got := calls.Swap(0); got != 1 {
+		t.Fatalf("NoRetry calls = %d, want 1", got)
+	}
+
+	_, _, err := c.SendPost("/", nil, nil, nil, Retry(RetryPolicy{MaxAttempts: 4, Backoff: time.Millisecond, NonIdempotent: true}))
+	var exhausted *RetriesExhaustedError
+	if !errors.As(err, &exhausted) || len(exhausted.Attempts) != 4 {
+		t.Fatalf("err = %v, want 4 exhausted attempts", err)
+	}
+	if got := calls.Load(); got != 4 {
+		t.Fatalf("Retry calls = %d, want 4", got)
+	}
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrScopeClosed = errors.New("client scope closed")
+
+// Scope binds requests to one context for fan-out work: the first failing
+// request or function cancels everything else in the scope, and Close
+// cancels whatever is still in flight.
+//
+//	scope := c.Scoped(ctx)
+//	for _, id := range ids {
+//		scope.Go(func(ctx context.Context) error {
+//			_, err := scope.Send(client.RequestSpec{Method: http.MethodGet, Path: "/items/" + id})
+//			return err
+//		})
+//	}
+//	err := scope.Wait()
+type Scope struct {
+	client *Client
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	err    error
+}
+
+func (client *Client) Scoped(ctx context.Context) *Scope {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	return &Scope{client: client, ctx: ctx, cancel: cancel}
+}
+
+// Context is canceled once the scope failed or was closed.
+func (scope *Scope) Context() context.Context {
+	return scope.ctx
+}
+
+// Send sends spec with the scope context. An error cancels the scope.
+func (scope *Scope) Send(spec RequestSpec, opts ...RequestOption) (*Response, error) {
+	response, err := scope.client.Send(scope.ctx, spec, opts...)
+	if err != nil {
+		scope.fail(err)
+	}
+
+	return response, err
+}
+
+// Go runs fn in its own goroutine with the scope context. An error cancels
+// the scope.
+func (scope *Scope) Go(fn func(ctx context.Context) error) {
+	scope.wg.Add(1)
+
+	go func() {
+		defer scope.wg.Done()
+
+		if err := fn(scope.ctx); err != nil {
+			scope.fail(err)
+		}
+	}()
+}
+
+// Wait waits for every function started with Go, releases the scope and
+// returns the first error.
+func (scope *Scope) Wait() error {
+	scope.wg.Wait()
+	scope.cancel(ErrScopeClosed)
+
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+
+	return scope.err
+}
+
+// Close cancels every request still in flight. Requests sent afterwards
+// fail right away.
+func (scope *Scope) Close() {
+	scope.cancel(ErrScopeClosed)
+}
+
+func (scope *Scope) fail(err error) {
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+
+	if scope.err == nil {
+		scope.err = err
+		scope.cancel(err)
+	}
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestScope_FirstErrorCancelsOthers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	scope := c.Scoped(context.Background())
+
+	slowErr := make(chan error, 1)
+	scope.Go(func(ctx context.Context) error {
+		_, err := scope.Send(RequestSpec{Method: http.MethodGet, Path: "/slow"})
+		slowErr <- err
+		return err
+	})
+	scope.Go(func(ctx context.Context) error {
+		_, err := scope.Send(RequestSpec{Method: http.MethodGet, Path: "/fail"})
+		return err
+	})
+
+	started := time.Now()
+	if err := scope.Wait(); !errors.Is(err, ErrRequestFailed) {
+		t.Fatalf("Wait error: %v", err)
+	}
+	if time.Since(started) > time.Second {
+		t.Fatal("slow request was not canceled")
+	}
+	if err := <-slowErr; err == nil {
+		t.Fatal("slow request succeeded")
+	}
+}
+
+func TestScope_Close(t *testing.T) {
+	c := newTestClient(t, "http://127.0.0.1:1")
+	scope := c.Scoped(context.Background())
+	scope.Close()
+
+	if !errors.Is(context.Cause(scope.Context()), ErrScopeClosed) {
+		t.Fatalf("cause=%v", context.Cause(scope.Context()))
+	}
+	if _, err := scope.Send(RequestSpec{Method: http.MethodGet, Path: "/"}); err == nil {
+		t.Fatal("send after Close succeeded")
+	}
+	if err := scope.Wait(); err == nil {
+		t.Fatal("Wait after failed send returned nil")
+	}
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SecretSource looks secrets up by name at runtime, e.g. from Vault or SSM
+// Parameter Store.
+type SecretSource interface {
+	Get(ctx context.Context, name string) (string, error)
+}
+
+type SecretSourceFunc func(ctx context.Context, name string) (string, error)
+
+func (fn SecretSourceFunc) Get(ctx context.Context, name string) (string, error) {
+	return fn(ctx, name)
+}
+
+type secretToken struct {
+	source SecretSource
+	name   string
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	value   string
+	fetched time.Time
+}
+
+// SecretToken reads the secret name from source as a TokenSource for the
+// auth options. The value is looked up again once ttl has passed, so a
+// rotated secret is picked up without a restart; a non-positive ttl looks it
+// up for every request. If a lookup fails the last value keeps being used.
+func SecretToken(source SecretSource, name string, ttl time.Duration) TokenSource {
+	return &secretToken{source: source, name: name, ttl: ttl}
+}
+
+func (token *secretToken) Token(ctx context.Context) (Token, error) {
+	token.mu.Lock()
+	defer token.mu.Unlock()
+
+	if token.value != "" && time.Since(token.fetched) < token.ttl {
+		return Token{Value: token.value}, nil
+	}
+
+	value, err := token.source.Get(ctx, token.name)
+	if err != nil {
+		if token.value != "" {
+			return Token{Value: token.value}, nil
+		}
+
+		return Token{}, err
+	}
+
+	token.value, token.fetched = value, time.Now()
+
+	return Token{Value: value}, nil
+}
+
+func (token *secretToken) String() string { return "secret:" + token.name }
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestSecretToken_PicksUpRotation(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("X-API-Key")
+	}))
+	defer srv.Close()
+
+	secret := "v1"
+	var failing bool
+	source := SecretSourceFunc(func(_ context.Context, name string) (string, error) {
+		if name != "payments/api-key" {
+			t.Errorf("name=%s", name)
+		}
+		if failing {
+			return "", errors.New("vault sealed")
+		}
+		return secret, nil
+	})
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithAPIKey("X-API-Key", SecretToken(source, "payments/api-key", 0)))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	send := func() {
+		t.Helper()
+		if _, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+	}
+
+	send()
+	if got != "v1" {
+		t.Fatalf("key=%q", got)
+	}
+
+	secret = "v2"
+	send()
+	if got != "v2" {
+		t.Fatalf("rotated key not used: %q", got)
+	}
+
+	failing = true
+	send()
+	if got != "v2" {
+		t.Fatalf("last key not kept on failure: %q", got)
+	}
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Session carries cookies, consistency tokens (see WithReadYourWrites) and
+// headers across a sequence of calls that share the underlying Client.
+type Session struct {
+	client *Client
+	ctx    context.Context
+	state  *sessionState
+}
+
+type sessionState struct {
+	mu      sync.Mutex
+	jar     http.CookieJar
+	headers MultiHeaders
+
+	csrf      *CSRFConfig
+	csrfToken string
+}
+
+// NewSession starts a session whose calls run with ctx.
+func (client *Client) NewSession(ctx context.Context) *Session {
+	jar, _ := cookiejar.New(nil)
+
+	return &Session{
+		client: client,
+		ctx:    WithConsistencyScope(ctx),
+		state:  &sessionState{jar: jar, headers: MultiHeaders{}},
+	}
+}
+
+// WithContext returns a session sharing this one's state whose calls run
+// with ctx, e.g. to set a deadline for a single call.
+func (session *Session) WithContext(ctx context.Context) *Session {
+	scoped := *session
+	scoped.ctx = context.WithValue(ctx, consistencyContextKey{}, consistencyScopeFrom(session.ctx))
+
+	return &scoped
+}
+
+// SetHeader sets a header sent with every call of the session; headers of
+// the request itself take precedence.
+func (session *Session) SetHeader(key, val string) *Session {
+	session.state.mu.Lock()
+	defer session.state.mu.Unlock()
+
+	session.state.headers.Set(key, val)
+
+	return session
+}
+
+// Cookies returns the session cookies that would be sent to rawUrl.
+func (session *Session) Cookies(rawUrl string) []*http.Cookie {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return nil
+	}
+
+	return session.state.jar.Cookies(u)
+}
+
+func (session *Session) Send(spec RequestSpec, opts ...RequestOption) (*Response, error) {
+	// Only the caller's headers go through the allowlist; session headers,
+	// cookies and the CSRF token are the session's own.
+	session.client.filterHeaders(&spec)
+
+	headers := spec.Headers.Clone()
+	if headers == nil {
+		headers = MultiHeaders{}
+	}
+
+	session.state.mu.Lock()
+	for key, values := range session.state.headers {
+		if len(headers.Values(key)) == 0 {
+			headers[key] = append([]string(nil), values...)
+		}
+	}
+	session.state.mu.Unlock()
+
+	spec.Headers = headers
+
+	opts, err := session.applyCSRF(&spec, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cookies are matched against the endpoint each attempt goes to and
+	// stored for the URL that answered.
+	opts = append(opts, withCookieJar(session.state.jar))
+
+	response, err := session.client.sendFiltered(session.ctx, spec, opts)
+	session.observeCSRF(response)
+
+	return response, err
+}
+
+func withCookieJar(jar http.CookieJar) RequestOption {
+	return func(options *requestOptions) {
+		options.jar = jar
+	}
+}
+
+// attachCookies sets the Cookie header from the session jar, if any.
+func attachCookies(request *http.Request, options *requestOptions) {
+	if options.jar == nil {
+		return
+	}
+
+	cookies := options.jar.Cookies(request.URL)
+	if len(cookies) == 0 {
+		return
+	}
+
+	pairs := make([]string, 0, len(cookies))
+	for _, cookie := range cookies {
+		pairs = append(pairs, cookie.String())
+	}
+
+	request.Header.Set("Cookie", strings.Join(pairs, "; "))
+}
+
+func storeCookies(response *http.Response, options *requestOptions) {
+	if options.jar != nil && response.Request != nil {
+		options.jar.SetCookies(response.Request.URL, response.Cookies())
+	}
+}
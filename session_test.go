@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestSession_CarriesCookiesHeadersAndTokens(t *testing.T) {
+	var gotCookie, gotTenant, gotToken string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "sid", Value: "abc", Path: "/"})
+		case "/orders":
+			if r.Method == http.MethodPost {
+				w.Header().Set(defaultConsistencyHeader, "lsn-7")
+				return
+			}
+			gotCookie = r.Header.Get("Cookie")
+			gotTenant = r.Header.Get("X-Tenant")
+			gotToken = r.Header.Get(defaultConsistencyHeader)
+		}
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithReadYourWrites())
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	session := c.NewSession(context.Background()).SetHeader("X-Tenant", "acme")
+	for _, spec := range []RequestSpec{
+		{Method: http.MethodPost, Path: "/login"},
+		{Method: http.MethodPost, Path: "/orders"},
+		{Method: http.MethodGet, Path: "/orders"},
+	} {
+		if _, err = session.Send(spec); err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+	}
+
+	if gotCookie != "sid=abc" || gotTenant != "acme" || gotToken != "lsn-7" {
+		t.Fatalf("cookie=%q tenant=%q token=%q", gotCookie, gotTenant, gotToken)
+	}
+
+	if _, err = c.NewSession(context.Background()).Send(RequestSpec{Method: http.MethodGet, Path: "/orders"}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if gotCookie != "" || gotToken != "" {
+		t.Fatalf("state leaked between sessions: cookie=%q token=%q", gotCookie, gotToken)
+	}
+}
+
+func TestSession_CookiesFollowServingEndpoint(t *testing.T) {
+	var mismatched, matched atomic.Int32
+	handler := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/login" {
+				http.SetCookie(w, &http.Cookie{Name: "sid", Value: name, Path: "/"})
+				return
+			}
+			switch cookie, err := r.Cookie("sid"); {
+			case err != nil:
+			case cookie.Value == name:
+				matched.Add(1)
+			default:
+				mismatched.Add(1)
+			}
+		}
+	}
+	a := httptest.NewServer(handler("a"))
+	defer a.Close()
+	b := httptest.NewServer(handler("b"))
+	defer b.Close()
+
+	// Cookies ignore ports, so the endpoints need different host names.
+	log := zerolog.Nop()
+	c, err := New(a.URL, nil, &log, false, "ua", WithEndpoints(strings.Replace(b.URL, "127.0.0.1", "localhost", 1)))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	session := c.NewSession(context.Background())
+	for i := 0; i < 20; i++ {
+		for _, path := range []string{"/login", "/me"} {
+			if _, err = session.Send(RequestSpec{Method: http.MethodGet, Path: path}); err != nil {
+				t.Fatalf("Send error: %v", err)
+			}
+		}
+	}
+
+	if mismatched.Load() != 0 || matched.Load() == 0 {
+		t.Fatalf("matched=%d mismatched=%d", matched.Load(), mismatched.Load())
+	}
+}
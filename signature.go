@@ -0,0 +1,438 @@
+package client
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	signatureHeader      = "Signature"
+	signatureInputHeader = "Signature-Input"
+	defaultSignatureName = "sig1"
+
+	// signatureClockSkew is how far in the future a created parameter may be.
+	signatureClockSkew = time.Minute
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid http message signature")
+	ErrSignatureExpired = errors.New("http message signature expired")
+)
+
+var defaultSignatureComponents = []string{"@method", "@authority", "@path", "@query"}
+
+// MessageSigner produces RFC 9421 signatures over a signature base.
+type MessageSigner interface {
+	Algorithm() string
+	Sign(base []byte) ([]byte, error)
+}
+
+// MessageVerifier checks RFC 9421 signatures over a signature base.
+type MessageVerifier interface {
+	Verify(base, signature []byte) error
+}
+
+type hmacSigner []byte
+
+// HMACSigner signs and verifies with hmac-sha256.
+func HMACSigner(key []byte) interface {
+	MessageSigner
+	MessageVerifier
+} {
+	return hmacSigner(key)
+}
+
+func (hmacSigner) Algorithm() string { return "hmac-sha256" }
+
+func (key hmacSigner) Sign(base []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(base)
+
+	return mac.Sum(nil), nil
+}
+
+func (key hmacSigner) Verify(base, signature []byte) error {
+	expected, _ := key.Sign(base)
+	if !hmac.Equal(expected, signature) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+type ed25519Signer ed25519.PrivateKey
+
+// Ed25519Signer signs with ed25519.
+func Ed25519Signer(key ed25519.PrivateKey) MessageSigner {
+	return ed25519Signer(key)
+}
+
+func (ed25519Signer) Algorithm() string { return "ed25519" }
+
+func (key ed25519Signer) Sign(base []byte) ([]byte, error) {
+	return ed25519.Sign(ed25519.PrivateKey(key), base), nil
+}
+
+type ed25519Verifier ed25519.PublicKey
+
+// Ed25519Verifier verifies ed25519 signatures.
+func Ed25519Verifier(key ed25519.PublicKey) MessageVerifier {
+	return ed25519Verifier(key)
+}
+
+func (key ed25519Verifier) Verify(base, signature []byte) error {
+	if !ed25519.Verify(ed25519.PublicKey(key), base, signature) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// MessageSignatures configures RFC 9421 request signing. Components are
+// derived components such as "@method", "@target-uri", "@authority",
+// "@scheme", "@request-target", "@path" and "@query", or lowercase header
+// names; "@method", "@authority", "@path" and "@query" by default.
+type MessageSignatures struct {
+	Signer     MessageSigner
+	KeyID      string
+	Components []string
+	// Label names the signature, "sig1" by default.
+	Label   string
+	Expires time.Duration
+	Tag     string
+}
+
+// WithMessageSignatures signs every request with Signature-Input and
+// Signature headers once all other headers, including auth and digests,
+// are set.
+func WithMessageSignatures(config MessageSignatures) Option {
+	return func(client *Client) error {
+		if config.Signer == nil {
+			return errors.New("message signatures: no signer")
+		}
+
+		if len(config.Components) == 0 {
+			config.Components = defaultSignatureComponents
+		}
+
+		if config.Label == "" {
+			config.Label = defaultSignatureName
+		}
+
+		client.signatures = &config
+
+		return nil
+	}
+}
+
+func (client *Client) signRequest(request *http.Request, nonce string, created time.Time) error {
+	config := client.signatures
+	if config == nil {
+		return nil
+	}
+
+	params := serializeSignatureParams(config.Components, created, nonce, config)
+
+	base, err := signatureBase(config.Components, params, func(component string) (string, error) {
+		return requestComponent(request, component)
+	})
+	if err != nil {
+		return err
+	}
+
+	signature, err := config.Signer.Sign(base)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set(signatureInputHeader, config.Label+"="+params)
+	request.Header.Set(signatureHeader, config.Label+"=:"+base64.StdEncoding.EncodeToString(signature)+":")
+
+	return nil
+}
+
+func serializeSignatureParams(components []string, created time.Time, nonce string, config *MessageSignatures) string {
+	quoted := make([]string, 0, len(components))
+	for _, component := range components {
+		quoted = append(quoted, strconv.Quote(component))
+	}
+
+	params := "(" + strings.Join(quoted, " ") + ");created=" + strconv.FormatInt(created.Unix(), 10)
+
+	if config.Expires > 0 {
+		params += ";expires=" + strconv.FormatInt(created.Add(config.Expires).Unix(), 10)
+	}
+
+	if nonce != "" {
+		params += ";nonce=" + strconv.Quote(nonce)
+	}
+
+	if config.KeyID != "" {
+		params += ";keyid=" + strconv.Quote(config.KeyID)
+	}
+
+	params += ";alg=" + strconv.Quote(config.Signer.Algorithm())
+
+	if config.Tag != "" {
+		params += ";tag=" + strconv.Quote(config.Tag)
+	}
+
+	return params
+}
+
+func signatureBase(components []string, params string, value func(string) (string, error)) ([]byte, error) {
+	var base strings.Builder
+
+	for _, component := range components {
+		componentValue, err := value(component)
+		if err != nil {
+			return nil, err
+		}
+
+		base.WriteString(strconv.Quote(component) + ": " + componentValue + "\n")
+	}
+
+	base.WriteString(`"@signature-params": ` + params)
+
+	return []byte(base.String()), nil
+}
+
+func requestComponent(request *http.Request, component string) (string, error) {
+	switch component {
+	case "@method":
+		return request.Method, nil
+	case "@target-uri":
+		return request.URL.String(), nil
+	case "@authority":
+		host := request.Host
+		if host == "" {
+			host = request.URL.Host
+		}
+
+		return strings.ToLower(host), nil
+	case "@scheme":
+		return strings.ToLower(request.URL.Scheme), nil
+	case "@request-target":
+		return request.URL.RequestURI(), nil
+	case "@path":
+		if path := request.URL.EscapedPath(); path != "" {
+			return path, nil
+		}
+
+		return "/", nil
+	case "@query":
+		return "?" + request.URL.RawQuery, nil
+	default:
+		return headerComponent(request.Header, component)
+	}
+}
+
+func headerComponent(header http.Header, component string) (string, error) {
+	if strings.HasPrefix(component, "@") {
+		return "", fmt.Errorf("%w: unsupported component %s", ErrInvalidSignature, component)
+	}
+
+	values := header.Values(component)
+	if len(values) == 0 {
+		return "", fmt.Errorf("%w: missing header %s", ErrInvalidSignature, component)
+	}
+
+	trimmed := make([]string, 0, len(values))
+	for _, value := range values {
+		trimmed = append(trimmed, strings.TrimSpace(value))
+	}
+
+	return strings.Join(trimmed, ", "), nil
+}
+
+// VerifyRequestSignature checks the signature labelled label on an incoming
+// request, e.g. a federation callback. The signature must cover every
+// component in required and, when maxAge is positive, have been created at
+// most maxAge ago.
+func VerifyRequestSignature(
+	request *http.Request,
+	label string,
+	verifier MessageVerifier,
+	required []string,
+	maxAge time.Duration,
+) error {
+	return verifySignature(request.Header, label, verifier, required, maxAge, func(component string) (string, error) {
+		return requestComponent(request, component)
+	})
+}
+
+// VerifyResponseSignature checks the signature labelled label on a
+// response like VerifyRequestSignature; "@status" and header components are
+// supported.
+func VerifyResponseSignature(
+	response *Response,
+	label string,
+	verifier MessageVerifier,
+	required []string,
+	maxAge time.Duration,
+) error {
+	return verifySignature(response.Header, label, verifier, required, maxAge, func(component string) (string, error) {
+		if component == "@status" {
+			return strconv.Itoa(response.StatusCode), nil
+		}
+
+		return headerComponent(response.Header, component)
+	})
+}
+
+func verifySignature(
+	header http.Header,
+	label string,
+	verifier MessageVerifier,
+	required []string,
+	maxAge time.Duration,
+	value func(string) (string, error),
+) error {
+	params, ok := dictionaryMember(header.Get(signatureInputHeader), label)
+	if !ok {
+		return fmt.Errorf("%w: no signature input %s", ErrInvalidSignature, label)
+	}
+
+	encoded, ok := dictionaryMember(header.Get(signatureHeader), label)
+	if !ok || len(encoded) < 2 || encoded[0] != ':' || encoded[len(encoded)-1] != ':' {
+		return fmt.Errorf("%w: no signature %s", ErrInvalidSignature, label)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(encoded[1 : len(encoded)-1])
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	parsed, err := parseSignatureParams(params)
+	if err != nil {
+		return err
+	}
+
+	// The covered components come from the sender, so a signature over
+	// less than the caller needs must not pass.
+	for _, component := range required {
+		if !slices.Contains(parsed.components, component) {
+			return fmt.Errorf("%w: %s is not covered", ErrInvalidSignature, component)
+		}
+	}
+
+	if err = parsed.checkTime(time.Now(), maxAge); err != nil {
+		return err
+	}
+
+	base, err := signatureBase(parsed.components, params, value)
+	if err != nil {
+		return err
+	}
+
+	return verifier.Verify(base, signature)
+}
+
+// dictionaryMember returns the raw value of key in a structured field
+// dictionary such as `sig1=("@method");created=1, sig2=...`.
+func dictionaryMember(field, key string) (string, bool) {
+	depth, quoted, start := 0, false, 0
+
+	for i := 0; i <= len(field); i++ {
+		if i < len(field) {
+			switch c := field[i]; {
+			case c == '\\' && quoted:
+				i++
+				continue
+			case c == '"':
+				quoted = !quoted
+				continue
+			case quoted:
+				continue
+			case c == '(':
+				depth++
+				continue
+			case c == ')':
+				depth--
+				continue
+			case c != ',' || depth > 0:
+				continue
+			}
+		}
+
+		name, member, found := strings.Cut(strings.TrimSpace(field[start:i]), "=")
+		if found && name == key {
+			return member, true
+		}
+
+		start = i + 1
+	}
+
+	return "", false
+}
+
+type signatureParams struct {
+	components []string
+	created    int64
+	expires    int64
+}
+
+func parseSignatureParams(params string) (signatureParams, error) {
+	var parsed signatureParams
+
+	end := strings.IndexByte(params, ')')
+	if !strings.HasPrefix(params, "(") || end < 0 {
+		return parsed, fmt.Errorf("%w: malformed signature input", ErrInvalidSignature)
+	}
+
+	for _, item := range strings.Fields(params[1:end]) {
+		component, err := strconv.Unquote(item)
+		if err != nil {
+			return parsed, fmt.Errorf("%w: malformed component %s", ErrInvalidSignature, item)
+		}
+
+		parsed.components = append(parsed.components, component)
+	}
+
+	for _, param := range strings.Split(params[end+1:], ";") {
+		if value, ok := strings.CutPrefix(param, "created="); ok {
+			parsed.created, _ = strconv.ParseInt(value, 10, 64)
+		}
+
+		if value, ok := strings.CutPrefix(param, "expires="); ok {
+			parsed.expires, _ = strconv.ParseInt(value, 10, 64)
+		}
+	}
+
+	return parsed, nil
+}
+
+func (parsed signatureParams) checkTime(now time.Time, maxAge time.Duration) error {
+	if parsed.expires > 0 && now.Unix() > parsed.expires {
+		return ErrSignatureExpired
+	}
+
+	if maxAge <= 0 {
+		return nil
+	}
+
+	if parsed.created <= 0 {
+		return fmt.Errorf("%w: no created parameter", ErrInvalidSignature)
+	}
+
+	created := time.Unix(parsed.created, 0)
+
+	if created.After(now.Add(signatureClockSkew)) {
+		return fmt.Errorf("%w: created in the future", ErrInvalidSignature)
+	}
+
+	if now.Sub(created) > maxAge {
+		return ErrSignatureExpired
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestMessageSignatures_SignedRequestVerifies(t *testing.T) {
+	key := []byte("shared-secret")
+	var verifyErr error
+	var input string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		input = r.Header.Get("Signature-Input")
+		verifyErr = VerifyRequestSignature(r, "sig1", HMACSigner(key), []string{"@method", "@path", "content-digest"}, time.Minute)
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua",
+		WithBodyDigest(BodyDigest{ContentDigest: []DigestAlgorithm{DigestSHA256}}),
+		WithMessageSignatures(MessageSignatures{
+			Signer:     HMACSigner(key),
+			KeyID:      "test-key",
+			Components: []string{"@method", "@authority", "@path", "@query", "content-digest"},
+			Expires:    time.Minute,
+		}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	_, err = c.Send(context.Background(), RequestSpec{Method: http.MethodPost, Path: "/pay", Params: MultiParams{"a": {"1"}}},
+		JSONBody(map[string]int{"amount": 5}))
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if verifyErr != nil {
+		t.Fatalf("verify error: %v (input %s)", verifyErr, input)
+	}
+	if !strings.Contains(input, `keyid="test-key"`) || !strings.Contains(input, `alg="hmac-sha256"`) {
+		t.Fatalf("input=%s", input)
+	}
+}
+
+func TestMessageSignatures_SignatureBase(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodPost, "https://Example.com/foo?param=Value&Pet=dog", nil)
+	request.Header.Set("Content-Type", "application/json")
+
+	base, err := signatureBase([]string{"@method", "@authority", "@path", "@query", "content-type"}, `("@method");created=1`,
+		func(component string) (string, error) { return requestComponent(request, component) })
+	if err != nil {
+		t.Fatalf("signatureBase error: %v", err)
+	}
+
+	want := `"@method": POST
+"@authority": example.com
+"@path": /foo
+"@query": ?param=Value&Pet=dog
+"content-type": application/json
+"@signature-params": ("@method");created=1`
+	if string(base) != want {
+		t.Fatalf("base:\n%s", base)
+	}
+}
+
+func TestVerifyResponseSignature_Ed25519(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(nil)
+	config := &MessageSignatures{Signer: Ed25519Signer(private), Label: "resp"}
+	components := []string{"@status", "content-type"}
+
+	response := &Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": {"application/json"}}}
+	params := serializeSignatureParams(components, time.Now(), "", config)
+	base, _ := signatureBase(components, params, func(component string) (string, error) {
+		if component == "@status" {
+			return "200", nil
+		}
+		return headerComponent(response.Header, component)
+	})
+	signature, _ := config.Signer.Sign(base)
+	response.Header.Set("Signature-Input", "other=(\"@status\");created=1, resp="+params)
+	response.Header.Set("Signature", "resp=:"+base64.StdEncoding.EncodeToString(signature)+":")
+
+	required := []string{"@status"}
+	if err := VerifyResponseSignature(response, "resp", Ed25519Verifier(public), required, time.Minute); err != nil {
+		t.Fatalf("verify error: %v", err)
+	}
+
+	response.StatusCode = http.StatusCreated
+	if err := VerifyResponseSignature(response, "resp", Ed25519Verifier(public), required, time.Minute); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestVerifyRequestSignature_RequiresComponentsAndFreshness(t *testing.T) {
+	key := HMACSigner([]byte("shared-secret"))
+	config := &MessageSignatures{Signer: key, Label: "sig1"}
+
+	sign := func(components []string, created time.Time) *http.Request {
+		request, _ := http.NewRequest(http.MethodPost, "https://example.com/pay?amount=5", nil)
+		params := serializeSignatureParams(components, created, "", config)
+		base, _ := signatureBase(components, params, func(component string) (string, error) {
+			return requestComponent(request, component)
+		})
+		signature, _ := key.Sign(base)
+		request.Header.Set("Signature-Input", "sig1="+params)
+		request.Header.Set("Signature", "sig1=:"+base64.StdEncoding.EncodeToString(signature)+":")
+		return request
+	}
+
+	required := []string{"@method", "@path", "@query"}
+
+	cases := []struct {
+		name    string
+		request *http.Request
+		want    error
+	}{
+		{"covers all", sign(required, time.Now()), nil},
+		{"covers too little", sign([]string{"@method"}, time.Now()), ErrInvalidSignature},
+		{"too old", sign(required, time.Now().Add(-time.Hour)), ErrSignatureExpired},
+		{"from the future", sign(required, time.Now().Add(time.Hour)), ErrInvalidSignature},
+	}
+
+	for _, tc := range cases {
+		err := VerifyRequestSignature(tc.request, "sig1", key, required, 5*time.Minute)
+		if !errors.Is(err, tc.want) || (tc.want == nil && err != nil) {
+			t.Errorf("%s: err=%v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"time"
+)
+
+const defaultSimpleTimeout = 30 * time.Second
+
+// SimpleClient wraps Client for scripts and CLIs: every call runs with its own
+// default timeout and no params or per-request headers. Use Client.Send when
+// more control is needed.
+type SimpleClient struct {
+	Client  *Client
+	Timeout time.Duration
+}
+
+func NewSimple(baseUrl string, opts ...Option) (*SimpleClient, error) {
+	client, err := New(baseUrl, nil, nil, true, "", opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Simple(), nil
+}
+
+func (client *Client) Simple() *SimpleClient {
+	return &SimpleClient{Client: client, Timeout: defaultSimpleTimeout}
+}
+
+func (simple *SimpleClient) Get(path string) ([]byte, error) {
+	return simple.do(http.MethodGet, path, nil, nil)
+}
+
+func (simple *SimpleClient) Post(path string, body []byte) ([]byte, error) {
+	return simple.do(http.MethodPost, path, body, nil)
+}
+
+func (simple *SimpleClient) Put(path string, body []byte) ([]byte, error) {
+	return simple.do(http.MethodPut, path, body, nil)
+}
+
+func (simple *SimpleClient) Patch(path string, body []byte) ([]byte, error) {
+	return simple.do(http.MethodPatch, path, body, nil)
+}
+
+func (simple *SimpleClient) Delete(path string) ([]byte, error) {
+	return simple.do(http.MethodDelete, path, nil, nil)
+}
+
+func (simple *SimpleClient) GetJSON(path string, out any) error {
+	return simple.doJSON(http.MethodGet, path, nil, out)
+}
+
+func (simple *SimpleClient) PostJSON(path string, in, out any) error {
+	return simple.doJSON(http.MethodPost, path, in, out)
+}
+
+func (simple *SimpleClient) PutJSON(path string, in, out any) error {
+	return simple.doJSON(http.MethodPut, path, in, out)
+}
+
+func (simple *SimpleClient) PatchJSON(path string, in, out any) error {
+	return simple.doJSON(http.MethodPatch, path, in, out)
+}
+
+func (simple *SimpleClient) doJSON(method, path string, in, out any) error {
+	var body []byte
+
+	if in != nil {
+		encoded, err := simple.Client.json().Marshal(in)
+		if err != nil {
+			return err
+		}
+
+		body = encoded
+	}
+
+	headers := MultiHeaders{}
+	headers.Set("Accept", ContentTypeJson)
+
+	if body != nil {
+		headers.Set(ContentTypeHeader, ContentTypeJson)
+	}
+
+	data, err := simple.do(method, path, body, headers)
+	if err != nil || out == nil || len(data) == 0 {
+		return err
+	}
+
+	return simple.Client.unmarshalJSON(data, out, simple.Client.decodeOptions)
+}
+
+func (simple *SimpleClient) do(method, path string, body []byte, headers MultiHeaders) ([]byte, error) {
+	ctx := context.Background()
+
+	if simple.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, simple.Timeout)
+		defer cancel()
+	}
+
+	spec := RequestSpec{Method: method, Path: path}
+
+	if body != nil {
+		spec.Body = bytes.NewReader(body)
+	}
+
+	response, err := simple.Client.Send(ctx, spec, withHeaders(headers))
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Body, nil
+}
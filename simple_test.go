@@ -0,0 +1,60 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSimpleClient_JSONRoundTrip(t *testing.T) {
+	var gotContentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get(ContentTypeHeader)
+		var in map[string]int
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		in["n"]++
+		_ = json.NewEncoder(w).Encode(in)
+	}))
+	defer srv.Close()
+
+	simple, err := NewSimple(srv.URL)
+	if err != nil {
+		t.Fatalf("NewSimple error: %v", err)
+	}
+
+	var out map[string]int
+	if err = simple.PostJSON("/inc", map[string]int{"n": 1}, &out); err != nil {
+		t.Fatalf("PostJSON error: %v", err)
+	}
+	if out["n"] != 2 || gotContentType != ContentTypeJson {
+		t.Fatalf("out=%v content-type=%s", out, gotContentType)
+	}
+}
+
+func TestSimpleClient_DefaultTimeoutAndErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(300 * time.Millisecond)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	simple, err := NewSimple(srv.URL)
+	if err != nil {
+		t.Fatalf("NewSimple error: %v", err)
+	}
+
+	if _, err = simple.Get("/missing"); !errors.Is(err, ErrRequestFailed) {
+		t.Fatalf("expected ErrRequestFailed, got %v", err)
+	}
+
+	simple.Timeout = 50 * time.Millisecond
+	if _, err = simple.Get("/slow"); err == nil {
+		t.Fatal("expected timeout error")
+	}
+}
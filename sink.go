@@ -0,0 +1,83 @@
+package client
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/rs/zerolog"
+)
+
+// Sink copies a successful response body to w instead of buffering it in
+// Response.Body. Error responses are still read into Response.Body so the
+// usual status handling applies; caching and coalescing are skipped.
+func Sink(w io.Writer) RequestOption {
+	return func(options *requestOptions) {
+		options.sink = w
+	}
+}
+
+// TeeResponse copies a successful response body to every writer in one pass
+// while keeping its first maxBody bytes in Response.Body; BodyTruncated
+// reports whether anything was cut off. Otherwise it behaves like Sink.
+func TeeResponse(maxBody int64, writers ...io.Writer) RequestOption {
+	return func(options *requestOptions) {
+		options.sink = io.MultiWriter(writers...)
+		options.keepBody = maxBody
+	}
+}
+
+type cappedBuffer struct {
+	data      []byte
+	limit     int64
+	truncated bool
+}
+
+func (buffer *cappedBuffer) Write(p []byte) (int, error) {
+	room := buffer.limit - int64(len(buffer.data))
+
+	if int64(len(p)) > room {
+		buffer.data = append(buffer.data, p[:room]...)
+		buffer.truncated = true
+	} else {
+		buffer.data = append(buffer.data, p...)
+	}
+
+	return len(p), nil
+}
+
+func streamResponse(
+	response *http.Response,
+	sink io.Writer,
+	keepBody int64,
+	logger *zerolog.Logger,
+) (*Response, error) {
+	defer func() {
+		if err := closeResponseBody(response); err != nil {
+			logger.Warn().
+				Err(err).
+				Msg("failed to close response body")
+		}
+	}()
+
+	result := &Response{
+		StatusCode: response.StatusCode,
+		Header:     response.Header,
+	}
+
+	var kept *cappedBuffer
+
+	if keepBody > 0 {
+		kept = &cappedBuffer{limit: keepBody}
+		sink = io.MultiWriter(sink, kept)
+	}
+
+	written, err := io.Copy(sink, response.Body)
+	result.BytesWritten = written
+
+	if kept != nil {
+		result.Body = kept.data
+		result.BodyTruncated = kept.truncated
+	}
+
+	return result, err
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSink_StreamsSuccessfulBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("not found"))
+			return
+		}
+		_, _ = w.Write([]byte("payload"))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	var buf bytes.Buffer
+	resp, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/file"}, Sink(&buf))
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if buf.String() != "payload" || resp.BytesWritten != int64(len("payload")) || len(resp.Body) != 0 {
+		t.Fatalf("sink=%q written=%d body=%q", buf.String(), resp.BytesWritten, resp.Body)
+	}
+
+	buf.Reset()
+	resp, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/missing"}, Sink(&buf))
+	if !errors.Is(err, ErrRequestFailed) {
+		t.Fatalf("expected ErrRequestFailed, got %v", err)
+	}
+	if buf.Len() != 0 || string(resp.Body) != "not found" {
+		t.Fatalf("sink=%q body=%q", buf.String(), resp.Body)
+	}
+}
+
+func TestTeeResponse_FeedsAllWritersAndCapsBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("0123456789"))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	var file bytes.Buffer
+	hash := sha256.New()
+	resp, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}, TeeResponse(4, &file, hash))
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	want := sha256.Sum256([]byte("0123456789"))
+	if file.String() != "0123456789" || !bytes.Equal(hash.Sum(nil), want[:]) {
+		t.Fatalf("file=%q", file.String())
+	}
+	if string(resp.Body) != "0123" || !resp.BodyTruncated || resp.BytesWritten != 10 {
+		t.Fatalf("body=%q truncated=%v written=%d", resp.Body, resp.BodyTruncated, resp.BytesWritten)
+	}
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type requestTimings struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	gotConn      time.Time
+	firstByte    time.Time
+}
+
+type successSampler struct {
+	every   int
+	counter atomic.Uint64
+}
+
+// WithSlowRequestThreshold logs successful requests that took longer than
+// threshold to receive response headers at warn level, with a breakdown of
+// dns, connect, tls and time to first byte. Faster requests are logged at
+// debug level instead of info.
+func WithSlowRequestThreshold(threshold time.Duration) Option {
+	return func(client *Client) error {
+		client.slowThreshold = threshold
+
+		return nil
+	}
+}
+
+// WithSuccessLogSampling logs one in every successful requests that are not
+// slow. A non-positive value drops them from the log entirely.
+func WithSuccessLogSampling(every int) Option {
+	return func(client *Client) error {
+		client.successSampler = &successSampler{every: every}
+
+		return nil
+	}
+}
+
+func (client *Client) logSuccess(request *http.Request, response *http.Response, timings *requestTimings) {
+	elapsed := time.Since(timings.start)
+
+	if client.slowThreshold > 0 && elapsed > client.slowThreshold {
+		client.logger.Warn().
+			Str(client.logField("method"), request.Method).
+			Func(client.logURL(request.URL.String())).
+			Int(client.logField("status"), response.StatusCode).
+			Dur("threshold", client.slowThreshold).
+			Dict("timings", timings.dict(elapsed)).
+			Msg("slow http request")
+
+		return
+	}
+
+	if !client.successSampler.sample() {
+		return
+	}
+
+	event := client.logger.Info()
+	if client.slowThreshold > 0 {
+		event = client.logger.Debug()
+	}
+
+	event.
+		Str(client.logField("method"), request.Method).
+		Func(client.logURL(request.URL.String())).
+		Int(client.logField("status"), response.StatusCode).
+		Msg("http request succeeded")
+}
+
+func (sampler *successSampler) sample() bool {
+	if sampler == nil {
+		return true
+	}
+
+	if sampler.every <= 0 {
+		return false
+	}
+
+	return (sampler.counter.Add(1)-1)%uint64(sampler.every) == 0
+}
+
+func (timings *requestTimings) mark(at *time.Time) {
+	timings.mu.Lock()
+	defer timings.mu.Unlock()
+
+	if at.IsZero() {
+		*at = time.Now()
+	}
+}
+
+func (timings *requestTimings) dict(total time.Duration) *zerolog.Event {
+	timings.mu.Lock()
+	defer timings.mu.Unlock()
+
+	dict := zerolog.Dict()
+
+	if !timings.dnsDone.IsZero() {
+		dict.Dur("dns", timings.dnsDone.Sub(timings.dnsStart))
+	}
+
+	if !timings.connectDone.IsZero() {
+		dict.Dur("connect", timings.connectDone.Sub(timings.connectStart))
+	}
+
+	if !timings.tlsDone.IsZero() {
+		dict.Dur("tls", timings.tlsDone.Sub(timings.tlsStart))
+	}
+
+	if !timings.firstByte.IsZero() {
+		dict.Dur("ttfb", timings.firstByte.Sub(timings.start))
+	}
+
+	return dict.Dur("total", total)
+}
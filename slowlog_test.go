@@ -0,0 +1,88 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestSlowRequestThreshold_WarnsWithTimings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(50 * time.Millisecond)
+		}
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	log := zerolog.New(&buf)
+	c, err := New(srv.URL, nil, &log, false, "ua", WithSlowRequestThreshold(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/fast"}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/slow"}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("lines=%d: %s", len(lines), buf.String())
+	}
+
+	var fast, slow struct {
+		Level   string             `json:"level"`
+		Message string             `json:"message"`
+		Timings map[string]float64 `json:"timings"`
+	}
+	if err = json.Unmarshal([]byte(lines[0]), &fast); err != nil {
+		t.Fatalf("bad log line: %v", err)
+	}
+	if err = json.Unmarshal([]byte(lines[1]), &slow); err != nil {
+		t.Fatalf("bad log line: %v", err)
+	}
+	if fast.Level != "debug" {
+		t.Fatalf("fast=%+v", fast)
+	}
+	if slow.Level != "warn" || slow.Message != "slow http request" {
+		t.Fatalf("slow=%+v", slow)
+	}
+	if _, ok := slow.Timings["ttfb"]; !ok {
+		t.Fatalf("timings=%v", slow.Timings)
+	}
+	if _, ok := slow.Timings["total"]; !ok {
+		t.Fatalf("timings=%v", slow.Timings)
+	}
+}
+
+func TestSuccessLogSampling(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	log := zerolog.New(&buf)
+	c, err := New(srv.URL, nil, &log, false, "ua", WithSuccessLogSampling(3))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	for i := 0; i < 6; i++ {
+		if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+	}
+
+	if n := strings.Count(buf.String(), "http request succeeded"); n != 2 {
+		t.Fatalf("logged %d successes", n)
+	}
+}
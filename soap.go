@@ -0,0 +1,197 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type SOAPVersion int
+
+const (
+	SOAP11 SOAPVersion = iota
+	SOAP12
+)
+
+const (
+	soap11Namespace   = "http://schemas.xmlsoap.org/soap/envelope/"
+	soap12Namespace   = "http://www.w3.org/2003/05/soap-envelope"
+	soap11ContentType = "text/xml; charset=utf-8"
+	soap12ContentType = "application/soap+xml; charset=utf-8"
+	soapActionHeader  = "SOAPAction"
+)
+
+var ErrInvalidSOAPResponse = errors.New("invalid SOAP response")
+
+// SOAPCall describes one SOAP operation. Body and Header are marshaled
+// with encoding/xml and placed inside the envelope's Body and Header.
+type SOAPCall struct {
+	Path    string
+	Action  string
+	Version SOAPVersion
+	Header  any
+	Body    any
+	Headers MultiHeaders
+}
+
+// SOAPFault is returned when the service answers with a SOAP Fault.
+type SOAPFault struct {
+	StatusCode int
+	Code       string
+	Reason     string
+	Actor      string
+	Detail     []byte
+}
+
+func (fault *SOAPFault) Error() string {
+	return fmt.Sprintf("soap fault %s: %s", fault.Code, fault.Reason)
+}
+
+type soapEnvelope struct {
+	Header soapInner `xml:"Header"`
+	Body   soapInner `xml:"Body"`
+}
+
+type soapInner struct {
+	Inner []byte `xml:",innerxml"`
+}
+
+type soapFaultXML struct {
+	Code11   string    `xml:"faultcode"`
+	String11 string    `xml:"faultstring"`
+	Actor11  string    `xml:"faultactor"`
+	Detail11 soapInner `xml:"detail"`
+	Code12   string    `xml:"Code>Value"`
+	Reason12 string    `xml:"Reason>Text"`
+	Role12   string    `xml:"Role"`
+	Detail12 soapInner `xml:"Detail"`
+}
+
+// CallSOAP posts the envelope for call through Send and decodes the content
+// of the response Body element into out when out is not nil.
+func (client *Client) CallSOAP(ctx context.Context, call SOAPCall, out any) (*Response, error) {
+	payload, err := call.envelope()
+	if err != nil {
+		return nil, err
+	}
+
+	headers := MultiHeaders{}
+
+	if call.Version == SOAP12 {
+		contentType := soap12ContentType
+		if call.Action != "" {
+			contentType += fmt.Sprintf("; action=%q", call.Action)
+		}
+
+		headers.Set(ContentTypeHeader, contentType)
+	} else {
+		headers.Set(ContentTypeHeader, soap11ContentType)
+		headers.Set(soapActionHeader, fmt.Sprintf("%q", call.Action))
+	}
+
+	response, err := client.Send(ctx, RequestSpec{
+		Method:  http.MethodPost,
+		Path:    call.Path,
+		Headers: call.Headers,
+		Body:    bytes.NewReader(payload),
+	}, withHeaders(headers))
+	if response == nil {
+		return nil, err
+	}
+
+	body, fault := parseSOAPResponse(response)
+	if fault != nil {
+		return response, fault
+	}
+
+	if err != nil {
+		return response, err
+	}
+
+	if body == nil {
+		return response, ErrInvalidSOAPResponse
+	}
+
+	if out != nil {
+		if err = xml.Unmarshal(body, out); err != nil {
+			return response, fmt.Errorf("%w: %w", ErrInvalidSOAPResponse, err)
+		}
+	}
+
+	return response, nil
+}
+
+func (call SOAPCall) envelope() ([]byte, error) {
+	namespace := soap11Namespace
+	if call.Version == SOAP12 {
+		namespace = soap12Namespace
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<soap:Envelope xmlns:soap="` + namespace + `">`)
+
+	if call.Header != nil {
+		header, err := xml.Marshal(call.Header)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.WriteString("<soap:Header>")
+		buf.Write(header)
+		buf.WriteString("</soap:Header>")
+	}
+
+	buf.WriteString("<soap:Body>")
+
+	if call.Body != nil {
+		body, err := xml.Marshal(call.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(body)
+	}
+
+	buf.WriteString("</soap:Body></soap:Envelope>")
+
+	return buf.Bytes(), nil
+}
+
+func parseSOAPResponse(response *Response) ([]byte, *SOAPFault) {
+	var envelope soapEnvelope
+
+	if err := xml.Unmarshal(response.Body, &envelope); err != nil {
+		return nil, nil
+	}
+
+	body := bytes.TrimSpace(envelope.Body.Inner)
+
+	var wrapper struct {
+		XMLName xml.Name
+		soapFaultXML
+	}
+
+	if xml.Unmarshal(body, &wrapper) != nil || wrapper.XMLName.Local != "Fault" {
+		return body, nil
+	}
+
+	fault := &SOAPFault{
+		StatusCode: response.StatusCode,
+		Code:       strings.TrimSpace(wrapper.Code11 + wrapper.Code12),
+		Reason:     strings.TrimSpace(wrapper.String11 + wrapper.Reason12),
+		Actor:      strings.TrimSpace(wrapper.Actor11 + wrapper.Role12),
+		Detail:     bytes.TrimSpace(wrapper.Detail11.Inner),
+	}
+
+	if len(fault.Detail) == 0 {
+		fault.Detail = bytes.TrimSpace(wrapper.Detail12.Inner)
+	}
+
+	return body, fault
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type getPrice struct {
+	XMLName xml.Name `xml:"urn:shop GetPrice"`
+	Item    string   `xml:"Item"`
+}
+
+type getPriceResponse struct {
+	Price string `xml:"Price"`
+}
+
+func TestCallSOAP_DecodesBody(t *testing.T) {
+	var gotAction, gotType, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAction = r.Header.Get("SOAPAction")
+		gotType = r.Header.Get(ContentTypeHeader)
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		_, _ = w.Write([]byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>` +
+			`<GetPriceResponse><Price>9.99</Price></GetPriceResponse></soap:Body></soap:Envelope>`))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	var out getPriceResponse
+	_, err := c.CallSOAP(context.Background(), SOAPCall{Path: "/ws", Action: "urn:GetPrice", Body: getPrice{Item: "apple"}}, &out)
+	if err != nil {
+		t.Fatalf("CallSOAP error: %v", err)
+	}
+	if out.Price != "9.99" {
+		t.Errorf("price=%q", out.Price)
+	}
+	if gotAction != `"urn:GetPrice"` || !strings.HasPrefix(gotType, "text/xml") {
+		t.Errorf("action=%q type=%q", gotAction, gotType)
+	}
+	if !strings.Contains(gotBody, "<soap:Body><GetPrice xmlns=\"urn:shop\"><Item>apple</Item></GetPrice></soap:Body>") {
+		t.Errorf("unexpected envelope: %s", gotBody)
+	}
+}
+
+func TestCallSOAP_Fault12(t *testing.T) {
+	var gotType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotType = r.Header.Get(ContentTypeHeader)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope"><env:Body><env:Fault>` +
+			`<env:Code><env:Value>env:Sender</env:Value></env:Code><env:Reason><env:Text>bad item</env:Text></env:Reason>` +
+			`</env:Fault></env:Body></env:Envelope>`))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	_, err := c.CallSOAP(context.Background(), SOAPCall{Path: "/ws", Action: "urn:GetPrice", Version: SOAP12}, nil)
+
+	var fault *SOAPFault
+	if !errors.As(err, &fault) {
+		t.Fatalf("expected SOAPFault, got %v", err)
+	}
+	if fault.Code != "env:Sender" || fault.Reason != "bad item" || fault.StatusCode != http.StatusInternalServerError {
+		t.Errorf("fault: %+v", fault)
+	}
+	if gotType != `application/soap+xml; charset=utf-8; action="urn:GetPrice"` {
+		t.Errorf("content-type=%q", gotType)
+	}
+}
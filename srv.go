@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	srvScheme      = "srv"
+	srvHTTPSScheme = "srv+https"
+
+	defaultSRVRefreshInterval = 30 * time.Second
+)
+
+var errNoSRVTargets = errors.New("srv lookup returned no targets")
+
+type srvConfig struct {
+	lookup   func(ctx context.Context, name string) ([]*net.SRV, error)
+	interval time.Duration
+}
+
+// WithSRVResolver replaces the DNS lookup used for "srv://" base URLs.
+func WithSRVResolver(lookup func(ctx context.Context, name string) ([]*net.SRV, error)) Option {
+	return func(client *Client) error {
+		client.srv.lookup = lookup
+
+		return nil
+	}
+}
+
+// WithSRVRefreshInterval sets how often SRV records are looked up again.
+// A non-positive interval keeps the targets resolved at construction.
+func WithSRVRefreshInterval(interval time.Duration) Option {
+	return func(client *Client) error {
+		client.srv.interval = interval
+
+		return nil
+	}
+}
+
+func isSRVUrl(baseUrl string) bool {
+	return strings.HasPrefix(baseUrl, srvScheme+"://") || strings.HasPrefix(baseUrl, srvHTTPSScheme+"://")
+}
+
+// resolveSRVEndpoints handles base URLs like "srv://_api._tcp.payments.internal/v1":
+// requests go to the SRV targets over http ("srv+https://" for https), keeping
+// the path of the base URL.
+func (client *Client) resolveSRVEndpoints(baseUrl string) error {
+	u, err := url.Parse(baseUrl)
+	if err != nil {
+		return err
+	}
+
+	scheme := "http"
+	if u.Scheme == srvHTTPSScheme {
+		scheme = "https"
+	}
+
+	lookup := client.srv.lookup
+	if lookup == nil {
+		lookup = func(ctx context.Context, name string) ([]*net.SRV, error) {
+			_, records, err := net.DefaultResolver.LookupSRV(ctx, "", "", name)
+			return records, err
+		}
+	}
+
+	interval := client.srv.interval
+	if interval == 0 {
+		interval = defaultSRVRefreshInterval
+	}
+
+	pool := &endpointPool{
+		interval: interval,
+		resolve: func(ctx context.Context) ([]endpoint, error) {
+			records, err := lookup(ctx, u.Host)
+			if err != nil {
+				return nil, err
+			}
+
+			return srvEndpoints(scheme, u.Path, records)
+		},
+		onError: func(err error) {
+			client.logger.Warn().
+				Err(err).
+				Str(client.logField("url"), baseUrl).
+				Msg("failed to refresh srv endpoints")
+		},
+		outlier: client.outlier,
+		logger:  client.logger,
+	}
+
+	endpoints, err := pool.resolve(context.Background())
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", baseUrl, err)
+	}
+
+	pool.set(endpoints)
+	client.endpoints = pool
+
+	return nil
+}
+
+func srvEndpoints(scheme, path string, records []*net.SRV) ([]endpoint, error) {
+	endpoints := make([]endpoint, 0, len(records))
+
+	for _, record := range records {
+		host := strings.TrimSuffix(record.Target, ".")
+		if host == "" {
+			continue
+		}
+
+		endpoints = append(endpoints, endpoint{
+			url:      scheme + "://" + net.JoinHostPort(host, strconv.Itoa(int(record.Port))) + path,
+			priority: int(record.Priority),
+			weight:   int(record.Weight),
+		})
+	}
+
+	if len(endpoints) == 0 {
+		return nil, errNoSRVTargets
+	}
+
+	return endpoints, nil
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func srvRecord(t *testing.T, srv *httptest.Server, priority, weight uint16) *net.SRV {
+	t.Helper()
+
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatalf("bad server url: %v", err)
+	}
+	p, _ := strconv.Atoi(port)
+
+	return &net.SRV{Target: host + ".", Port: uint16(p), Priority: priority, Weight: weight}
+}
+
+func TestSRVBaseURL_BalancesAcrossTargets(t *testing.T) {
+	var hitsA, hitsB int32
+	var gotPath string
+	var mu sync.Mutex
+	a := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hitsA, 1)
+		mu.Lock()
+		gotPath = r.URL.Path
+		mu.Unlock()
+	}))
+	defer a.Close()
+	b := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hitsB, 1)
+	}))
+	defer b.Close()
+
+	var lookedUp string
+	log := zerolog.Nop()
+	c, err := New("srv://_api._tcp.payments.internal/v1", nil, &log, false, "ua",
+		WithSRVResolver(func(_ context.Context, name string) ([]*net.SRV, error) {
+			lookedUp = name
+			return []*net.SRV{srvRecord(t, a, 1, 50), srvRecord(t, b, 1, 50)}, nil
+		}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	if lookedUp != "_api._tcp.payments.internal" {
+		t.Fatalf("looked up %q", lookedUp)
+	}
+
+	for i := 0; i < 40; i++ {
+		if _, _, err = c.SendGet("/items", nil, nil); err != nil {
+			t.Fatalf("SendGet error: %v", err)
+		}
+	}
+	if atomic.LoadInt32(&hitsA) == 0 || atomic.LoadInt32(&hitsB) == 0 {
+		t.Fatalf("hits a=%d b=%d", hitsA, hitsB)
+	}
+	if gotPath != "/v1/items" {
+		t.Fatalf("path=%s", gotPath)
+	}
+}
+
+func TestSRVBaseURL_PriorityAndRefresh(t *testing.T) {
+	var hitsA, hitsB int32
+	a := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hitsA, 1)
+	}))
+	defer a.Close()
+	b := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hitsB, 1)
+	}))
+	defer b.Close()
+
+	var lookups int32
+	log := zerolog.Nop()
+	c, err := New("srv://_api._tcp.internal", nil, &log, false, "ua",
+		WithSRVRefreshInterval(10*time.Millisecond),
+		WithSRVResolver(func(context.Context, string) ([]*net.SRV, error) {
+			if atomic.AddInt32(&lookups, 1) == 1 {
+				return []*net.SRV{srvRecord(t, a, 1, 0), srvRecord(t, b, 2, 100)}, nil
+			}
+			return []*net.SRV{srvRecord(t, b, 1, 0)}, nil
+		}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	if _, _, err = c.SendGet("/x", nil, nil); err != nil || atomic.LoadInt32(&hitsA) != 1 {
+		t.Fatalf("priority target not used: a=%d err=%v", hitsA, err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&hitsB) == 0 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+		if _, _, err = c.SendGet("/x", nil, nil); err != nil {
+			t.Fatalf("SendGet error: %v", err)
+		}
+	}
+	if atomic.LoadInt32(&hitsB) == 0 {
+		t.Fatal("refreshed targets were never used")
+	}
+}
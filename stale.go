@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"syscall"
+)
+
+// WithStaleConnectionRetry retries an idempotent request once when it fails
+// because the server closed a kept-alive connection under it. Only attempts
+// that got no response at all are replayed; a body cut off midway, possibly
+// already copied to a Sink, is returned as an error.
+func WithStaleConnectionRetry() Option {
+	return func(client *Client) error {
+		client.retryStaleConnections = true
+
+		return nil
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func isStaleConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		strings.Contains(err.Error(), "server closed idle connection")
+}
+
+func (client *Client) exchangeWithStaleRetry(
+	ctx context.Context,
+	spec *RequestSpec,
+	options *requestOptions,
+	cacheKey string,
+) (*Response, error) {
+	if !client.retryStaleConnections || !isIdempotent(spec.Method) {
+		return client.exchange(ctx, spec, options, cacheKey)
+	}
+
+	rewind, err := rewindSpecBody(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	options.staleConnection = false
+
+	result, err := client.exchange(ctx, spec, options, cacheKey)
+	if !options.staleConnection || !isStaleConnectionError(err) || ctx.Err() != nil {
+		return result, err
+	}
+
+	client.logger.Warn().
+		Err(err).
+		Str(client.logField("method"), spec.Method).
+		Func(client.logURL(client.baseUrl + spec.Path)).
+		Msg("retrying http request after stale connection")
+
+	rewind()
+
+	result, err = client.exchange(ctx, spec, options, cacheKey)
+	if result != nil {
+		result.Retries++
+	}
+
+	return result, err
+}
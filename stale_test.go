@@ -0,0 +1,107 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// staleTransport fails every odd request as if the server had closed the
+// reused connection before answering.
+type staleTransport struct {
+	calls atomic.Int32
+}
+
+func (transport *staleTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if trace := httptrace.ContextClientTrace(request.Context()); trace != nil && trace.GotConn != nil {
+		conn, peer := net.Pipe()
+		defer conn.Close()
+		defer peer.Close()
+
+		trace.GotConn(httptrace.GotConnInfo{Conn: conn, Reused: true})
+	}
+
+	if transport.calls.Add(1)%2 == 1 {
+		return nil, io.EOF
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("ok")),
+		Request:    request,
+	}, nil
+}
+
+func TestWithStaleConnectionRetry(t *testing.T) {
+	log := zerolog.Nop()
+	transport := &staleTransport{}
+
+	c, err := New("http://stale.invalid", nil, &log, false, "ua", WithHTTPClient(&http.Client{Transport: transport}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); err == nil {
+		t.Fatal("expected error without the option")
+	}
+
+	transport.calls.Store(0)
+
+	c, err = New("http://stale.invalid", nil, &log, false, "ua",
+		WithHTTPClient(&http.Client{Transport: transport}), WithStaleConnectionRetry())
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	resp, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if string(resp.Body) != "ok" || resp.Retries != 1 {
+		t.Fatalf("body=%q retries=%d", resp.Body, resp.Retries)
+	}
+
+	if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodPost, Path: "/"}); err == nil {
+		t.Fatal("POST must not be retried")
+	}
+}
+
+func TestWithStaleConnectionRetry_KeepsTruncatedSinkBody(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Length", "100")
+		_, _ = w.Write([]byte("hello"))
+		w.(http.Flusher).Flush()
+
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithStaleConnectionRetry())
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	var sink bytes.Buffer
+	if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}, Sink(&sink)); err == nil {
+		t.Fatal("expected the truncated body to fail")
+	}
+	if sink.String() != "hello" || calls.Load() != 1 {
+		t.Fatalf("sink=%q calls=%d", sink.String(), calls.Load())
+	}
+}
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+type Stats struct {
+	InFlight       int64
+	Queued         int64
+	MaxConcurrency int
+	Completed      int64
+	LastWait       time.Duration
+	AverageWait    time.Duration
+	MaxWait        time.Duration
+}
+
+type RequestMetrics struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Err        error
+	Duration   time.Duration
+	QueueWait  time.Duration
+	InFlight   int64
+	Queued     int64
+	Meta       *Meta
+	// Deprecation is the notice of the response, if the server sent one.
+	Deprecation *DeprecationNotice
+}
+
+type clientStats struct {
+	slots     chan struct{}
+	inFlight  atomic.Int64
+	queued    atomic.Int64
+	completed atomic.Int64
+	totalWait atomic.Int64
+	lastWait  atomic.Int64
+	maxWait   atomic.Int64
+}
+
+// WithMaxConcurrency caps the number of requests sent at the same time;
+// further requests queue until a slot frees up or their context is done.
+func WithMaxConcurrency(limit int) Option {
+	return func(client *Client) error {
+		if limit < 1 {
+			return errors.New("max concurrency must be positive")
+		}
+
+		client.stats.slots = make(chan struct{}, limit)
+
+		return nil
+	}
+}
+
+// WithMetricsHook is called after every request that reached the network
+// stage with its timings and the client saturation at that moment.
+func WithMetricsHook(hook func(RequestMetrics)) Option {
+	return func(client *Client) error {
+		client.metricsHook = hook
+
+		return nil
+	}
+}
+
+func (client *Client) Stats() Stats {
+	stats := &client.stats
+	completed := stats.completed.Load()
+
+	result := Stats{
+		InFlight:       stats.inFlight.Load(),
+		Queued:         stats.queued.Load(),
+		MaxConcurrency: cap(stats.slots),
+		Completed:      completed,
+		LastWait:       time.Duration(stats.lastWait.Load()),
+		MaxWait:        time.Duration(stats.maxWait.Load()),
+	}
+
+	if completed > 0 {
+		result.AverageWait = time.Duration(stats.totalWait.Load() / completed)
+	}
+
+	return result
+}
+
+func (client *Client) acquireSlot(ctx context.Context) (func(), time.Duration, error) {
+	stats := &client.stats
+	started := time.Now()
+
+	if stats.slots != nil {
+		stats.queued.Add(1)
+
+		select {
+		case stats.slots <- struct{}{}:
+			stats.queued.Add(-1)
+		case <-ctx.Done():
+			stats.queued.Add(-1)
+			return nil, time.Since(started), ctx.Err()
+		}
+	}
+
+	waited := time.Since(started)
+
+	stats.inFlight.Add(1)
+	stats.lastWait.Store(int64(waited))
+	stats.totalWait.Add(int64(waited))
+
+	for {
+		current := stats.maxWait.Load()
+		if int64(waited) <= current || stats.maxWait.CompareAndSwap(current, int64(waited)) {
+			break
+		}
+	}
+
+	return func() {
+		stats.inFlight.Add(-1)
+		stats.completed.Add(1)
+
+		if stats.slots != nil {
+			<-stats.slots
+		}
+	}, waited, nil
+}
+
+func (client *Client) recordMetrics(
+	ctx context.Context,
+	spec *RequestSpec,
+	response *Response,
+	err error,
+	waited time.Duration,
+	duration time.Duration,
+) {
+	if client.metricsHook == nil {
+		return
+	}
+
+	metrics := RequestMetrics{
+		Method:    spec.Method,
+		Path:      spec.Path,
+		Err:       err,
+		Duration:  duration,
+		QueueWait: waited,
+		InFlight:  client.stats.inFlight.Load(),
+		Queued:    client.stats.queued.Load(),
+		Meta:      MetaFromContext(ctx),
+	}
+
+	if response != nil {
+		metrics.StatusCode = response.StatusCode
+		metrics.Deprecation = response.Deprecation
+	}
+
+	client.metricsHook(metrics)
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithMaxConcurrency_QueuesAndReportsStats(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+
+	var mu sync.Mutex
+	var metrics []RequestMetrics
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua",
+		WithMaxConcurrency(2),
+		WithMetricsHook(func(m RequestMetrics) {
+			mu.Lock()
+			metrics = append(metrics, m)
+			mu.Unlock()
+		}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, _ = c.SendGet("/x", nil, nil)
+		}()
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for c.Stats().Queued != 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	stats := c.Stats()
+	if stats.InFlight != 2 || stats.Queued != 3 || stats.MaxConcurrency != 2 {
+		t.Fatalf("stats=%+v", stats)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	stats = c.Stats()
+	if stats.InFlight != 0 || stats.Queued != 0 || stats.Completed != 5 || stats.MaxWait < 20*time.Millisecond {
+		t.Fatalf("final stats=%+v", stats)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(metrics) != 5 || metrics[0].StatusCode != http.StatusOK || metrics[0].Method != http.MethodGet {
+		t.Fatalf("metrics=%+v", metrics)
+	}
+}
+
+func TestWithMaxConcurrency_QueuedRequestHonorsContext(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithMaxConcurrency(1))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	go func() { _, _, _ = c.SendGet("/busy", nil, nil) }()
+
+	for c.Stats().InFlight != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err = c.Send(ctx, RequestSpec{Method: http.MethodGet, Path: "/x"}); err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if c.Stats().Queued != 0 {
+		t.Fatalf("queued=%d", c.Stats().Queued)
+	}
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+type sessionKeyContextKey struct{}
+
+// StickySessions pins requests sharing a session key to one endpoint. The key
+// comes from WithSessionKey, then the Cookie named here, then the Header.
+type StickySessions struct {
+	Cookie string
+	Header string
+}
+
+func WithStickySessions(config StickySessions) Option {
+	return func(client *Client) error {
+		client.sticky = &config
+
+		return nil
+	}
+}
+
+func WithSessionKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, sessionKeyContextKey{}, key)
+}
+
+func (client *Client) sessionKey(ctx context.Context, spec *RequestSpec) string {
+	if client.sticky == nil || client.endpoints == nil {
+		return ""
+	}
+
+	if key, ok := ctx.Value(sessionKeyContextKey{}).(string); ok && key != "" {
+		return key
+	}
+
+	header := client.requestHeaders(spec)
+
+	if client.sticky.Cookie != "" {
+		request := &http.Request{Header: header}
+
+		if cookie, err := request.Cookie(client.sticky.Cookie); err == nil && cookie.Value != "" {
+			return cookie.Value
+		}
+	}
+
+	if client.sticky.Header != "" {
+		return header.Get(client.sticky.Header)
+	}
+
+	return ""
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func newStickyServers(t *testing.T, n int) ([]*httptest.Server, func() map[string]map[int]bool) {
+	t.Helper()
+
+	var mu sync.Mutex
+	seen := map[string]map[int]bool{}
+	servers := make([]*httptest.Server, n)
+
+	for i := range servers {
+		i := i
+		servers[i] = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("X-Session")
+			if cookie, err := r.Cookie("sid"); err == nil {
+				key = cookie.Value
+			}
+			mu.Lock()
+			if seen[key] == nil {
+				seen[key] = map[int]bool{}
+			}
+			seen[key][i] = true
+			mu.Unlock()
+		}))
+		t.Cleanup(servers[i].Close)
+	}
+
+	return servers, func() map[string]map[int]bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return seen
+	}
+}
+
+func TestWithStickySessions_RoutesKeyToSameEndpoint(t *testing.T) {
+	servers, seen := newStickyServers(t, 3)
+
+	log := zerolog.Nop()
+	c, err := New(servers[0].URL, nil, &log, false, "ua",
+		WithEndpoints(servers[1].URL, servers[2].URL),
+		WithStickySessions(StickySessions{Cookie: "sid", Header: "X-Session"}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		for s := 0; s < 5; s++ {
+			key := fmt.Sprintf("s%d", s)
+			if s%2 == 0 {
+				_, _, err = c.SendGet("/x", nil, Headers{"Cookie": "sid=" + key})
+			} else {
+				_, _, err = c.SendGet("/x", nil, Headers{"X-Session": key})
+			}
+			if err != nil {
+				t.Fatalf("SendGet error: %v", err)
+			}
+		}
+	}
+
+	for key, backends := range seen() {
+		if len(backends) != 1 {
+			t.Fatalf("session %s hit %d backends", key, len(backends))
+		}
+	}
+}
+
+func TestWithSessionKey_OverridesHeaders(t *testing.T) {
+	servers, seen := newStickyServers(t, 3)
+
+	log := zerolog.Nop()
+	c, err := New(servers[0].URL, nil, &log, false, "ua",
+		WithEndpoints(servers[1].URL, servers[2].URL),
+		WithStickySessions(StickySessions{Header: "X-Session"}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	ctx := WithSessionKey(context.Background(), "tenant-1")
+	for i := 0; i < 10; i++ {
+		_, err = c.Send(ctx, RequestSpec{
+			Method:  http.MethodGet,
+			Path:    "/x",
+			Headers: MultiHeaders{"X-Session": {fmt.Sprint(i)}},
+		})
+		if err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+	}
+
+	backends := map[int]bool{}
+	for _, hit := range seen() {
+		for backend := range hit {
+			backends[backend] = true
+		}
+	}
+	if len(backends) != 1 {
+		t.Fatalf("context session key hit %d backends", len(backends))
+	}
+}
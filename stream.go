@@ -0,0 +1,325 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var ErrStreamStalled = errors.New("no stream activity within the heartbeat interval")
+
+type StreamFormat int
+
+const (
+	// StreamSSE parses text/event-stream responses.
+	StreamSSE StreamFormat = iota
+	// StreamNDJSON yields every non-empty line as an event.
+	StreamNDJSON
+	// StreamLongPoll yields every non-empty response body as one event and
+	// is meant to be used with Reconnect.
+	StreamLongPoll
+)
+
+// StreamEvent is one message of a stream. NDJSON events only carry Data.
+type StreamEvent struct {
+	ID    string
+	Event string
+	Data  []byte
+	Retry time.Duration
+}
+
+// StreamStall describes a missed heartbeat.
+type StreamStall struct {
+	Method      string
+	Path        string
+	Events      int
+	LastEventID string
+	Idle        time.Duration
+}
+
+type StreamConfig struct {
+	Format StreamFormat
+	// Heartbeat fails the stream with ErrStreamStalled when nothing arrives
+	// within it. SSE comment lines count as activity. Zero disables it.
+	Heartbeat time.Duration
+	// OnStall is called before the stalled stream is torn down.
+	OnStall func(StreamStall)
+	// Reconnect sends the request again when the stream ends or fails.
+	Reconnect *Reconnect
+}
+
+type streamState struct {
+	mu          sync.Mutex
+	events      int
+	lastEventID string
+	lastSeen    time.Time
+	offset      int64
+	retry       time.Duration
+}
+
+// streamingBody lets a stream outlive the client timeout, which then only
+// covers waiting for the response headers. Heartbeat and WithBodyReadTimeout
+// still end silent streams.
+func streamingBody(options *requestOptions) {
+	options.streaming = true
+}
+
+type streamHandlerError struct {
+	err error
+}
+
+func (e *streamHandlerError) Error() string {
+	return e.err.Error()
+}
+
+// Stream sends spec and calls handle for every event of the response body
+// until the server ends it, ctx is done or handle returns an error, which is
+// then returned as is. With StreamConfig.Reconnect set, ended and failed
+// streams are sent again; bodies should then be set with a RequestOption
+// such as JSONBody so they are encoded again for every attempt. The client
+// timeout only covers waiting for the response headers.
+func (client *Client) Stream(
+	ctx context.Context,
+	spec RequestSpec,
+	config StreamConfig,
+	handle func(StreamEvent) error,
+	opts ...RequestOption,
+) error {
+	state := &streamState{}
+	failures := 0
+
+	for attempt := 1; ; attempt++ {
+		received := state.events
+		response, err := client.streamOnce(ctx, spec, config, handle, state, opts)
+
+		var handlerErr *streamHandlerError
+		if errors.As(err, &handlerErr) {
+			return handlerErr.err
+		}
+
+		if config.Reconnect == nil || ctx.Err() != nil || !reconnectable(response, err) {
+			return err
+		}
+
+		var delay time.Duration
+
+		delivered := state.events > received
+		if delivered {
+			failures = 0
+		}
+
+		if err != nil || !delivered {
+			failures++
+
+			if config.Reconnect.MaxAttempts > 0 && failures > config.Reconnect.MaxAttempts {
+				return err
+			}
+
+			delay = config.Reconnect.delay(failures, state.retry)
+		}
+
+		resume := state.resume(attempt)
+		client.reconnecting(spec, config, resume, delay, err)
+
+		if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+			return sleepErr
+		}
+
+		spec = config.resumeSpec(spec, resume)
+	}
+}
+
+func (client *Client) streamOnce(
+	ctx context.Context,
+	spec RequestSpec,
+	config StreamConfig,
+	handle func(StreamEvent) error,
+	state *streamState,
+	opts []RequestOption,
+) (*Response, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	state.mu.Lock()
+	state.lastSeen = time.Now()
+	state.mu.Unlock()
+
+	var watchdog *time.Timer
+
+	if config.Heartbeat > 0 {
+		watchdog = time.AfterFunc(config.Heartbeat, func() {
+			client.stalled(spec, config, state)
+			cancel(ErrStreamStalled)
+		})
+		defer watchdog.Stop()
+	}
+
+	reader, writer := io.Pipe()
+	parsed := make(chan error, 1)
+
+	go func() {
+		err := parseStream(reader, config.Format, func(event *StreamEvent, consumed int) error {
+			state.mu.Lock()
+			state.lastSeen = time.Now()
+			state.offset += int64(consumed)
+
+			if event != nil {
+				state.events++
+
+				if event.ID != "" {
+					state.lastEventID = event.ID
+				}
+
+				if event.Retry > 0 {
+					state.retry = event.Retry
+				}
+			}
+			state.mu.Unlock()
+
+			if watchdog != nil {
+				watchdog.Reset(config.Heartbeat)
+			}
+
+			if event == nil {
+				return nil
+			}
+
+			if err := handle(*event); err != nil {
+				return &streamHandlerError{err: err}
+			}
+
+			return nil
+		})
+
+		_ = reader.CloseWithError(err)
+		parsed <- err
+	}()
+
+	response, err := client.Send(ctx, spec, append(opts, Sink(writer), streamingBody)...)
+	_ = writer.CloseWithError(err)
+
+	parseErr := <-parsed
+
+	if errors.Is(context.Cause(ctx), ErrStreamStalled) {
+		return response, ErrStreamStalled
+	}
+
+	if parseErr != nil {
+		return response, parseErr
+	}
+
+	return response, err
+}
+
+func (client *Client) stalled(spec RequestSpec, config StreamConfig, state *streamState) {
+	state.mu.Lock()
+	stall := StreamStall{
+		Method:      spec.Method,
+		Path:        spec.Path,
+		Events:      state.events,
+		LastEventID: state.lastEventID,
+		Idle:        time.Since(state.lastSeen),
+	}
+	state.mu.Unlock()
+
+	client.logger.Warn().
+		Str(client.logField("method"), stall.Method).
+		Str("path", stall.Path).
+		Int("events", stall.Events).
+		Dur("idle", stall.Idle).
+		Msg("http stream stalled")
+
+	if config.OnStall != nil {
+		config.OnStall(stall)
+	}
+}
+
+// parseStream calls emit with nil for activity that carries no event and
+// with the number of raw bytes consumed since the previous call.
+func parseStream(r io.Reader, format StreamFormat, emit func(*StreamEvent, int) error) error {
+	if format == StreamLongPoll {
+		body, err := io.ReadAll(r)
+		if err != nil || len(body) == 0 {
+			return err
+		}
+
+		return emit(&StreamEvent{Data: body}, len(body))
+	}
+
+	reader := bufio.NewReader(r)
+
+	var (
+		event    StreamEvent
+		data     [][]byte
+		consumed int
+	)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && (!errors.Is(err, io.EOF) || len(line) == 0) {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+
+		consumed += len(line)
+		line = bytes.TrimRight(line, "\r\n")
+
+		if format == StreamNDJSON {
+			if len(line) == 0 {
+				continue
+			}
+
+			if emitErr := emit(&StreamEvent{Data: line}, consumed); emitErr != nil {
+				return emitErr
+			}
+
+			consumed = 0
+
+			continue
+		}
+
+		if len(line) == 0 {
+			if data == nil {
+				continue
+			}
+
+			event.Data = bytes.Join(data, []byte("\n"))
+
+			if emitErr := emit(&event, consumed); emitErr != nil {
+				return emitErr
+			}
+
+			event, data, consumed = StreamEvent{ID: event.ID}, nil, 0
+
+			continue
+		}
+
+		field, value, _ := bytes.Cut(line, []byte(":"))
+		value = bytes.TrimPrefix(value, []byte(" "))
+
+		switch string(field) {
+		case "":
+			if emitErr := emit(nil, 0); emitErr != nil {
+				return emitErr
+			}
+		case "data":
+			data = append(data, value)
+		case "event":
+			event.Event = string(value)
+		case "id":
+			event.ID = string(value)
+		case "retry":
+			if ms, convErr := strconv.Atoi(string(value)); convErr == nil {
+				event.Retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
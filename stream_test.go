@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStream_SSE(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(": hello\n\nid: 1\nevent: update\ndata: a\ndata: b\n\ndata: c\r\n\r\n"))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	var events []StreamEvent
+	err := c.Stream(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}, StreamConfig{},
+		func(event StreamEvent) error {
+			events = append(events, event)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("events=%+v", events)
+	}
+	if events[0].ID != "1" || events[0].Event != "update" || string(events[0].Data) != "a\nb" {
+		t.Fatalf("first=%+v", events[0])
+	}
+	if events[1].ID != "1" || events[1].Event != "" || string(events[1].Data) != "c" {
+		t.Fatalf("second=%+v", events[1])
+	}
+}
+
+func TestStream_NDJSONHandlerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{\"n\":1}\n\n{\"n\":2}\n{\"n\":3}\n"))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	stop := errors.New("stop")
+
+	var lines []string
+	err := c.Stream(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"},
+		StreamConfig{Format: StreamNDJSON},
+		func(event StreamEvent) error {
+			lines = append(lines, string(event.Data))
+			if len(lines) == 2 {
+				return stop
+			}
+			return nil
+		})
+	if !errors.Is(err, stop) {
+		t.Fatalf("err=%v", err)
+	}
+	if len(lines) != 2 || lines[1] != `{"n":2}` {
+		t.Fatalf("lines=%v", lines)
+	}
+}
+
+func TestStream_HeartbeatStall(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("id: 7\ndata: first\n\n"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	c := newTestClient(t, srv.URL)
+
+	var stall StreamStall
+	err := c.Stream(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/feed"},
+		StreamConfig{Heartbeat: 50 * time.Millisecond, OnStall: func(s StreamStall) { stall = s }},
+		func(StreamEvent) error { return nil })
+	if !errors.Is(err, ErrStreamStalled) {
+		t.Fatalf("err=%v", err)
+	}
+	if stall.Events != 1 || stall.LastEventID != "7" || stall.Path != "/feed" || stall.Idle < 50*time.Millisecond {
+		t.Fatalf("stall=%+v", stall)
+	}
+}
+
+func TestStream_OutlivesClientTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for i := 0; i < 5; i++ {
+			_, _ = w.Write([]byte("{}\n"))
+			w.(http.Flusher).Flush()
+			time.Sleep(40 * time.Millisecond)
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	c.httpClient.Timeout = 100 * time.Millisecond
+
+	events := 0
+	err := c.Stream(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}, StreamConfig{Format: StreamNDJSON},
+		func(StreamEvent) error {
+			events++
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	if events != 5 {
+		t.Fatalf("events = %d, want 5", events)
+	}
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+var ErrTimeout = errors.New("http request timed out")
+
+// TimeoutKind tells which limit fired.
+type TimeoutKind string
+
+const (
+	// TimeoutClient is the overall http.Client timeout from New.
+	TimeoutClient TimeoutKind = "client"
+	// TimeoutContext is the deadline of the request context.
+	TimeoutContext TimeoutKind = "context"
+	// TimeoutDial is the dialer timeout while connecting.
+	TimeoutDial TimeoutKind = "dial"
+	// TimeoutTLSHandshake is the transport's TLS handshake timeout.
+	TimeoutTLSHandshake TimeoutKind = "tls_handshake"
+	// TimeoutResponseHeader is the transport's response header timeout.
+	TimeoutResponseHeader TimeoutKind = "response_header"
+	// TimeoutBodyRead is a read deadline hit while reading the body.
+	TimeoutBodyRead TimeoutKind = "body_read"
+)
+
+// TimeoutError classifies a timeout. Phase is where the request was when it
+// fired: "dial", "tls", "headers" or "body".
+type TimeoutError struct {
+	Kind  TimeoutKind
+	Phase string
+	Err   error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s timeout during %s: %v", e.Kind, e.Phase, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+func (e *TimeoutError) Timeout() bool {
+	return true
+}
+
+func classifyTimeout(ctx context.Context, err error, timings *requestTimings, readingBody bool) error {
+	if err == nil || !isTimeout(err) {
+		return err
+	}
+
+	var classified *TimeoutError
+	if errors.As(err, &classified) {
+		return err
+	}
+
+	phase := timings.phase()
+	if readingBody {
+		phase = "body"
+	}
+
+	message := err.Error()
+
+	var kind TimeoutKind
+
+	var headerDeadline *errHeaderDeadline
+
+	switch {
+	case strings.Contains(message, "Client.Timeout"), errors.As(err, &headerDeadline):
+		kind = TimeoutClient
+	case errors.Is(err, context.DeadlineExceeded) && ctx.Err() != nil:
+		kind = TimeoutContext
+	case strings.Contains(message, "TLS handshake timeout"):
+		kind, phase = TimeoutTLSHandshake, "tls"
+	case strings.Contains(message, "timeout awaiting response headers"):
+		kind, phase = TimeoutResponseHeader, "headers"
+	case isDialError(err):
+		kind, phase = TimeoutDial, "dial"
+	case readingBody:
+		kind = TimeoutBodyRead
+	default:
+		return err
+	}
+
+	return &TimeoutError{Kind: kind, Phase: phase, Err: err}
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var timeout interface{ Timeout() bool }
+
+	return errors.As(err, &timeout) && timeout.Timeout()
+}
+
+func isDialError(err error) bool {
+	var opErr *net.OpError
+
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func (timings *requestTimings) phase() string {
+	timings.mu.Lock()
+	defer timings.mu.Unlock()
+
+	switch {
+	case !timings.firstByte.IsZero():
+		return "body"
+	case !timings.gotConn.IsZero():
+		return "headers"
+	case !timings.tlsStart.IsZero() && timings.tlsDone.IsZero():
+		return "tls"
+	default:
+		return "dial"
+	}
+}
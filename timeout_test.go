@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestTimeout_ClientTimeoutAwaitingHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	c.httpClient.Timeout = 50 * time.Millisecond
+
+	_, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"})
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("err=%v, want TimeoutError", err)
+	}
+	if timeoutErr.Kind != TimeoutClient || timeoutErr.Phase != "headers" || !errors.Is(err, ErrTimeout) {
+		t.Fatalf("kind=%s phase=%s", timeoutErr.Kind, timeoutErr.Phase)
+	}
+}
+
+func TestTimeout_ClientTimeoutReadingBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	c.httpClient.Timeout = 50 * time.Millisecond
+
+	_, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"})
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Kind != TimeoutClient || timeoutErr.Phase != "body" {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestTimeout_ContextDeadline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.Send(ctx, RequestSpec{Method: http.MethodGet, Path: "/"})
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Kind != TimeoutContext {
+		t.Fatalf("err=%v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("deadline not unwrapped: %v", err)
+	}
+}
+
+func TestClassifyTimeout_TransportErrors(t *testing.T) {
+	dial := &net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded}
+	read := &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}
+
+	cases := []struct {
+		name        string
+		err         error
+		readingBody bool
+		kind        TimeoutKind
+		phase       string
+	}{
+		{"dial", dial, false, TimeoutDial, "dial"},
+		{"tls", timeoutString("net/http: TLS handshake timeout"), false, TimeoutTLSHandshake, "tls"},
+		{"headers", timeoutString("net/http: timeout awaiting response headers"), false, TimeoutResponseHeader, "headers"},
+		{"body", read, true, TimeoutBodyRead, "body"},
+	}
+
+	for _, tc := range cases {
+		err := classifyTimeout(context.Background(), tc.err, &requestTimings{}, tc.readingBody)
+
+		var timeoutErr *TimeoutError
+		if !errors.As(err, &timeoutErr) || timeoutErr.Kind != tc.kind || timeoutErr.Phase != tc.phase {
+			t.Fatalf("%s: err=%v", tc.name, err)
+		}
+	}
+
+	if err := classifyTimeout(context.Background(), ErrRequestFailed, &requestTimings{}, true); err != ErrRequestFailed {
+		t.Fatalf("non-timeout error was wrapped: %v", err)
+	}
+}
+
+type timeoutString string
+
+func (e timeoutString) Error() string { return string(e) }
+func (e timeoutString) Timeout() bool { return true }
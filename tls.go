@@ -0,0 +1,67 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// WithTLSConfig replaces the TLS configuration of the transport with a copy
+// of config. Since it replaces the whole configuration, apply it before
+// WithClientCertificate and WithRootCAs.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(client *Client) error {
+		if config == nil {
+			return errors.New("tls config must not be nil")
+		}
+
+		client.mutableTransport().TLSClientConfig = config.Clone()
+
+		return nil
+	}
+}
+
+// WithClientCertificate presents the PEM encoded key pair to servers that
+// require mutual TLS.
+func WithClientCertificate(certFile, keyFile string) Option {
+	return func(client *Client) error {
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("load client certificate: %w", err)
+		}
+
+		config := client.tlsSettings()
+		config.Certificates = append(slices.Clip(config.Certificates), certificate)
+
+		return nil
+	}
+}
+
+// WithRootCAs verifies servers against pool instead of the system roots.
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(client *Client) error {
+		if pool == nil {
+			return errors.New("root CA pool must not be nil")
+		}
+
+		client.tlsSettings().RootCAs = pool
+
+		return nil
+	}
+}
+
+// tlsSettings returns the transport's TLS configuration for modification,
+// cloned first so a config shared with other transports is left untouched.
+func (client *Client) tlsSettings() *tls.Config {
+	transport := client.mutableTransport()
+
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	} else {
+		transport.TLSClientConfig = transport.TLSClientConfig.Clone()
+	}
+
+	return transport.TLSClientConfig
+}
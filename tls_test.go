@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func writeClientCertificate(t *testing.T) (string, string, *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "orders-service"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey: %v", err)
+	}
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+
+	if err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	return certFile, keyFile, cert
+}
+
+func newMutualTLSServer(t *testing.T, clientCert *x509.Certificate) *httptest.Server {
+	t.Helper()
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	srv.TLS = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestWithClientCertificate_MutualTLS(t *testing.T) {
+	certFile, keyFile, cert := writeClientCertificate(t)
+	srv := newMutualTLSServer(t, cert)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithRootCAs(roots), WithClientCertificate(certFile, keyFile))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	resp, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"})
+	if err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+	if string(resp.Body) != "orders-service" {
+		t.Fatalf("server saw client %q, want orders-service", resp.Body)
+	}
+}
+
+func TestWithRootCAs_WithoutClientCertificateIsRejected(t *testing.T) {
+	_, _, cert := writeClientCertificate(t)
+	srv := newMutualTLSServer(t, cert)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithRootCAs(roots))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); err == nil {
+		t.Fatalf("expected handshake failure without a client certificate")
+	}
+}
+
+func TestWithTLSConfig_CopiesConfig(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	certFile, keyFile, _ := writeClientCertificate(t)
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua",
+		WithTLSConfig(config), WithRootCAs(roots), WithClientCertificate(certFile, keyFile))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if config.RootCAs != nil || len(config.Certificates) != 0 {
+		t.Fatalf("caller's tls config was modified")
+	}
+
+	if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+}
+
+func TestWithClientCertificate_MissingFile(t *testing.T) {
+	log := zerolog.Nop()
+	_, err := New("https://example.com", nil, &log, false, "ua",
+		WithClientCertificate(filepath.Join(t.TempDir(), "missing.crt"), "missing.key"))
+	if err == nil {
+		t.Fatalf("expected an error for a missing certificate")
+	}
+}
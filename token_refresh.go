@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRefreshSkew        = time.Minute
+	defaultMinRefreshInterval = time.Second
+	refreshRetryBackoff       = 5 * time.Second
+)
+
+// TokenRefresh configures PreRefresh. Skew is how long before expiry the
+// token is refreshed, one minute by default; Jitter adds a random extra
+// lead of up to its value so replicas do not refresh in lockstep. The lead
+// never exceeds half of the token lifetime, so short-lived tokens are used
+// for a while, and refreshes are at least MinInterval apart, one second by
+// default.
+type TokenRefresh struct {
+	Skew        time.Duration
+	Jitter      time.Duration
+	MinInterval time.Duration
+}
+
+// RefreshingTokenSource caches a token and refreshes it in the background
+// ahead of expiry, so requests only wait for a fetch when no valid token
+// is cached.
+type RefreshingTokenSource struct {
+	source TokenSource
+	config TokenRefresh
+
+	mu      sync.Mutex
+	token   Token
+	timer   *time.Timer
+	stopped bool
+}
+
+// PreRefresh wraps source with background refresh. Stop it when done.
+func PreRefresh(source TokenSource, config TokenRefresh) *RefreshingTokenSource {
+	if config.Skew <= 0 {
+		config.Skew = defaultRefreshSkew
+	}
+
+	if config.MinInterval <= 0 {
+		config.MinInterval = defaultMinRefreshInterval
+	}
+
+	return &RefreshingTokenSource{source: source, config: config}
+}
+
+func (refreshing *RefreshingTokenSource) Token(ctx context.Context) (Token, error) {
+	refreshing.mu.Lock()
+	defer refreshing.mu.Unlock()
+
+	if refreshing.token.Value != "" && !refreshing.token.expired(time.Now()) {
+		return refreshing.token, nil
+	}
+
+	token, err := refreshing.source.Token(ctx)
+	if err != nil {
+		return Token{}, err
+	}
+
+	refreshing.store(token)
+
+	return token, nil
+}
+
+// Stop cancels the scheduled refresh; later calls to Token fetch on demand.
+func (refreshing *RefreshingTokenSource) Stop() {
+	refreshing.mu.Lock()
+	defer refreshing.mu.Unlock()
+
+	refreshing.stopped = true
+
+	if refreshing.timer != nil {
+		refreshing.timer.Stop()
+	}
+}
+
+// store must be called with refreshing.mu held.
+func (refreshing *RefreshingTokenSource) store(token Token) {
+	refreshing.token = token
+
+	if token.Expiry.IsZero() {
+		return
+	}
+
+	refreshing.schedule(refreshing.refreshDelay(time.Until(token.Expiry)))
+}
+
+func (refreshing *RefreshingTokenSource) refreshDelay(lifetime time.Duration) time.Duration {
+	lead := refreshing.config.Skew
+	if refreshing.config.Jitter > 0 {
+		lead += time.Duration(rand.Int63n(int64(refreshing.config.Jitter))) //nolint:gosec
+	}
+
+	lead = min(lead, lifetime/2)
+
+	return max(lifetime-lead, refreshing.config.MinInterval)
+}
+
+// schedule must be called with refreshing.mu held.
+func (refreshing *RefreshingTokenSource) schedule(delay time.Duration) {
+	if refreshing.stopped {
+		return
+	}
+
+	if refreshing.timer != nil {
+		refreshing.timer.Stop()
+	}
+
+	refreshing.timer = time.AfterFunc(max(delay, 0), refreshing.refresh)
+}
+
+func (refreshing *RefreshingTokenSource) refresh() {
+	token, err := refreshing.source.Token(context.Background())
+
+	refreshing.mu.Lock()
+	defer refreshing.mu.Unlock()
+
+	if err != nil {
+		remaining := time.Until(refreshing.token.Expiry)
+		if remaining > 0 {
+			refreshing.schedule(max(min(refreshRetryBackoff, remaining/2), refreshing.config.MinInterval))
+		}
+
+		return
+	}
+
+	refreshing.store(token)
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPreRefresh_RefreshesBeforeExpiry(t *testing.T) {
+	var fetches int32
+	source := TokenSourceFunc(func(context.Context) (Token, error) {
+		n := atomic.AddInt32(&fetches, 1)
+		return Token{Value: string(rune('a' + n)), Expiry: time.Now().Add(300 * time.Millisecond)}, nil
+	})
+
+	refreshing := PreRefresh(source, TokenRefresh{
+		Skew:        100 * time.Millisecond,
+		Jitter:      10 * time.Millisecond,
+		MinInterval: 10 * time.Millisecond,
+	})
+	defer refreshing.Stop()
+
+	first, err := refreshing.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token error: %v", err)
+	}
+
+	time.Sleep(250 * time.Millisecond)
+	if atomic.LoadInt32(&fetches) != 2 {
+		t.Fatalf("expected background refresh, fetches=%d", fetches)
+	}
+
+	second, err := refreshing.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token error: %v", err)
+	}
+	if second.Value == first.Value || atomic.LoadInt32(&fetches) != 2 {
+		t.Fatalf("first=%v second=%v fetches=%d", first, second, fetches)
+	}
+}
+
+func TestPreRefresh_ShortLivedTokenKeepsHalfItsLifetime(t *testing.T) {
+	refreshing := PreRefresh(nil, TokenRefresh{})
+
+	if got := refreshing.refreshDelay(30 * time.Second); got != 15*time.Second {
+		t.Fatalf("delay for a 30s token = %v, want 15s", got)
+	}
+	if got := refreshing.refreshDelay(10 * time.Minute); got != 9*time.Minute {
+		t.Fatalf("delay for a 10m token = %v, want 9m", got)
+	}
+	if got := refreshing.refreshDelay(-time.Second); got != defaultMinRefreshInterval {
+		t.Fatalf("delay for an expired token = %v, want %v", got, defaultMinRefreshInterval)
+	}
+}
+
+func TestPreRefresh_ThirtySecondTokenDoesNotLoop(t *testing.T) {
+	var fetches int32
+	source := TokenSourceFunc(func(context.Context) (Token, error) {
+		atomic.AddInt32(&fetches, 1)
+		return Token{Value: "t", Expiry: time.Now().Add(30 * time.Second)}, nil
+	})
+
+	refreshing := PreRefresh(source, TokenRefresh{})
+	defer refreshing.Stop()
+
+	if _, err := refreshing.Token(context.Background()); err != nil {
+		t.Fatalf("Token error: %v", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Fatalf("fetches = %d, want 1", n)
+	}
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"bytes"
+	"context"
+)
+
+// RequestTransform rewrites an encoded request body before it is sent, e.g.
+// to encrypt or sign it. It may change headers such as Content-Type.
+type RequestTransform func(ctx context.Context, headers MultiHeaders, body []byte) ([]byte, error)
+
+// ResponseTransform rewrites a response body after it is read and before it
+// is validated or decoded, e.g. to decrypt a JWE or PGP payload. The cache
+// stores the body as received and cache hits are transformed again, so
+// decrypted plaintext never reaches a DiskCache or RedisCache.
+type ResponseTransform func(ctx context.Context, response *Response) ([]byte, error)
+
+// WithRequestTransform adds a transform run on every request body, in the
+// order added. Requests without a body are left alone.
+func WithRequestTransform(transform RequestTransform) Option {
+	return func(client *Client) error {
+		client.requestTransforms = append(client.requestTransforms, transform)
+
+		return nil
+	}
+}
+
+// WithResponseTransform adds a transform run on every non-empty response
+// body, in the order added. Bodies streamed to a Sink are not transformed.
+func WithResponseTransform(transform ResponseTransform) Option {
+	return func(client *Client) error {
+		client.responseTransforms = append(client.responseTransforms, transform)
+
+		return nil
+	}
+}
+
+func (client *Client) transformRequest(ctx context.Context, spec *RequestSpec) error {
+	if len(client.requestTransforms) == 0 || spec.Body == nil {
+		return nil
+	}
+
+	body, err := readSpecBody(spec)
+	if err != nil {
+		return err
+	}
+
+	spec.Headers = spec.Headers.Clone()
+	if spec.Headers == nil {
+		spec.Headers = MultiHeaders{}
+	}
+
+	for _, transform := range client.requestTransforms {
+		if body, err = transform(ctx, spec.Headers, body); err != nil {
+			return err
+		}
+	}
+
+	spec.Body = bytes.NewReader(body)
+
+	return nil
+}
+
+func (client *Client) transformResponse(ctx context.Context, response *Response) error {
+	if len(response.Body) == 0 {
+		return nil
+	}
+
+	for _, transform := range client.responseTransforms {
+		body, err := transform(ctx, response)
+		if err != nil {
+			return err
+		}
+
+		response.Body = body
+	}
+
+	return nil
+}
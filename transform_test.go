@@ -0,0 +1,93 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestTransforms_EncryptRequestAndDecryptResponse(t *testing.T) {
+	var gotBody, gotType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		gotType = r.Header.Get(ContentTypeHeader)
+		_, _ = w.Write([]byte(base64.StdEncoding.EncodeToString([]byte(`{"id":1}`))))
+	}))
+	defer srv.Close()
+
+	encrypt := func(_ context.Context, headers MultiHeaders, body []byte) ([]byte, error) {
+		headers.Set(ContentTypeHeader, "application/jose")
+		return []byte(base64.StdEncoding.EncodeToString(body)), nil
+	}
+	decrypt := func(_ context.Context, response *Response) ([]byte, error) {
+		return base64.StdEncoding.DecodeString(string(bytes.TrimSpace(response.Body)))
+	}
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithRequestTransform(encrypt), WithResponseTransform(decrypt))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	response, err := c.Send(context.Background(), RequestSpec{Method: http.MethodPost, Path: "/"}, JSONBody(map[string]int{"n": 1}))
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if gotType != "application/jose" || gotBody != base64.StdEncoding.EncodeToString([]byte(`{"n":1}`)) {
+		t.Fatalf("type=%s body=%s", gotType, gotBody)
+	}
+	if string(response.Body) != `{"id":1}` {
+		t.Fatalf("response body=%s", response.Body)
+	}
+}
+
+func TestTransforms_CacheKeepsRawBody(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		_, _ = w.Write([]byte(base64.StdEncoding.EncodeToString([]byte(`{"secret":1}`))))
+	}))
+	defer srv.Close()
+
+	decrypt := func(_ context.Context, response *Response) ([]byte, error) {
+		return base64.StdEncoding.DecodeString(string(response.Body))
+	}
+
+	cache := NewMemoryCache()
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua",
+		WithCache(cache, time.Minute),
+		WithResponseTransform(decrypt))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		response, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/secret"})
+		if err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+		if string(response.Body) != `{"secret":1}` {
+			t.Fatalf("send %d: body=%s", i, response.Body)
+		}
+	}
+
+	if hits != 1 {
+		t.Fatalf("hits=%d, want 1", hits)
+	}
+	entry, err := cache.Get(context.Background(), c.cacheKey(context.Background(), &RequestSpec{Method: http.MethodGet, Path: "/secret"}))
+	if err != nil {
+		t.Fatalf("cache Get: %v", err)
+	}
+	if bytes.Contains(entry.Body, []byte("secret")) {
+		t.Fatalf("plaintext cached: %s", entry.Body)
+	}
+}
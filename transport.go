@@ -0,0 +1,76 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+)
+
+var errTransportOptions = errors.New("transport options need an *http.Transport")
+
+// WithHTTPClient sends requests through a copy of httpClient, keeping its
+// transport, cookie jar, redirect policy and timeout; the timeout passed to
+// New is ignored. Apply it before any other option. A shared *http.Transport
+// is cloned only once another option has to change it, such as WithTLSConfig
+// or WithIPFamily; those options fail with any other RoundTripper.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(client *Client) error {
+		if httpClient == nil {
+			return errors.New("http client must not be nil")
+		}
+
+		if client.httpClient.Transport != nil {
+			return errors.New("WithHTTPClient must be applied before transport options")
+		}
+
+		client.httpClient = *httpClient
+		client.sharedTransport = true
+
+		return nil
+	}
+}
+
+// transport returns the *http.Transport requests go through without
+// changing the client, so it is safe while requests are in flight. It is nil
+// for a custom RoundTripper and for the net/http default transport.
+func (client *Client) transport() *http.Transport {
+	switch transport := client.httpClient.Transport.(type) {
+	case *http.Transport:
+		return transport
+	case *middlewareTransport:
+		base, _ := transport.base.(*http.Transport)
+		return base
+	}
+
+	return nil
+}
+
+// mutableTransport returns a transport owned by the client, installing or
+// cloning one first. Only options and New may call it.
+func (client *Client) mutableTransport() *http.Transport {
+	switch transport := client.httpClient.Transport.(type) {
+	case *http.Transport:
+		if client.sharedTransport {
+			transport = transport.Clone()
+			client.httpClient.Transport = transport
+			client.sharedTransport = false
+		}
+
+		return transport
+	case *middlewareTransport:
+		if base, ok := transport.base.(*http.Transport); ok {
+			return base
+		}
+
+		client.replacedTransport = transport.base
+
+		return http.DefaultTransport.(*http.Transport).Clone()
+	case nil:
+	default:
+		client.replacedTransport = transport
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	client.httpClient.Transport = transport
+
+	return transport
+}
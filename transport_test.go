@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithHTTPClient_UsesInjectedClient(t *testing.T) {
+	var gotCookie, gotUA string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("session"); err == nil {
+			gotCookie = cookie.Value
+		}
+		gotUA = r.Header.Get("User-Agent")
+	}))
+	defer srv.Close()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatalf("cookiejar.New: %v", err)
+	}
+	u, _ := url.Parse(srv.URL)
+	jar.SetCookies(u, []*http.Cookie{{Name: "session", Value: "abc"}})
+
+	trips := 0
+	injected := &http.Client{
+		Jar: jar,
+		Transport: RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			trips++
+			return http.DefaultTransport.RoundTrip(r)
+		}),
+	}
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "orders-service/1.0", WithHTTPClient(injected))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	if trips != 1 {
+		t.Fatalf("injected transport used %d times, want 1", trips)
+	}
+	if gotCookie != "abc" {
+		t.Fatalf("cookie = %q, want abc", gotCookie)
+	}
+	if gotUA != "orders-service/1.0" {
+		t.Fatalf("User-Agent = %q", gotUA)
+	}
+}
+
+func TestWithHTTPClient_ClonesSharedTransportBeforeChangingIt(t *testing.T) {
+	shared := &http.Transport{}
+	injected := &http.Client{Transport: shared}
+
+	log := zerolog.Nop()
+	c, err := New("http://example.com", nil, &log, false, "ua",
+		WithHTTPClient(injected), WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS13}))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if shared.TLSClientConfig != nil && shared.TLSClientConfig.MinVersion == tls.VersionTLS13 {
+		t.Fatalf("shared transport was modified")
+	}
+	if injected.Transport != shared {
+		t.Fatalf("injected client was modified")
+	}
+	if got := c.transport().TLSClientConfig; got == nil || got.MinVersion != tls.VersionTLS13 {
+		t.Fatalf("client transport did not get the tls config")
+	}
+}
+
+func TestWithHTTPClient_KeepsSharedTransportWithoutTransportOptions(t *testing.T) {
+	shared := &http.Transport{}
+
+	log := zerolog.Nop()
+	c, err := New("http://example.com", nil, &log, false, "ua", WithHTTPClient(&http.Client{Transport: shared}))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if c.httpClient.Transport != shared {
+		t.Fatalf("transport was replaced")
+	}
+}
+
+func TestWithHTTPClient_TransportOptionsNeedHTTPTransport(t *testing.T) {
+	injected := &http.Client{Transport: RoundTripperFunc(http.DefaultTransport.RoundTrip)}
+
+	log := zerolog.Nop()
+	_, err := New("http://example.com", nil, &log, false, "ua", WithHTTPClient(injected), WithIPFamily(IPFamilyIPv4Only))
+	if !errors.Is(err, errTransportOptions) {
+		t.Fatalf("err = %v, want errTransportOptions", err)
+	}
+}
+
+func TestWithHTTPClient_MustComeFirst(t *testing.T) {
+	log := zerolog.Nop()
+	_, err := New("http://example.com", nil, &log, false, "ua",
+		WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}), WithHTTPClient(&http.Client{}))
+	if err == nil {
+		t.Fatalf("expected an error when WithHTTPClient follows transport options")
+	}
+}
+
+func TestWithHTTPClient_MiddlewareWrapsCustomTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	var order []string
+	injected := &http.Client{Transport: RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		order = append(order, "transport")
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+	middleware := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			order = append(order, "middleware")
+			return next.RoundTrip(r)
+		})
+	}
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithHTTPClient(injected), WithMiddleware(middleware))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	if len(order) != 2 || order[0] != "middleware" || order[1] != "transport" {
+		t.Fatalf("order = %v", order)
+	}
+}
+
+func TestTransport_ReadOnlyKeepsCustomRoundTripper(t *testing.T) {
+	trips := 0
+	custom := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		trips++
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithHTTPClient(&http.Client{Transport: custom}))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if c.transport() != nil {
+		t.Fatalf("transport() should be nil for a custom RoundTripper")
+	}
+
+	if _, err = c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+	if trips != 1 {
+		t.Fatalf("custom RoundTripper used %d times, want 1", trips)
+	}
+}
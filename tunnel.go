@@ -0,0 +1,190 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+var (
+	ErrTraceDisabled   = errors.New("TRACE requests are disabled")
+	ErrConnectDisabled = errors.New("CONNECT tunneling is disabled")
+)
+
+// traceStrippedHeaders are never sent with TRACE, since the server echoes
+// the request back (cross-site tracing).
+var traceStrippedHeaders = []string{AuthorizationHeader, "Proxy-Authorization", "Cookie"}
+
+func WithTraceRequests() Option {
+	return func(client *Client) error {
+		client.allowTrace = true
+
+		return nil
+	}
+}
+
+func WithConnectTunneling() Option {
+	return func(client *Client) error {
+		client.allowConnect = true
+
+		return nil
+	}
+}
+
+// Trace sends a TRACE request without a body and without credential headers.
+// It has to be enabled with WithTraceRequests.
+func (client *Client) Trace(ctx context.Context, path string) (*Response, error) {
+	if !client.allowTrace {
+		return nil, ErrTraceDisabled
+	}
+
+	return client.Send(ctx, RequestSpec{Method: http.MethodTrace, Path: path}, stripHeaders(traceStrippedHeaders...))
+}
+
+func stripHeaders(names ...string) RequestOption {
+	return func(options *requestOptions) {
+		options.stripHeaders = append(options.stripHeaders, names...)
+	}
+}
+
+// Connect asks the server at the base URL to open a tunnel to target
+// (host:port) and returns the raw connection once it answers 2xx. It has to
+// be enabled with WithConnectTunneling.
+func (client *Client) Connect(ctx context.Context, target string) (net.Conn, error) {
+	if !client.allowConnect {
+		return nil, ErrConnectDisabled
+	}
+
+	baseUrl := client.selectBaseUrl("")
+	defer client.releaseEndpoint(baseUrl)
+
+	proxy, err := url.Parse(baseUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := client.dialProxy(ctx, proxy)
+	if err != nil {
+		client.logger.Error().
+			Err(err).
+			Str(client.logField("method"), http.MethodConnect).
+			Str(client.logField("url"), baseUrl).
+			Msg("failed to connect to tunnel proxy")
+		return nil, err
+	}
+
+	tunnel, err := client.openTunnel(ctx, conn, target)
+	if err != nil {
+		_ = conn.Close()
+		client.logger.Error().
+			Err(err).
+			Str(client.logField("method"), http.MethodConnect).
+			Str(client.logField("url"), baseUrl).
+			Str("target", target).
+			Msg("failed to open tunnel")
+		return nil, err
+	}
+
+	client.logger.Info().
+		Str(client.logField("method"), http.MethodConnect).
+		Str(client.logField("url"), baseUrl).
+		Str("target", target).
+		Msg("tunnel established")
+
+	return tunnel, nil
+}
+
+func (client *Client) dialProxy(ctx context.Context, proxy *url.URL) (net.Conn, error) {
+	port := proxy.Port()
+	if port == "" {
+		port = "80"
+		if proxy.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	address := net.JoinHostPort(proxy.Hostname(), port)
+
+	transport := client.transport()
+
+	dial := (&net.Dialer{}).DialContext
+	if transport != nil && transport.DialContext != nil {
+		dial = transport.DialContext
+	}
+
+	conn, err := dial(ctx, "tcp", address)
+	if err != nil || proxy.Scheme != "https" {
+		return conn, err
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if transport != nil && transport.TLSClientConfig != nil {
+		config = transport.TLSClientConfig.Clone()
+	}
+
+	if config.ServerName == "" {
+		config.ServerName = proxy.Hostname()
+	}
+
+	tlsConn := tls.Client(conn, config)
+	if err = tlsConn.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return tlsConn, nil
+}
+
+func (client *Client) openTunnel(ctx context.Context, conn net.Conn, target string) (net.Conn, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	request := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: target},
+		Host:   target,
+		Header: client.requestHeaders(&RequestSpec{}),
+	}
+
+	if client.userAgent != "" {
+		request.Header.Set("User-Agent", client.userAgent)
+	}
+
+	if err := request.Write(conn); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, fmt.Errorf("%w: %s", ErrRequestFailed, response.Status)
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+
+	return &tunnelConn{Conn: conn, reader: reader}, nil
+}
+
+// tunnelConn keeps bytes the proxy sent right after its CONNECT response.
+type tunnelConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (conn *tunnelConn) Read(p []byte) (int, error) {
+	return conn.reader.Read(p)
+}
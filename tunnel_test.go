@@ -0,0 +1,81 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestTrace_RequiresOptInAndStripsCredentials(t *testing.T) {
+	var gotMethod, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotAuth = r.Method, r.Header.Get(AuthorizationHeader)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	if _, err := c.Trace(context.Background(), "/"); !errors.Is(err, ErrTraceDisabled) {
+		t.Fatalf("expected ErrTraceDisabled, got %v", err)
+	}
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithTraceRequests())
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	c.SetHeader(AuthorizationHeader, "Bearer secret")
+
+	if _, err = c.Trace(context.Background(), "/"); err != nil {
+		t.Fatalf("Trace error: %v", err)
+	}
+	if gotMethod != http.MethodTrace || gotAuth != "" {
+		t.Fatalf("method=%s auth=%q", gotMethod, gotAuth)
+	}
+}
+
+func TestConnect_ReturnsTunnel(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		req, err := http.ReadRequest(bufio.NewReader(conn))
+		if err != nil || req.Method != http.MethodConnect || req.Host != "db.internal:5432" {
+			_, _ = io.WriteString(conn, "HTTP/1.1 400 Bad Request\r\n\r\n")
+			return
+		}
+		_, _ = io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\nhello")
+	}()
+
+	log := zerolog.Nop()
+	c, err := New("http://"+ln.Addr().String(), nil, &log, false, "ua", WithConnectTunneling())
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	conn, err := c.Connect(context.Background(), "db.internal:5432")
+	if err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+	defer conn.Close()
+
+	buf := make([]byte, 5)
+	if _, err = io.ReadFull(conn, buf); err != nil || string(buf) != "hello" {
+		t.Fatalf("read %q, err %v", buf, err)
+	}
+}
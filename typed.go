@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// GetAs sends a GET and decodes the response body into T with DecodeJSON.
+// An empty body leaves T at its zero value.
+func GetAs[T any](
+	ctx context.Context,
+	client *Client,
+	path string,
+	params MultiParams,
+	headers MultiHeaders,
+	opts ...RequestOption,
+) (T, *Response, error) {
+	spec := RequestSpec{Method: http.MethodGet, Path: path, Params: params, Headers: headers}
+
+	return SendAs[T](ctx, client, spec, opts...)
+}
+
+func DeleteAs[T any](
+	ctx context.Context,
+	client *Client,
+	path string,
+	params MultiParams,
+	headers MultiHeaders,
+	opts ...RequestOption,
+) (T, *Response, error) {
+	spec := RequestSpec{Method: http.MethodDelete, Path: path, Params: params, Headers: headers}
+
+	return SendAs[T](ctx, client, spec, opts...)
+}
+
+// PostAs encodes body with the client's body marshaler, sends it and decodes
+// the response like GetAs. A nil body sends none.
+func PostAs[T any](
+	ctx context.Context,
+	client *Client,
+	path string,
+	body any,
+	params MultiParams,
+	headers MultiHeaders,
+	opts ...RequestOption,
+) (T, *Response, error) {
+	return sendBodyAs[T](ctx, client, http.MethodPost, path, body, params, headers, opts)
+}
+
+func PutAs[T any](
+	ctx context.Context,
+	client *Client,
+	path string,
+	body any,
+	params MultiParams,
+	headers MultiHeaders,
+	opts ...RequestOption,
+) (T, *Response, error) {
+	return sendBodyAs[T](ctx, client, http.MethodPut, path, body, params, headers, opts)
+}
+
+func PatchAs[T any](
+	ctx context.Context,
+	client *Client,
+	path string,
+	body any,
+	params MultiParams,
+	headers MultiHeaders,
+	opts ...RequestOption,
+) (T, *Response, error) {
+	return sendBodyAs[T](ctx, client, http.MethodPatch, path, body, params, headers, opts)
+}
+
+// SendAs sends spec and decodes the response body into T. On error the
+// response is still returned when there is one.
+func SendAs[T any](ctx context.Context, client *Client, spec RequestSpec, opts ...RequestOption) (T, *Response, error) {
+	var result T
+
+	response, err := client.Send(ctx, spec, opts...)
+	if err != nil || len(response.Body) == 0 {
+		return result, response, err
+	}
+
+	err = client.DecodeJSON(response, &result)
+
+	return result, response, err
+}
+
+func sendBodyAs[T any](
+	ctx context.Context,
+	client *Client,
+	method, path string,
+	body any,
+	params MultiParams,
+	headers MultiHeaders,
+	opts []RequestOption,
+) (T, *Response, error) {
+	spec := RequestSpec{Method: method, Path: path, Params: params, Headers: headers}
+
+	if body != nil {
+		spec.Options = []RequestOption{Body(body)}
+	}
+
+	return SendAs[T](ctx, client, spec, opts...)
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type typedItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestGetAs_DecodesBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("id") != "7" || r.Header.Get("X-Trace") != "t1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":7,"name":"seven"}`))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	item, resp, err := GetAs[typedItem](context.Background(), c, "/items",
+		MultiParams{"id": {"7"}}, MultiHeaders{"X-Trace": {"t1"}})
+	if err != nil {
+		t.Fatalf("GetAs error: %v", err)
+	}
+	if item != (typedItem{ID: 7, Name: "seven"}) || resp.StatusCode != http.StatusOK {
+		t.Fatalf("item=%+v status=%d", item, resp.StatusCode)
+	}
+}
+
+func TestPostAs_EncodesBodyAndKeepsErrorResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in typedItem
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		if in.Name == "" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		in.ID = 1
+		_ = json.NewEncoder(w).Encode(in)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	created, _, err := PostAs[typedItem](context.Background(), c, "/items", typedItem{Name: "one"}, nil, nil)
+	if err != nil || created.ID != 1 || created.Name != "one" {
+		t.Fatalf("created=%+v err=%v", created, err)
+	}
+
+	_, resp, err := PostAs[typedItem](context.Background(), c, "/items", typedItem{}, nil, nil)
+	if !errors.Is(err, ErrRequestFailed) || resp == nil || resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("resp=%+v err=%v", resp, err)
+	}
+}
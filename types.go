@@ -1,9 +1,71 @@
 package client
 
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Deprecated: Headers keeps a single value per name, use MultiHeaders.
 type Headers map[string]string
 
+// Deprecated: Params keeps a single value per key, use MultiParams.
 type Params map[string]string
 
+type MultiHeaders map[string][]string
+
+type MultiParams map[string][]string
+
+type RequestSpec struct {
+	Method  string
+	Path    string
+	Params  MultiParams
+	Headers MultiHeaders
+	Body    io.Reader
+
+	// OrderedParams are appended after Params in insertion order.
+	OrderedParams OrderedParams
+
+	// Options apply to this request before any passed to Send.
+	Options []RequestOption
+}
+
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Request    *RequestSpec
+	Pagination *MetaResponse
+	Links      *LinksResponse
+
+	// BytesWritten is the number of body bytes copied to a Sink.
+	BytesWritten  int64
+	BodyTruncated bool
+	// Decompressed reports that the body arrived with a Content-Encoding
+	// that was decoded, by the client or the transport; false for cache hits.
+	Decompressed bool
+
+	Meta *Meta
+
+	// URL is the URL that answered the request, after any redirects.
+	URL string
+	// Endpoint is the base URL that served the request, empty for cache hits.
+	Endpoint string
+	CacheHit bool
+	Retries  int
+	// Duration covers all attempts, including queueing and retry waits.
+	Duration time.Duration
+
+	// Connection is nil for cache hits.
+	Connection *ConnectionInfo
+	// Deprecation is nil unless the server sent Deprecation, Sunset or
+	// Warning headers.
+	Deprecation *DeprecationNotice
+
+	strictDecoding *bool
+}
+
 type Href string
 
 type LinksResponse struct {
@@ -30,3 +92,93 @@ type MetaResponse struct {
 	CurrentPage int `json:"currentPage"`
 	PerPage     int `json:"perPage"`
 }
+
+func (headers Headers) Multi() MultiHeaders {
+	multi := MultiHeaders{}
+
+	for key, val := range headers {
+		multi.Add(key, val)
+	}
+
+	return multi
+}
+
+func (params Params) Multi() MultiParams {
+	multi := MultiParams{}
+
+	for key, val := range params {
+		multi.Add(key, val)
+	}
+
+	return multi
+}
+
+func HeadersFrom(header http.Header) MultiHeaders {
+	return MultiHeaders(header.Clone())
+}
+
+func ParamsFrom(values url.Values) MultiParams {
+	multi := MultiParams{}
+
+	for key, val := range values {
+		multi[key] = append([]string(nil), val...)
+	}
+
+	return multi
+}
+
+func (headers MultiHeaders) Add(key, val string) {
+	http.Header(headers).Add(key, val)
+}
+
+func (headers MultiHeaders) Set(key, val string) {
+	http.Header(headers).Set(key, val)
+}
+
+func (headers MultiHeaders) Get(key string) string {
+	return http.Header(headers).Get(key)
+}
+
+func (headers MultiHeaders) Values(key string) []string {
+	return http.Header(headers).Values(key)
+}
+
+func (headers MultiHeaders) Del(key string) {
+	http.Header(headers).Del(key)
+}
+
+func (headers MultiHeaders) Header() http.Header {
+	return http.Header(headers).Clone()
+}
+
+func (headers MultiHeaders) Clone() MultiHeaders {
+	return MultiHeaders(http.Header(headers).Clone())
+}
+
+func (params MultiParams) Add(key, val string) {
+	params[key] = append(params[key], val)
+}
+
+func (params MultiParams) Set(key, val string) {
+	params[key] = []string{val}
+}
+
+func (params MultiParams) Get(key string) string {
+	return url.Values(params).Get(key)
+}
+
+func (params MultiParams) Has(key string) bool {
+	return url.Values(params).Has(key)
+}
+
+func (params MultiParams) Del(key string) {
+	delete(params, key)
+}
+
+func (params MultiParams) Values() url.Values {
+	return url.Values(params.Clone())
+}
+
+func (params MultiParams) Clone() MultiParams {
+	return ParamsFrom(url.Values(params))
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestMultiParamsAndHeaders_KeepRepeatedValues(t *testing.T) {
+	var gotIDs, gotAccept []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotIDs = r.URL.Query()["id"]
+		gotAccept = r.Header.Values("Accept")
+	}))
+	defer srv.Close()
+
+	params := MultiParams{}
+	params.Add("id", "1")
+	params.Add("id", "2")
+
+	headers := MultiHeaders{}
+	headers.Add("accept", "application/json")
+	headers.Add("Accept", "text/plain")
+
+	c := newTestClient(t, srv.URL)
+	_, err := c.Send(context.Background(), RequestSpec{
+		Method:  http.MethodGet,
+		Path:    "/items",
+		Params:  params,
+		Headers: headers,
+	})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if !reflect.DeepEqual(gotIDs, []string{"1", "2"}) {
+		t.Fatalf("id=%v", gotIDs)
+	}
+	if !reflect.DeepEqual(gotAccept, []string{"application/json", "text/plain"}) {
+		t.Fatalf("accept=%v", gotAccept)
+	}
+}
+
+func TestLegacyConversions(t *testing.T) {
+	params := Params{"a": "1"}.Multi()
+	if params.Get("a") != "1" || len(params["a"]) != 1 {
+		t.Fatalf("params=%v", params)
+	}
+
+	headers := Headers{"x-trace": "t"}.Multi()
+	if headers.Get("X-Trace") != "t" {
+		t.Fatalf("headers=%v", headers)
+	}
+
+	back := HeadersFrom(headers.Header())
+	back.Add("X-Trace", "u")
+	if len(headers.Values("X-Trace")) != 1 || len(back.Values("X-Trace")) != 2 {
+		t.Fatalf("HeadersFrom must copy: %v %v", headers, back)
+	}
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+const sniffLength = 512
+
+// FileUpload configures SendFile. ContentType overrides detection from the
+// file extension and, failing that, from the first bytes of the file.
+type FileUpload struct {
+	ContentType string
+	Params      MultiParams
+	Headers     MultiHeaders
+}
+
+// SendFile streams the file at name as the request body with its exact
+// Content-Length. Retries read the file again instead of buffering it.
+func (client *Client) SendFile(
+	ctx context.Context,
+	method string,
+	path string,
+	name string,
+	upload FileUpload,
+	opts ...RequestOption,
+) (*Response, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := upload.ContentType
+	if contentType == "" {
+		if contentType, err = detectContentType(file, name); err != nil {
+			return nil, err
+		}
+	}
+
+	headers := MultiHeaders{}
+	headers.Set(ContentTypeHeader, contentType)
+
+	spec := RequestSpec{Method: method, Path: path, Params: upload.Params, Headers: upload.Headers}
+	if info.Size() > 0 {
+		spec.Body = io.NewSectionReader(file, 0, info.Size())
+	}
+
+	return client.Send(ctx, spec, append([]RequestOption{withContentLength(info.Size()), withHeaders(headers)}, opts...)...)
+}
+
+func detectContentType(file *os.File, name string) (string, error) {
+	if byExtension := mime.TypeByExtension(filepath.Ext(name)); byExtension != "" {
+		return byExtension, nil
+	}
+
+	head := make([]byte, sniffLength)
+
+	n, err := file.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	return http.DetectContentType(head[:n]), nil
+}
+
+func withContentLength(length int64) RequestOption {
+	return func(options *requestOptions) {
+		options.contentLength = length
+	}
+}
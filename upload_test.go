@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestSendFile(t *testing.T) {
+	type seen struct {
+		contentType string
+		length      int64
+		body        string
+	}
+	var got seen
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got = seen{r.Header.Get(ContentTypeHeader), r.ContentLength, string(b)}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	jsonFile := filepath.Join(dir, "data.json")
+	rawFile := filepath.Join(dir, "blob")
+	png := "\x89PNG\r\n\x1a\n0000"
+
+	if err := os.WriteFile(jsonFile, []byte(`{"a":1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(rawFile, []byte(png), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := newTestClient(t, srv.URL)
+
+	cases := []struct {
+		name   string
+		upload FileUpload
+		want   seen
+	}{
+		{jsonFile, FileUpload{}, seen{"application/json", 7, `{"a":1}`}},
+		{rawFile, FileUpload{}, seen{"image/png", int64(len(png)), png}},
+		{rawFile, FileUpload{ContentType: "application/octet-stream"}, seen{"application/octet-stream", int64(len(png)), png}},
+	}
+
+	for _, tc := range cases {
+		if _, err := c.SendFile(context.Background(), http.MethodPut, "/upload", tc.name, tc.upload); err != nil {
+			t.Fatalf("SendFile error: %v", err)
+		}
+		if got != tc.want {
+			t.Errorf("%s: got %+v, want %+v", filepath.Base(tc.name), got, tc.want)
+		}
+	}
+}
+
+func TestSendFile_RetriesRereadFileAndRespectLimit(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		if string(b) != "0123456789" || r.ContentLength != 10 {
+			t.Errorf("attempt %d: body=%q length=%d", calls.Load()+1, b, r.ContentLength)
+		}
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	name := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(name, []byte("0123456789"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithRetry(RetryPolicy{Backoff: time.Millisecond}))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	if _, err = c.SendFile(context.Background(), http.MethodPut, "/upload", name, FileUpload{}); err != nil {
+		t.Fatalf("SendFile error: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("calls = %d, want 3", calls.Load())
+	}
+
+	limited, err := New(srv.URL, nil, &log, false, "ua",
+		WithRetry(RetryPolicy{Backoff: time.Millisecond}), WithMaxRequestBytes(4))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	if _, err = limited.SendFile(context.Background(), http.MethodPut, "/upload", name, FileUpload{}); !errors.Is(err, ErrRequestTooLarge) {
+		t.Fatalf("err = %v, want ErrRequestTooLarge", err)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("oversized file reached the server")
+	}
+}
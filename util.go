@@ -1,9 +1,29 @@
 package client
 
-import "encoding/base64"
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+)
 
 func PrepareBasicAuth(username, password string) string {
 	auth := username + ":" + password
 
 	return base64.StdEncoding.EncodeToString([]byte(auth))
 }
+
+// resolveSameOrigin resolves ref against base and reports whether the result
+// keeps the scheme and host of base.
+func resolveSameOrigin(base, ref string) (string, bool) {
+	baseUrl, err := url.Parse(base)
+	if err != nil || !baseUrl.IsAbs() {
+		return "", false
+	}
+
+	target, err := baseUrl.Parse(ref)
+	if err != nil || !strings.EqualFold(target.Scheme, baseUrl.Scheme) || !strings.EqualFold(target.Host, baseUrl.Host) {
+		return "", false
+	}
+
+	return target.String(), true
+}
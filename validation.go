@@ -0,0 +1,100 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+type RequestValidationError struct {
+	Method string
+	Path   string
+	Err    error
+}
+
+func (e *RequestValidationError) Error() string {
+	return fmt.Sprintf("request validation failed (%s %s): %v", e.Method, e.Path, e.Err)
+}
+
+func (e *RequestValidationError) Unwrap() error {
+	return e.Err
+}
+
+type ResponseValidationError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *ResponseValidationError) Error() string {
+	return fmt.Sprintf("response validation failed (status %d): %v", e.StatusCode, e.Err)
+}
+
+func (e *ResponseValidationError) Unwrap() error {
+	return e.Err
+}
+
+func (client *Client) validateResponse(response *Response) error {
+	for _, validator := range client.validators {
+		if err := validator(response); err != nil {
+			return &ResponseValidationError{StatusCode: response.StatusCode, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func (client *Client) validateRequest(spec *RequestSpec) error {
+	if err := client.validateMethod(spec.Method); err != nil {
+		return &RequestValidationError{Method: spec.Method, Path: spec.Path, Err: err}
+	}
+
+	for _, validator := range client.requestValidators {
+		if err := validator(spec); err != nil {
+			return &RequestValidationError{Method: spec.Method, Path: spec.Path, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func readSpecBody(spec *RequestSpec) ([]byte, error) {
+	if spec.Body == nil {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(spec.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	spec.Body = bytes.NewReader(body)
+
+	return body, nil
+}
+
+// rewindSpecBody returns a func that makes the body readable from the start
+// again. Sections of a file are reopened rather than read into memory; any
+// other body is buffered.
+func rewindSpecBody(spec *RequestSpec) (func(), error) {
+	if section, ok := spec.Body.(*io.SectionReader); ok {
+		start, err := section.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+
+		size := section.Size() - start
+
+		return func() { spec.Body = io.NewSectionReader(section, start, size) }, nil
+	}
+
+	body, err := readSpecBody(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		if body != nil {
+			spec.Body = bytes.NewReader(body)
+		}
+	}, nil
+}
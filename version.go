@@ -0,0 +1,98 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultVersionHeader = "API-Version"
+
+	deprecationHeader = "Deprecation"
+	sunsetHeader      = "Sunset"
+)
+
+type VersionStrategy int
+
+const (
+	// VersionHeader sends the version in APIVersion.Header.
+	VersionHeader VersionStrategy = iota
+	// VersionMediaType turns application/json in Accept and Content-Type,
+	// or a missing Accept, into application/vnd.<Vendor>.<Version>+json.
+	VersionMediaType
+	// VersionPathPrefix prepends "/<Version>" to relative request paths.
+	VersionPathPrefix
+)
+
+// APIVersion is sent with every request as configured by Strategy. Version
+// is used verbatim, such as "v2" or "2024-06-01". A version set on the
+// request itself wins.
+type APIVersion struct {
+	Version  string
+	Strategy VersionStrategy
+	// Header is "API-Version" by default.
+	Header string
+	Vendor string
+}
+
+// WithAPIVersion pins the API version of every request. Deprecation
+// warnings then carry the version.
+func WithAPIVersion(version APIVersion) Option {
+	return func(client *Client) error {
+		if version.Version == "" {
+			return errors.New("api version must not be empty")
+		}
+
+		if version.Strategy == VersionMediaType && version.Vendor == "" {
+			return errors.New("api version media type needs a vendor")
+		}
+
+		version.Header = fieldOrDefault(version.Header, defaultVersionHeader)
+		version.Version = strings.Trim(version.Version, "/")
+		client.apiVersion = &version
+
+		return nil
+	}
+}
+
+func (client *Client) versionedPath(path string) string {
+	if client.apiVersion == nil || client.apiVersion.Strategy != VersionPathPrefix {
+		return path
+	}
+
+	prefix := "/" + client.apiVersion.Version
+	if path == prefix || strings.HasPrefix(path, prefix+"/") {
+		return path
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return prefix + path
+}
+
+func (client *Client) applyAPIVersion(request *http.Request) {
+	version := client.apiVersion
+	if version == nil {
+		return
+	}
+
+	switch version.Strategy {
+	case VersionHeader:
+		if request.Header.Get(version.Header) == "" {
+			request.Header.Set(version.Header, version.Version)
+		}
+	case VersionMediaType:
+		mediaType := "application/vnd." + version.Vendor + "." + version.Version + "+json"
+
+		if accept := request.Header.Get("Accept"); accept == "" || accept == ContentTypeJson {
+			request.Header.Set("Accept", mediaType)
+		}
+
+		if request.Header.Get(ContentTypeHeader) == ContentTypeJson {
+			request.Header.Set(ContentTypeHeader, mediaType)
+		}
+	}
+}
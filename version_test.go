@@ -0,0 +1,79 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func newVersionedClient(t *testing.T, url string, version APIVersion, log zerolog.Logger) *Client {
+	t.Helper()
+	c, err := New(url, nil, &log, false, "ua", WithAPIVersion(version))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	return c
+}
+
+func TestWithAPIVersion_Strategies(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+
+	c := newVersionedClient(t, srv.URL, APIVersion{Version: "2024-06-01"}, zerolog.Nop())
+	if _, err := c.Send(ctx, RequestSpec{Method: http.MethodGet, Path: "/items"}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if got.Header.Get("API-Version") != "2024-06-01" {
+		t.Fatalf("header=%q", got.Header.Get("API-Version"))
+	}
+
+	c = newVersionedClient(t, srv.URL, APIVersion{Version: "v2", Strategy: VersionMediaType, Vendor: "acme"}, zerolog.Nop())
+	headers := MultiHeaders{}
+	headers.Set(ContentTypeHeader, ContentTypeJson)
+	spec := RequestSpec{Method: http.MethodPost, Path: "/items", Headers: headers, Body: strings.NewReader("{}")}
+	if _, err := c.Send(ctx, spec); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if got.Header.Get("Accept") != "application/vnd.acme.v2+json" ||
+		got.Header.Get(ContentTypeHeader) != "application/vnd.acme.v2+json" {
+		t.Fatalf("accept=%q content-type=%q", got.Header.Get("Accept"), got.Header.Get(ContentTypeHeader))
+	}
+
+	c = newVersionedClient(t, srv.URL, APIVersion{Version: "v2", Strategy: VersionPathPrefix}, zerolog.Nop())
+	for _, path := range []string{"/items", "/v2/items"} {
+		if _, err := c.Send(ctx, RequestSpec{Method: http.MethodGet, Path: path, Params: MultiParams{"a": {"1"}}}); err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+		if got.URL.Path != "/v2/items" || got.URL.RawQuery != "a=1" {
+			t.Fatalf("path %q sent as %q", path, got.URL.String())
+		}
+	}
+}
+
+func TestWithAPIVersion_LogsDeprecation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "@1735689600")
+		w.Header().Set("Sunset", "Wed, 31 Dec 2025 23:59:59 GMT")
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	c := newVersionedClient(t, srv.URL, APIVersion{Version: "v1"}, zerolog.New(&buf))
+	if _, err := c.Send(context.Background(), RequestSpec{Method: http.MethodGet, Path: "/"}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "server advertised api deprecation") || !strings.Contains(buf.String(), `"deprecated_at":"2025-01-01T00:00:00Z"`) {
+		t.Fatalf("log=%s", buf.String())
+	}
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type warmupConfig struct {
+	ctx         context.Context
+	connections int
+	interval    time.Duration
+}
+
+// WithConnectionWarmup opens the given number of idle connections to the base
+// host while the client is constructed and, when interval is positive, primes
+// them again on that schedule until ctx is done.
+func WithConnectionWarmup(ctx context.Context, connections int, interval time.Duration) Option {
+	return func(client *Client) error {
+		if connections < 1 {
+			return errors.New("warmup connections must be positive")
+		}
+
+		transport := client.mutableTransport()
+		if transport.MaxIdleConnsPerHost < connections {
+			transport.MaxIdleConnsPerHost = connections
+		}
+
+		client.warmup = &warmupConfig{ctx: ctx, connections: connections, interval: interval}
+
+		return nil
+	}
+}
+
+func (client *Client) WarmUp(ctx context.Context, connections int) error {
+	var wg sync.WaitGroup
+
+	errs := make([]error, connections)
+
+	for i := 0; i < connections; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			errs[i] = client.primeConnection(ctx)
+		}(i)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (client *Client) primeConnection(ctx context.Context) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodHead, client.currentBaseUrl(), nil)
+	if err != nil {
+		return err
+	}
+
+	client.fillRequestHeaders(request, nil)
+
+	response, err := client.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	return closeResponseBody(response)
+}
+
+func (client *Client) startWarmup() {
+	config := client.warmup
+
+	client.runWarmup(config)
+
+	if config.interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(config.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-config.ctx.Done():
+				return
+			case <-ticker.C:
+				client.runWarmup(config)
+			}
+		}
+	}()
+}
+
+func (client *Client) runWarmup(config *warmupConfig) {
+	if err := client.WarmUp(config.ctx, config.connections); err != nil {
+		client.logger.Warn().
+			Err(err).
+			Str(client.logField("url"), client.baseUrl).
+			Int("connections", config.connections).
+			Msg("failed to warm up connections")
+	}
+}
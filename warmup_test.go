@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithConnectionWarmup_OpensIdleConnections(t *testing.T) {
+	var mu sync.Mutex
+	var newConns, heads int
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			mu.Lock()
+			heads++
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+		}
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			newConns++
+			mu.Unlock()
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	log := zerolog.Nop()
+	c, err := New(srv.URL, nil, &log, false, "ua", WithConnectionWarmup(ctx, 3, 0))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	mu.Lock()
+	warmed := newConns
+	mu.Unlock()
+	if warmed != 3 || heads != 3 {
+		t.Fatalf("warmed connections=%d heads=%d", warmed, heads)
+	}
+
+	if _, _, err = c.SendGet("/x", nil, nil); err != nil {
+		t.Fatalf("SendGet error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if newConns != warmed {
+		t.Fatalf("request opened a new connection: %d", newConns)
+	}
+}
+
+func TestWithConnectionWarmup_RejectsNonPositive(t *testing.T) {
+	log := zerolog.Nop()
+	if _, err := New("http://localhost", nil, &log, false, "ua", WithConnectionWarmup(context.Background(), 0, 0)); err == nil {
+		t.Fatal("expected error for zero connections")
+	}
+}
@@ -0,0 +1,216 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultWebhookAttempts   = 5
+	defaultWebhookBackoff    = time.Second
+	defaultWebhookMaxBackoff = time.Minute
+	webhookIDBytes           = 16
+
+	webhookIDHeader        = "Webhook-Id"
+	webhookTimestampHeader = "Webhook-Timestamp"
+	webhookSignatureHeader = "Webhook-Signature"
+)
+
+// Webhook configures Deliver. Requests are signed following the Standard
+// Webhooks scheme: Webhook-Signature carries "v1,<base64 HMAC-SHA256>" of
+// "<id>.<unix timestamp>.<body>" keyed with Secret.
+type Webhook struct {
+	Secret      []byte
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	Headers     MultiHeaders
+}
+
+type WebhookEvent struct {
+	// ID stays the same across retries so receivers can deduplicate;
+	// a random one is generated when empty.
+	ID      string
+	Payload []byte
+}
+
+type DeliveryAttempt struct {
+	At         time.Time
+	StatusCode int
+	Err        error
+	Duration   time.Duration
+}
+
+type DeliveryReport struct {
+	EventID   string
+	Delivered bool
+	Attempts  []DeliveryAttempt
+	Response  *Response
+}
+
+// Deliver POSTs a signed webhook event to path, retrying transport errors,
+// 408, 429 and 5xx answers with exponential backoff, or after Retry-After
+// for 429 and 503. The report is returned
+// together with the last error when delivery did not succeed.
+func (client *Client) Deliver(ctx context.Context, path string, event WebhookEvent, hook Webhook) (*DeliveryReport, error) {
+	hook = hook.withDefaults()
+
+	if event.ID == "" {
+		id, err := newWebhookID()
+		if err != nil {
+			return nil, err
+		}
+
+		event.ID = id
+	}
+
+	report := &DeliveryReport{EventID: event.ID}
+	backoff := hook.Backoff
+
+	for attempt := 1; ; attempt++ {
+		started := time.Now()
+
+		response, err := client.Send(ctx, hook.request(path, event, started))
+
+		report.Response = response
+		report.Attempts = append(report.Attempts, DeliveryAttempt{
+			At:         started,
+			StatusCode: statusOf(response),
+			Err:        err,
+			Duration:   time.Since(started),
+		})
+
+		if err == nil {
+			report.Delivered = true
+			return report, nil
+		}
+
+		if attempt >= hook.MaxAttempts || ctx.Err() != nil || !retryableDelivery(response, err) {
+			return report, err
+		}
+
+		delay := backoff
+		if status := statusOf(response); status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable {
+			delay = retryAfter(response.Header, backoff)
+		}
+
+		client.logger.Warn().
+			Err(err).
+			Func(client.logURL(client.baseUrl+path)).
+			Str("event_id", event.ID).
+			Int("attempt", attempt).
+			Dur("backoff", delay).
+			Msg("retrying webhook delivery")
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return report, ctx.Err()
+		case <-timer.C:
+		}
+
+		if backoff *= 2; backoff > hook.MaxBackoff {
+			backoff = hook.MaxBackoff
+		}
+	}
+}
+
+func (hook Webhook) withDefaults() Webhook {
+	if hook.MaxAttempts < 1 {
+		hook.MaxAttempts = defaultWebhookAttempts
+	}
+
+	if hook.Backoff <= 0 {
+		hook.Backoff = defaultWebhookBackoff
+	}
+
+	if hook.MaxBackoff <= 0 {
+		hook.MaxBackoff = defaultWebhookMaxBackoff
+	}
+
+	return hook
+}
+
+func (hook Webhook) request(path string, event WebhookEvent, at time.Time) RequestSpec {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+
+	headers := MultiHeaders{}
+	headers.Set(ContentTypeHeader, ContentTypeJson)
+	headers.Set(webhookIDHeader, event.ID)
+	headers.Set(webhookTimestampHeader, timestamp)
+	headers.Set(webhookSignatureHeader, "v1,"+SignWebhook(hook.Secret, event.ID, timestamp, event.Payload))
+
+	return RequestSpec{
+		Method:  http.MethodPost,
+		Path:    path,
+		Headers: hook.Headers,
+		Body:    bytes.NewReader(event.Payload),
+		Options: []RequestOption{withHeaders(headers)},
+	}
+}
+
+// SignWebhook returns the base64 HMAC-SHA256 signature used by Deliver,
+// also useful for verifying deliveries in tests and receivers.
+func SignWebhook(secret []byte, id, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(id + "." + timestamp + "."))
+	mac.Write(payload)
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// retryableDelivery retries transport errors and 408, 429 and 5xx answers;
+// local failures such as a closed client, validation, hooks or signing would
+// fail the same way again.
+func retryableDelivery(response *Response, err error) bool {
+	if response == nil {
+		var (
+			urlErr      *url.Error
+			redirectErr *RedirectError
+			timeoutErr  *TimeoutError
+		)
+
+		return (errors.As(err, &urlErr) && !errors.As(err, &redirectErr)) || errors.As(err, &timeoutErr)
+	}
+
+	if !errors.Is(err, ErrRequestFailed) {
+		return false
+	}
+
+	switch {
+	case response.StatusCode == http.StatusRequestTimeout, response.StatusCode == http.StatusTooManyRequests:
+		return true
+	default:
+		return response.StatusCode >= http.StatusInternalServerError
+	}
+}
+
+func statusOf(response *Response) int {
+	if response == nil {
+		return 0
+	}
+
+	return response.StatusCode
+}
+
+func newWebhookID() (string, error) {
+	id := make([]byte, webhookIDBytes)
+
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return "evt_" + hex.EncodeToString(id), nil
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDeliver_SignsAndRetries(t *testing.T) {
+	secret := []byte("whsec")
+	var calls int32
+	var ids []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		id, ts := r.Header.Get("Webhook-Id"), r.Header.Get("Webhook-Timestamp")
+		ids = append(ids, id)
+		if r.Header.Get("Webhook-Signature") != "v1,"+SignWebhook(secret, id, ts, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	hook := Webhook{Secret: secret, Backoff: time.Millisecond}
+
+	report, err := c.Deliver(context.Background(), "/hook", WebhookEvent{Payload: []byte(`{"type":"ping"}`)}, hook)
+	if err != nil {
+		t.Fatalf("Deliver error: %v", err)
+	}
+	if !report.Delivered || len(report.Attempts) != 3 || report.Attempts[0].StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("report=%+v", report)
+	}
+	if ids[0] == "" || ids[0] != ids[2] || ids[0] != report.EventID {
+		t.Fatalf("event id must be stable across retries: %v", ids)
+	}
+}
+
+func TestDeliver_StopsOnClientError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	report, err := c.Deliver(context.Background(), "/hook", WebhookEvent{ID: "evt_1"}, Webhook{Backoff: time.Millisecond})
+	if !errors.Is(err, ErrRequestFailed) || report.Delivered || len(report.Attempts) != 1 {
+		t.Fatalf("err=%v report=%+v", err, report)
+	}
+}
+
+func TestDeliver_StopsOnLocalErrors(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	denied := errors.New("denied")
+	c.OnRequest(func(*http.Request) error { return denied })
+
+	report, err := c.Deliver(context.Background(), "/hook", WebhookEvent{ID: "evt_1"}, Webhook{Backoff: time.Millisecond})
+	if !errors.Is(err, denied) || len(report.Attempts) != 1 || atomic.LoadInt32(&calls) != 0 {
+		t.Fatalf("err=%v report=%+v calls=%d", err, report, calls)
+	}
+}
+
+func TestDeliver_HonorsRetryAfter(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set(retryAfterHeader, "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	report, err := c.Deliver(ctx, "/hook", WebhookEvent{ID: "evt_1"}, Webhook{Backoff: time.Hour})
+	if err != nil || !report.Delivered || len(report.Attempts) != 2 {
+		t.Fatalf("err=%v report=%+v", err, report)
+	}
+}